@@ -0,0 +1,92 @@
+/*
+Copyright 2021 Syntasso.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	"github.com/syntasso/kratix/lib/hash"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ApproveAnnotation approves the current workloads of a WorkPlacement
+	// waiting for approval. The WorkPlacement webhook replaces it with
+	// ApprovedRevisionAnnotation and ApprovedByAnnotation.
+	ApproveAnnotation = KratixPrefix + "approve"
+	// ApprovedRevisionAnnotation is the revision of the workloads approved
+	// with the ApproveAnnotation
+	ApprovedRevisionAnnotation = KratixPrefix + "approved-revision"
+	// ApprovedByAnnotation is who approved the workloads with the
+	// ApproveAnnotation
+	ApprovedByAnnotation = KratixPrefix + "approved-by"
+)
+
+// ApprovalSpec defines the desired state of Approval
+type ApprovalSpec struct {
+	// The name of the WorkPlacement, in the namespace of the Approval, whose
+	// workloads are approved
+	WorkPlacement string `json:"workPlacement"`
+
+	// The revision of the workloads approved, as shown on the WorkPlacement
+	// status. Defaults to the current revision.
+	// +kubebuilder:validation:Optional
+	Revision string `json:"revision,omitempty"`
+
+	// Who approved the workloads. Set from the request creating the Approval;
+	// any value given is overwritten.
+	// +kubebuilder:validation:Optional
+	ApprovedBy string `json:"approvedBy,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:categories=kratix
+//+kubebuilder:printcolumn:name="WorkPlacement",type=string,JSONPath=`.spec.workPlacement`
+//+kubebuilder:printcolumn:name="Revision",type=string,JSONPath=`.spec.revision`
+//+kubebuilder:printcolumn:name="Approved By",type=string,JSONPath=`.spec.approvedBy`
+
+// Approval approves the workloads of a WorkPlacement targeting a Destination
+// that requires approval before they are written
+type Approval struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ApprovalSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ApprovalList contains a list of Approval
+type ApprovalList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Approval `json:"items"`
+}
+
+// Returns the revision of the workloads, used to tell which workloads an
+// approval is for
+func WorkloadsRevision(workloads []Workload) (string, error) {
+	workloadsJSON, err := json.Marshal(workloads)
+	if err != nil {
+		return "", err
+	}
+	return hash.ComputeHash(string(workloadsJSON)), nil
+}
+
+func init() {
+	SchemeBuilder.Register(&Approval{}, &ApprovalList{})
+}
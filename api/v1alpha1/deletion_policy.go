@@ -0,0 +1,44 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeletionPolicy is what happens to the State Store content of a resource
+// when its resource request is deleted
+type DeletionPolicy string
+
+const (
+	// The workloads written for the resource are deleted from the State
+	// Stores, and from the Destinations with them
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// The Works and WorkPlacements of the resource are deleted, but the
+	// workloads written for it are left in the State Stores, with a record of
+	// what was orphaned
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+
+	// DeletionPolicyAnnotation overrides the Promise deletionPolicy when set
+	// on a resource request. Kratix also sets it on the Works and
+	// WorkPlacements of a resource request being deleted with the Orphan
+	// policy.
+	DeletionPolicyAnnotation = KratixPrefix + "deletion-policy"
+)
+
+// Returns the deletion policy of the resource request: the one in its
+// DeletionPolicyAnnotation if valid, the Promise's otherwise
+func (p *Promise) GetDeletionPolicy(resourceRequest metav1.Object) DeletionPolicy {
+	switch policy := DeletionPolicy(resourceRequest.GetAnnotations()[DeletionPolicyAnnotation]); policy {
+	case DeletionPolicyDelete, DeletionPolicyOrphan:
+		return policy
+	}
+	if p.Spec.DeletionPolicy == DeletionPolicyOrphan {
+		return DeletionPolicyOrphan
+	}
+	return DeletionPolicyDelete
+}
+
+// Returns true if the object is marked to be orphaned with the
+// DeletionPolicyAnnotation
+func Orphaned(obj metav1.Object) bool {
+	return DeletionPolicy(obj.GetAnnotations()[DeletionPolicyAnnotation]) == DeletionPolicyOrphan
+}
@@ -17,8 +17,12 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"slices"
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 type StateStoreCoreFields struct {
@@ -60,6 +64,64 @@ type DestinationSpec struct {
 	// +kubebuilder:default:={mode: "nestedByMetadata"}
 	Filepath Filepath `json:"filepath,omitempty"`
 
+	// Capacity limits the resources that can be scheduled to this destination.
+	// Once the destination is at capacity, new resources are scheduled to other
+	// matching destinations, or remain unscheduled until capacity frees up.
+	// Promise dependencies are not limited by capacity.
+	// +kubebuilder:validation:Optional
+	Capacity *DestinationCapacity `json:"capacity,omitempty"`
+
+	// Taints repel works from the destination, unless they tolerate the taint.
+	// Tolerations are set on the Promise's destinationSelectors, or in the
+	// workflow's destination-selectors.yaml.
+	// +kubebuilder:validation:Optional
+	Taints []DestinationTaint `json:"taints,omitempty"`
+
+	// If Cordoned is true, no new works are scheduled to the destination. Works
+	// already scheduled to it are not affected.
+	// +kubebuilder:validation:Optional
+	Cordoned bool `json:"cordoned,omitempty"`
+
+	// If Drain is true, the destination is cordoned and the resources scheduled
+	// to it are moved to other matching destinations, one at a time. Each
+	// resource is only removed from the destination once it has been written
+	// to its new destination. Progress is reported in status.drain, and the
+	// destination can be safely deleted once the phase is Drained. Promise
+	// dependencies are not moved.
+	// +kubebuilder:validation:Optional
+	Drain bool `json:"drain,omitempty"`
+
+	// Restricts the resource requests that can be scheduled to the
+	// destination to those in the allowed namespaces, so the destination can be
+	// dedicated to tenants. When unset, resource requests from any namespace
+	// can be scheduled to it. Promise dependencies are not restricted.
+	// +kubebuilder:validation:Optional
+	NamespaceAccess *DestinationNamespaceAccess `json:"namespaceAccess,omitempty"`
+
+	// Periodically checks that the files in the State Store still match the
+	// workloads written to the destination, and reports any difference with
+	// the Drifted condition on the WorkPlacements. When unset, files changed
+	// or deleted outside of Kratix are only noticed on the next write.
+	// +kubebuilder:validation:Optional
+	DriftDetection *DriftDetection `json:"driftDetection,omitempty"`
+
+	// If true, changes to the workloads of WorkPlacements targeting the
+	// destination are only written once approved, with the
+	// kratix.io/approve annotation on the WorkPlacement or an Approval.
+	// Until then the WorkPlacements are pending approval, and show a summary
+	// of the changes on their status. Deleting a WorkPlacement does not
+	// require approval.
+	// +kubebuilder:validation:Optional
+	ApprovalRequired bool `json:"approvalRequired,omitempty"`
+
+	// Periodically reads the sync and health results an agent or GitOps tool
+	// on the destination writes back to .kratix/status/ in the State Store,
+	// and reports them with the Synced and Healthy conditions on the
+	// WorkPlacements. When unset, Kratix only reports whether it wrote the
+	// workloads.
+	// +kubebuilder:validation:Optional
+	HealthFeedback *HealthFeedback `json:"healthFeedback,omitempty"`
+
 	// cleanup can be set to either:
 	// - none (default): no cleanup after removing the destination
 	// - all: workplacements and statestore contents will be removed after removing the destination
@@ -73,18 +135,196 @@ const (
 	// kubebuilder comment for setting the default and Enum values.
 	FilepathModeNone             = "none"
 	FilepathModeNestedByMetadata = "nestedByMetadata"
+	FilepathModeTemplate         = "template"
 	DestinationCleanupAll        = "all"
 	DestinationCleanupNone       = "none"
+
+	DefaultDriftDetectionInterval = 10 * time.Minute
+	DefaultHealthFeedbackInterval = time.Minute
 )
 
+// A namespace is allowed if it is listed in namespaces or its labels match
+// namespaceSelector. If both are empty, no namespace is allowed.
+type DestinationNamespaceAccess struct {
+	// The names of the allowed namespaces
+	// +kubebuilder:validation:Optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Namespaces with labels matching the selector are allowed
+	// +kubebuilder:validation:Optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+type DriftDetection struct {
+	// How often the files of each WorkPlacement are checked
+	// +kubebuilder:default:="10m"
+	// +kubebuilder:validation:Optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+	// If Remediate is true, files that drifted are written again with the
+	// expected content. Otherwise, drift is only reported.
+	// +kubebuilder:validation:Optional
+	Remediate bool `json:"remediate,omitempty"`
+}
+
+type HealthFeedback struct {
+	// How often the results are read. They are read for all of the
+	// Destination's WorkPlacements at once.
+	// +kubebuilder:default:="1m"
+	// +kubebuilder:validation:Optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!has(self.mode) || self.mode != 'template' || (has(self.template) && self.template != '')",message="filepath.template is required when filepath.mode is template"
 type Filepath struct {
-	// +kubebuilder:validation:Enum:={nestedByMetadata,none}
+	// +kubebuilder:validation:Enum:={nestedByMetadata,none,template}
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="filepath.mode is immutable"
 	// filepath.mode can be set to either:
 	// - nestedByMetadata (default): files from the pipeline will be placed in a nested directory structure
 	// - none: file from the pipeline will be placed in a flat directory structure
+	// - template: files from the pipeline will be placed at the path rendered from filepath.template
 	// filepath.mode is immutable
 	Mode string `json:"mode,omitempty"`
+
+	// filepath.template is a Go template used to build the path of each file
+	// written to the destination when filepath.mode is template. It has access to:
+	// - .WorkPlacement: Name, Namespace, PromiseName, ResourceName, PipelineName and WorkloadGroupID
+	// - .ResourceRequest.Labels: the labels on the resource request. .ResourceRequest is not set for
+	//   Promise dependencies, so templates used for them must guard it with {{ with .ResourceRequest }}
+	// - .Workload.Filepath: the path of the file as written by the pipeline
+	// Paths must not collide with the paths of other WorkPlacements on the destination.
+	// For example: {{ with .ResourceRequest }}{{ .Labels.team }}/{{ end }}{{ .WorkPlacement.PromiseName }}/{{ .Workload.Filepath }}
+	// +kubebuilder:validation:Optional
+	Template string `json:"template,omitempty"`
+}
+
+type DestinationCapacity struct {
+	// Maximum number of resource WorkPlacements that can be scheduled to the
+	// destination.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	MaxWorkPlacements *int `json:"maxWorkPlacements,omitempty"`
+
+	// Named capacity dimensions available on the destination, for example
+	// `databases: 10`. Each resource consumes the amount set in its Promise's
+	// spec.schedulingPolicy.capacityRequests. Dimensions not listed here are
+	// unlimited.
+	// +kubebuilder:validation:Optional
+	Dimensions map[string]int `json:"dimensions,omitempty"`
+}
+
+type TaintEffect string
+
+const (
+	// if modifying these dont forget to edit below where they are written as a
+	// kubebuilder comment for setting the Enum values.
+	TaintEffectNoSchedule       TaintEffect = "NoSchedule"
+	TaintEffectPreferNoSchedule TaintEffect = "PreferNoSchedule"
+	TaintEffectNoExecute        TaintEffect = "NoExecute"
+
+	TolerationOpEqual  TolerationOperator = "Equal"
+	TolerationOpExists TolerationOperator = "Exists"
+)
+
+type DestinationTaint struct {
+	// The taint key to be applied to the destination.
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+	// The taint value corresponding to the taint key.
+	// +kubebuilder:validation:Optional
+	Value string `json:"value,omitempty"`
+	// effect can be set to either:
+	// - NoSchedule: works that do not tolerate the taint are not scheduled to the destination
+	// - PreferNoSchedule: resources that do not tolerate the taint are only scheduled to the destination if no other destination is available
+	// - NoExecute: as NoSchedule, and works already scheduled to the destination that do not tolerate the taint are rescheduled
+	// +kubebuilder:validation:Enum:={NoSchedule,PreferNoSchedule,NoExecute}
+	Effect TaintEffect `json:"effect"`
+}
+
+type TolerationOperator string
+
+// +kubebuilder:validation:XValidation:rule="!has(self.operator) || self.operator != 'Exists' || !has(self.value) || self.value == ''",message="value must be empty when operator is Exists"
+type Toleration struct {
+	// The taint key that the toleration applies to. Empty means match all taint
+	// keys, in which case the operator must be Exists.
+	// +kubebuilder:validation:Optional
+	Key string `json:"key,omitempty"`
+	// operator can be set to either:
+	// - Equal (default): the toleration matches taints with the same key and value
+	// - Exists: the toleration matches taints with the same key, whatever their value
+	// +kubebuilder:validation:Enum:={Equal,Exists}
+	// +kubebuilder:validation:Optional
+	Operator TolerationOperator `json:"operator,omitempty"`
+	// The taint value the toleration matches to when the operator is Equal.
+	// +kubebuilder:validation:Optional
+	Value string `json:"value,omitempty"`
+	// The taint effect to match. Empty means match all taint effects.
+	// +kubebuilder:validation:Enum:={NoSchedule,PreferNoSchedule,NoExecute}
+	// +kubebuilder:validation:Optional
+	Effect TaintEffect `json:"effect,omitempty"`
+}
+
+// ToleratesTaint returns true if the toleration matches the taint
+func (t Toleration) ToleratesTaint(taint DestinationTaint) bool {
+	if t.Effect != "" && t.Effect != taint.Effect {
+		return false
+	}
+
+	if t.Key != "" && t.Key != taint.Key {
+		return false
+	}
+
+	switch t.Operator {
+	case TolerationOpExists:
+		return true
+	case TolerationOpEqual, "":
+		return t.Key != "" && t.Value == taint.Value
+	}
+	return false
+}
+
+// Returns the taints of the destination with any of the given effects that
+// are not tolerated by any of the tolerations
+func (d *Destination) UntoleratedTaints(tolerations []Toleration, effects ...TaintEffect) []DestinationTaint {
+	var untolerated []DestinationTaint
+	for _, taint := range d.Spec.Taints {
+		if !slices.Contains(effects, taint.Effect) {
+			continue
+		}
+
+		tolerated := slices.ContainsFunc(tolerations, func(toleration Toleration) bool {
+			return toleration.ToleratesTaint(taint)
+		})
+		if !tolerated {
+			untolerated = append(untolerated, taint)
+		}
+	}
+	return untolerated
+}
+
+// IsCordoned returns true when no new works can be scheduled to the
+// destination
+func (d *Destination) IsCordoned() bool {
+	return d.Spec.Cordoned || d.Spec.Drain
+}
+
+// AllowsNamespace returns true when resource requests in the namespace with
+// the given labels can be scheduled to the destination
+func (d *Destination) AllowsNamespace(namespace string, namespaceLabels map[string]string) bool {
+	access := d.Spec.NamespaceAccess
+	if access == nil {
+		return true
+	}
+
+	if slices.Contains(access.Namespaces, namespace) {
+		return true
+	}
+
+	if access.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(access.NamespaceSelector)
+		if err == nil && selector.Matches(labels.Set(namespaceLabels)) {
+			return true
+		}
+	}
+	return false
 }
 
 // it gets defaulted by the K8s API, but for unit testing it wont be defaulted
@@ -103,10 +343,64 @@ func (d *Destination) GetCleanup() string {
 	return d.Spec.Cleanup
 }
 
+// Returns the drift detection settings of the destination, or nil if drift
+// detection is disabled
+func (d *Destination) GetDriftDetection() *DriftDetection {
+	if d.Spec.DriftDetection == nil {
+		return nil
+	}
+	driftDetection := d.Spec.DriftDetection.DeepCopy()
+	if driftDetection.Interval.Duration <= 0 {
+		driftDetection.Interval.Duration = DefaultDriftDetectionInterval
+	}
+	return driftDetection
+}
+
+func (d *Destination) GetHealthFeedback() *HealthFeedback {
+	if d.Spec.HealthFeedback == nil {
+		return nil
+	}
+	healthFeedback := d.Spec.HealthFeedback.DeepCopy()
+	if healthFeedback.Interval.Duration <= 0 {
+		healthFeedback.Interval.Duration = DefaultHealthFeedbackInterval
+	}
+	return healthFeedback
+}
+
 // DestinationStatus defines the observed state of Destination
 type DestinationStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of destination
 	// Important: Run "make" to regenerate code after modifying this file
+
+	// Progress of draining the destination, set while spec.drain is true
+	// +kubebuilder:validation:Optional
+	Drain *DrainStatus `json:"drain,omitempty"`
+
+	// Conditions of the destination. FilepathTemplateValid reports whether
+	// filepath.template can be used to write WorkPlacements, when
+	// filepath.mode is template. CanaryWritten reports whether the canary
+	// files were written to the State Store for the current generation
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+const (
+	DrainPhaseDraining = "Draining"
+	DrainPhaseDrained  = "Drained"
+)
+
+type DrainStatus struct {
+	// phase is either:
+	// - Draining: resources are still being moved to other destinations
+	// - Drained: no resources remain on the destination
+	Phase string `json:"phase"`
+	// Number of resource WorkPlacements remaining on the destination
+	Remaining int `json:"remaining"`
+	// The WorkPlacement currently being moved to another destination
+	// +kubebuilder:validation:Optional
+	Migrating string `json:"migrating,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,65 @@
+package v1alpha1_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	platformv1alpha1 "github.com/syntasso/kratix/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Destination", func() {
+	Describe("Taints", func() {
+		taint := platformv1alpha1.DestinationTaint{Key: "gpu", Value: "true", Effect: platformv1alpha1.TaintEffectNoSchedule}
+
+		DescribeTable("ToleratesTaint", func(toleration platformv1alpha1.Toleration, tolerates bool) {
+			Expect(toleration.ToleratesTaint(taint)).To(Equal(tolerates))
+		},
+			Entry("same key and value", platformv1alpha1.Toleration{Key: "gpu", Value: "true"}, true),
+			Entry("same key, value and effect", platformv1alpha1.Toleration{Key: "gpu", Operator: platformv1alpha1.TolerationOpEqual, Value: "true", Effect: platformv1alpha1.TaintEffectNoSchedule}, true),
+			Entry("different value", platformv1alpha1.Toleration{Key: "gpu", Value: "false"}, false),
+			Entry("different effect", platformv1alpha1.Toleration{Key: "gpu", Value: "true", Effect: platformv1alpha1.TaintEffectNoExecute}, false),
+			Entry("different key", platformv1alpha1.Toleration{Key: "pci", Value: "true"}, false),
+			Entry("Exists on the same key", platformv1alpha1.Toleration{Key: "gpu", Operator: platformv1alpha1.TolerationOpExists}, true),
+			Entry("Exists with no key", platformv1alpha1.Toleration{Operator: platformv1alpha1.TolerationOpExists}, true),
+			Entry("Equal with no key", platformv1alpha1.Toleration{Value: "true"}, false),
+		)
+
+		It("returns the untolerated taints with the given effects", func() {
+			destination := platformv1alpha1.Destination{
+				Spec: platformv1alpha1.DestinationSpec{
+					Taints: []platformv1alpha1.DestinationTaint{
+						taint,
+						{Key: "regulated", Effect: platformv1alpha1.TaintEffectNoExecute},
+						{Key: "spot", Effect: platformv1alpha1.TaintEffectPreferNoSchedule},
+					},
+				},
+			}
+			tolerations := []platformv1alpha1.Toleration{{Key: "gpu", Value: "true"}}
+
+			Expect(destination.UntoleratedTaints(tolerations, platformv1alpha1.TaintEffectNoSchedule, platformv1alpha1.TaintEffectNoExecute)).To(ConsistOf(
+				platformv1alpha1.DestinationTaint{Key: "regulated", Effect: platformv1alpha1.TaintEffectNoExecute},
+			))
+			Expect(destination.UntoleratedTaints(nil, platformv1alpha1.TaintEffectPreferNoSchedule)).To(ConsistOf(
+				platformv1alpha1.DestinationTaint{Key: "spot", Effect: platformv1alpha1.TaintEffectPreferNoSchedule},
+			))
+		})
+	})
+
+	DescribeTable("AllowsNamespace", func(access *platformv1alpha1.DestinationNamespaceAccess, allows bool) {
+		destination := platformv1alpha1.Destination{
+			Spec: platformv1alpha1.DestinationSpec{NamespaceAccess: access},
+		}
+		Expect(destination.AllowsNamespace("team-a", map[string]string{"tenant": "a"})).To(Equal(allows))
+	},
+		Entry("no namespace access", nil, true),
+		Entry("listed namespace", &platformv1alpha1.DestinationNamespaceAccess{Namespaces: []string{"team-a"}}, true),
+		Entry("unlisted namespace", &platformv1alpha1.DestinationNamespaceAccess{Namespaces: []string{"team-b"}}, false),
+		Entry("matching namespace labels", &platformv1alpha1.DestinationNamespaceAccess{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "a"}},
+		}, true),
+		Entry("non-matching namespace labels", &platformv1alpha1.DestinationNamespaceAccess{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "b"}},
+		}, false),
+		Entry("empty namespace access", &platformv1alpha1.DestinationNamespaceAccess{}, false),
+	)
+})
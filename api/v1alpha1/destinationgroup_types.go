@@ -0,0 +1,125 @@
+/*
+Copyright 2021 Syntasso.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DestinationGroupLabelPrefix is the prefix of the label set on the members of
+// a DestinationGroup, followed by the group name. destinationSelectors select
+// the members of a group with this label.
+const DestinationGroupLabelPrefix = "destinationgroup." + KratixPrefix
+
+// DestinationGroupLabel returns the label set on the members of the named
+// DestinationGroup
+func DestinationGroupLabel(groupName string) string {
+	return DestinationGroupLabelPrefix + groupName
+}
+
+// DestinationGroupSpec defines the desired state of DestinationGroup
+type DestinationGroupSpec struct {
+	// Selects the member Destinations by their labels. Labels stamped by the
+	// group itself are not used to select its members.
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// Labels stamped onto every member. Labels the member already sets
+	// are not overwritten.
+	// +kubebuilder:validation:Optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// The stateStoreRef stamped onto members that don't set their own.
+	// +kubebuilder:validation:Optional
+	StateStoreRef *StateStoreReference `json:"stateStoreRef,omitempty"`
+
+	// The scheduling policy stamped onto every member.
+	// +kubebuilder:validation:Optional
+	Scheduling *DestinationGroupScheduling `json:"scheduling,omitempty"`
+}
+
+type DestinationGroupScheduling struct {
+	// Taints added to every member, unless the member already has a taint
+	// with the same key and effect.
+	// +kubebuilder:validation:Optional
+	Taints []DestinationTaint `json:"taints,omitempty"`
+
+	// If true, strictMatchLabels is set on every member.
+	// +kubebuilder:validation:Optional
+	StrictMatchLabels bool `json:"strictMatchLabels,omitempty"`
+}
+
+const (
+	DestinationGroupMemberReady    = "Ready"
+	DestinationGroupMemberNotReady = "NotReady"
+	DestinationGroupMemberCordoned = "Cordoned"
+	DestinationGroupMemberDeleting = "Deleting"
+)
+
+// DestinationGroupStatus defines the observed state of DestinationGroup
+type DestinationGroupStatus struct {
+	// The member Destinations, sorted by name
+	// +optional
+	Members []DestinationGroupMember `json:"members,omitempty"`
+	// The number of member Destinations
+	// +optional
+	MemberCount int `json:"memberCount"`
+	// The number of member Destinations that are Ready
+	// +optional
+	ReadyCount int `json:"readyCount"`
+}
+
+type DestinationGroupMember struct {
+	Name string `json:"name"`
+	// health can be one of:
+	// - Ready: works can be scheduled to and written to the destination
+	// - NotReady: works can't be written to the destination
+	// - Cordoned: the destination is cordoned or draining
+	// - Deleting: the destination is being deleted
+	Health string `json:"health"`
+	// Why the destination is not ready
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,path=destinationgroups,categories=kratix
+// +kubebuilder:printcolumn:name="Members",type=integer,JSONPath=`.status.memberCount`
+// +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.readyCount`
+// +kubebuilder:validation:XValidation:rule="size(self.metadata.name) <= 63",message="name must be no more than 63 characters, as it is used in a label key"
+
+// DestinationGroup is the Schema for the destinationgroups API
+type DestinationGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DestinationGroupSpec   `json:"spec,omitempty"`
+	Status DestinationGroupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DestinationGroupList contains a list of DestinationGroup
+type DestinationGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DestinationGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DestinationGroup{}, &DestinationGroupList{})
+}
@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -183,7 +184,7 @@ func (p *PipelineFactory) readerContainer() corev1.Container {
 	}
 }
 
-func (p *PipelineFactory) workCreatorContainer() corev1.Container {
+func (p *PipelineFactory) workCreatorContainer() (corev1.Container, error) {
 	workCreatorCommand := "work-creator"
 
 	args := []string{
@@ -194,8 +195,28 @@ func (p *PipelineFactory) workCreatorContainer() corev1.Container {
 		"-workflow-type", string(p.WorkflowType),
 	}
 
+	var env []corev1.EnvVar
 	if p.ResourceWorkflow {
 		args = append(args, "-resource-name", p.ResourceRequest.GetName())
+
+		resourceRequestScheduling, err := p.Promise.GetResourceRequestScheduling(p.ResourceRequest)
+		if err != nil {
+			return corev1.Container{}, err
+		}
+		if resourceRequestScheduling != nil {
+			schedulingJSON, err := json.Marshal(resourceRequestScheduling)
+			if err != nil {
+				return corev1.Container{}, err
+			}
+			env = append(env, corev1.EnvVar{Name: ResourceRequestDestinationSelectorsEnvVar, Value: string(schedulingJSON)})
+		}
+	}
+
+	if WorkloadContentCodec != "" {
+		env = append(env, corev1.EnvVar{Name: WorkloadContentCodecEnvVar, Value: WorkloadContentCodec})
+	}
+	if DeduplicateWorkloadContent {
+		env = append(env, corev1.EnvVar{Name: DeduplicateWorkloadContentEnvVar, Value: "true"})
 	}
 
 	workCreatorCommand = fmt.Sprintf("%s %s", workCreatorCommand, strings.Join(args, " "))
@@ -204,13 +225,14 @@ func (p *PipelineFactory) workCreatorContainer() corev1.Container {
 		Name:    "work-writer",
 		Image:   os.Getenv("WC_IMG"),
 		Command: []string{"sh", "-c", workCreatorCommand},
+		Env:     env,
 		VolumeMounts: []corev1.VolumeMount{
 			{MountPath: "/work-creator-files/input", Name: "shared-output"},
 			{MountPath: "/work-creator-files/metadata", Name: "shared-metadata"},
 			{MountPath: "/work-creator-files/kratix-system", Name: "promise-scheduling"}, // this volumemount is a configmap
 		},
 		SecurityContext: kratixSecurityContext,
-	}
+	}, nil
 }
 
 func (p *PipelineFactory) pipelineContainers() ([]corev1.Container, []corev1.Volume) {
@@ -262,7 +284,10 @@ func (p *PipelineFactory) pipelineJob(schedulingConfigMap *corev1.ConfigMap, ser
 
 	readerContainer := p.readerContainer()
 	pipelineContainers, pipelineVolumes := p.pipelineContainers()
-	workCreatorContainer := p.workCreatorContainer()
+	workCreatorContainer, err := p.workCreatorContainer()
+	if err != nil {
+		return nil, err
+	}
 	statusWriterContainer := p.statusWriterContainer(obj, env)
 
 	volumes := append(p.defaultVolumes(schedulingConfigMap), pipelineVolumes...)
@@ -379,6 +404,17 @@ func (p *PipelineFactory) getObjAndHash() (*unstructured.Unstructured, string, e
 	return p.ResourceRequest, hash.ComputeHash(fmt.Sprintf("%s-%s", promiseHash, resourceHash)), nil
 }
 
+// Allows the work-creator to store the content of workloads too large to be
+// held in the Work in ConfigMaps, to check a ConfigMap it did not create holds
+// the same content, and to claim that content until its Work is owned. Every container of the pipeline runs under the
+// service account, so it is not allowed to change or delete ConfigMaps; the
+// Work controller owns and cleans up the stored ConfigMaps.
+var workloadPayloadPolicyRule = rbacv1.PolicyRule{
+	APIGroups: []string{""},
+	Resources: []string{"configmaps"},
+	Verbs:     []string{"get", "create"},
+}
+
 func (p *PipelineFactory) role() ([]rbacv1.Role, error) {
 	var roles []rbacv1.Role
 	if p.ResourceWorkflow {
@@ -408,8 +444,24 @@ func (p *PipelineFactory) role() ([]rbacv1.Role, error) {
 					Resources: []string{"works"},
 					Verbs:     []string{"*"},
 				},
+				workloadPayloadPolicyRule,
 			},
 		})
+	} else {
+		// the Works of Promise workflows are always in the system namespace, so
+		// their workload content is only stored there
+		roles = append(roles, rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      p.ID,
+				Labels:    promiseNameLabel(p.Promise.GetName()),
+				Namespace: SystemNamespace,
+			},
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: rbacv1.SchemeGroupVersion.String(),
+				Kind:       "Role",
+			},
+			Rules: []rbacv1.PolicyRule{workloadPayloadPolicyRule},
+		})
 	}
 
 	if p.Pipeline.hasUserPermissions() {
@@ -446,7 +498,7 @@ func (p *PipelineFactory) roleBindings(roles []rbacv1.Role, clusterRoles []rbacv
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      role.GetName(),
 				Labels:    role.Labels,
-				Namespace: p.Namespace,
+				Namespace: role.GetNamespace(),
 			},
 			TypeMeta: metav1.TypeMeta{
 				APIVersion: rbacv1.SchemeGroupVersion.String(),
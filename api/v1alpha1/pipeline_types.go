@@ -37,6 +37,11 @@ const (
 	kratixPromiseEnvVar      = "KRATIX_PROMISE_NAME"
 	kratixPipelineNameEnvVar = "KRATIX_PIPELINE_NAME"
 
+	// WorkloadContentCodecEnvVar and DeduplicateWorkloadContentEnvVar pass
+	// WorkloadContentCodec and DeduplicateWorkloadContent to the work-creator
+	WorkloadContentCodecEnvVar       = "KRATIX_WORKLOAD_CONTENT_CODEC"
+	DeduplicateWorkloadContentEnvVar = "KRATIX_DEDUPLICATE_WORKLOAD_CONTENT"
+
 	// This is used to identify the * namespace case in user permissions. Kubernetes does
 	// not allow * as a label value, so we use this value instead.
 	// It contains underscores, which makes it an invalid namespace, so it won't conflict
@@ -58,6 +63,13 @@ var (
 	}
 
 	DefaultUserProvidedContainersSecurityContext *corev1.SecurityContext
+
+	// The codec the work-creator compresses workloads with; the
+	// work-creator's default if empty
+	WorkloadContentCodec string
+	// Whether the work-creator stores identical workload content once per
+	// namespace, referenced by its digest
+	DeduplicateWorkloadContent bool
 )
 
 // PipelineSpec defines the desired state of Pipeline
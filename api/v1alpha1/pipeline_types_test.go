@@ -190,12 +190,13 @@ var _ = Describe("Pipeline", func() {
 						job := resources.Job
 
 						objs := resources.GetObjects()
-						Expect(objs).To(HaveLen(4))
+						Expect(objs).To(HaveLen(6))
 						Expect(objs).To(ContainElements(
-							serviceAccount, &clusterRoles[0], &clusterRoleBindings[0], configMap,
+							serviceAccount, &roles[0], &bindings[0], &clusterRoles[0], &clusterRoleBindings[0], configMap,
 						))
-						Expect(roles).To(HaveLen(0))
-						Expect(bindings).To(HaveLen(0))
+						Expect(roles).To(HaveLen(1))
+						Expect(bindings).To(HaveLen(1))
+						matchPromisePayloadRoleAndBinding(roles, bindings, factory, serviceAccount)
 
 						Expect(serviceAccount.GetName()).To(Equal("factoryID"))
 						Expect(resources.Job.Name).To(HavePrefix("kratix-%s-%s", promise.GetName(), pipeline.GetName()))
@@ -227,12 +228,13 @@ var _ = Describe("Pipeline", func() {
 						job := resources.Job
 
 						objs := resources.GetObjects()
-						Expect(objs).To(HaveLen(3))
+						Expect(objs).To(HaveLen(5))
 						Expect(objs).To(ContainElements(
-							serviceAccount, &clusterRoles[0], &clusterRoleBindings[0],
+							serviceAccount, &roles[0], &bindings[0], &clusterRoles[0], &clusterRoleBindings[0],
 						))
-						Expect(roles).To(HaveLen(0))
-						Expect(bindings).To(HaveLen(0))
+						Expect(roles).To(HaveLen(1))
+						Expect(bindings).To(HaveLen(1))
+						matchPromisePayloadRoleAndBinding(roles, bindings, factory, serviceAccount)
 
 						Expect(resources.Job.Name).To(HavePrefix("kratix-%s-%s", promise.GetName(), pipeline.GetName()))
 						job.Name = resources.Job.Name
@@ -679,6 +681,62 @@ var _ = Describe("Pipeline", func() {
 						))
 					})
 				})
+
+				When("a workload codec and deduplication are configured", func() {
+					AfterEach(func() {
+						v1alpha1.WorkloadContentCodec = ""
+						v1alpha1.DeduplicateWorkloadContent = false
+					})
+
+					It("passes them to the work creator", func() {
+						v1alpha1.WorkloadContentCodec = "zstd"
+						v1alpha1.DeduplicateWorkloadContent = true
+						var err error
+						resources, err = factory.Resources(nil)
+						Expect(err).ToNot(HaveOccurred())
+
+						containers := resources.Job.Spec.Template.Spec.InitContainers
+						Expect(containers[len(containers)-1].Env).To(ConsistOf(
+							corev1.EnvVar{Name: "KRATIX_WORKLOAD_CONTENT_CODEC", Value: "zstd"},
+							corev1.EnvVar{Name: "KRATIX_DEDUPLICATE_WORKLOAD_CONTENT", Value: "true"},
+						))
+					})
+				})
+			})
+
+			Describe("WorkCreatorContainer resource request destination selectors", func() {
+				BeforeEach(func() {
+					factory.ResourceWorkflow = true
+					resourceRequest.SetAnnotations(map[string]string{
+						"kratix.io/destination-selectors": `{"matchLabels": {"region": "eu-west"}}`,
+					})
+				})
+
+				workCreatorEnv := func() []corev1.EnvVar {
+					resources, err := factory.Resources(nil)
+					Expect(err).ToNot(HaveOccurred())
+					containers := resources.Job.Spec.Template.Spec.InitContainers
+					return containers[len(containers)-1].Env
+				}
+
+				It("passes them to the work creator when the Promise allows them", func() {
+					promise.Spec.SchedulingPolicy.AllowResourceRequestDestinationSelectors = true
+					Expect(workCreatorEnv()).To(ConsistOf(corev1.EnvVar{
+						Name:  "KRATIX_RESOURCE_REQUEST_DESTINATION_SELECTORS",
+						Value: `{"matchLabels":{"region":"eu-west"},"source":"resource-request"}`,
+					}))
+				})
+
+				It("ignores them when the Promise does not allow them", func() {
+					Expect(workCreatorEnv()).To(BeEmpty())
+				})
+
+				It("errors when they are invalid", func() {
+					promise.Spec.SchedulingPolicy.AllowResourceRequestDestinationSelectors = true
+					resourceRequest.SetAnnotations(map[string]string{"kratix.io/destination-selectors": "matchLabels: [not, a, map]"})
+					_, err := factory.Resources(nil)
+					Expect(err).To(MatchError(ContainSubstring("invalid kratix.io/destination-selectors annotation")))
+				})
 			})
 
 			Describe("PipelineContainers", func() {
@@ -785,29 +843,30 @@ var _ = Describe("Pipeline", func() {
 					Expect(err).ToNot(HaveOccurred())
 					Expect(resources.Name).To(Equal(pipeline.GetName()))
 
-					Expect(resources.Shared.Roles).To(HaveLen(1))
-					Expect(resources.Shared.Roles[0].GetName()).To(ContainSubstring(factory.ID))
-					Expect(resources.Shared.Roles[0].GetNamespace()).To(Equal("factoryNamespace"))
-					Expect(resources.Shared.Roles[0].Rules).To(ConsistOf(rbacv1.PolicyRule{
+					Expect(resources.Shared.Roles).To(HaveLen(2))
+					Expect(resources.Shared.Roles[1].GetName()).To(ContainSubstring(factory.ID))
+					Expect(resources.Shared.Roles[1].GetNamespace()).To(Equal("factoryNamespace"))
+					Expect(resources.Shared.Roles[1].Rules).To(ConsistOf(rbacv1.PolicyRule{
 						Verbs:         []string{"watch", "create"},
 						APIGroups:     []string{"", "apps"},
 						Resources:     []string{"deployments", "deployments/status"},
 						ResourceNames: []string{"a-deployment", "b-deployment"},
 					}))
-					matchUserPermissionsLabels(&resources, resources.Shared.Roles[0].GetLabels())
-
-					Expect(resources.Shared.RoleBindings).To(HaveLen(1))
-					Expect(resources.Shared.RoleBindings[0].GetName()).To(ContainSubstring(factory.ID))
-					Expect(resources.Shared.RoleBindings[0].GetNamespace()).To(Equal("factoryNamespace"))
-					Expect(resources.Shared.RoleBindings[0].RoleRef.Name).To(ContainSubstring(factory.ID))
-					Expect(resources.Shared.RoleBindings[0].RoleRef.Kind).To(Equal("Role"))
-					Expect(resources.Shared.RoleBindings[0].RoleRef.APIGroup).To(Equal("rbac.authorization.k8s.io"))
-					Expect(resources.Shared.RoleBindings[0].Subjects).To(ConsistOf(rbacv1.Subject{
+					matchUserPermissionsLabels(&resources, resources.Shared.Roles[1].GetLabels())
+
+					Expect(resources.Shared.RoleBindings).To(HaveLen(2))
+					Expect(resources.Shared.RoleBindings[1].GetName()).To(ContainSubstring(factory.ID))
+					Expect(resources.Shared.RoleBindings[1].GetNamespace()).To(Equal("factoryNamespace"))
+					Expect(resources.Shared.RoleBindings[1].RoleRef.Name).To(ContainSubstring(factory.ID))
+					Expect(resources.Shared.RoleBindings[1].RoleRef.Kind).To(Equal("Role"))
+					Expect(resources.Shared.RoleBindings[1].RoleRef.APIGroup).To(Equal("rbac.authorization.k8s.io"))
+					Expect(resources.Shared.RoleBindings[1].Subjects).To(ConsistOf(rbacv1.Subject{
 						Kind:      rbacv1.ServiceAccountKind,
 						Namespace: resources.Shared.ServiceAccount.GetNamespace(),
 						Name:      resources.Shared.ServiceAccount.GetName(),
 					}))
-					matchUserPermissionsLabels(&resources, resources.Shared.RoleBindings[0].GetLabels())
+					matchUserPermissionsLabels(&resources, resources.Shared.RoleBindings[1].GetLabels())
+					matchPromisePayloadRoleAndBinding(resources.Shared.Roles, resources.Shared.RoleBindings, factory, resources.Shared.ServiceAccount)
 				})
 			})
 
@@ -886,7 +945,7 @@ var _ = Describe("Pipeline", func() {
 					resources, err := factory.Resources(nil)
 					Expect(err).ToNot(HaveOccurred())
 					Expect(resources.Name).To(Equal(pipeline.GetName()))
-					Expect(resources.Shared.Roles).To(HaveLen(0))
+					Expect(resources.Shared.Roles).To(HaveLen(1))
 					Expect(resources.Shared.ClusterRoleBindings).To(HaveLen(1))
 
 					Expect(resources.Shared.ClusterRoles).To(ConsistOf(
@@ -908,17 +967,18 @@ var _ = Describe("Pipeline", func() {
 						}),
 					))
 
-					Expect(resources.Shared.RoleBindings).To(HaveLen(1))
-					matchUserPermissionsLabels(&resources, resources.Shared.RoleBindings[0].GetLabels())
-					Expect(resources.Shared.RoleBindings[0].GetNamespace()).To(Equal("specific-namespace"))
-					Expect(resources.Shared.RoleBindings[0].RoleRef.Name).To(Equal(resources.Shared.ClusterRoles[1].GetName()))
-					Expect(resources.Shared.RoleBindings[0].RoleRef.Kind).To(Equal("ClusterRole"))
-					Expect(resources.Shared.RoleBindings[0].RoleRef.APIGroup).To(Equal("rbac.authorization.k8s.io"))
-					Expect(resources.Shared.RoleBindings[0].Subjects).To(ConsistOf(rbacv1.Subject{
+					Expect(resources.Shared.RoleBindings).To(HaveLen(2))
+					matchUserPermissionsLabels(&resources, resources.Shared.RoleBindings[1].GetLabels())
+					Expect(resources.Shared.RoleBindings[1].GetNamespace()).To(Equal("specific-namespace"))
+					Expect(resources.Shared.RoleBindings[1].RoleRef.Name).To(Equal(resources.Shared.ClusterRoles[1].GetName()))
+					Expect(resources.Shared.RoleBindings[1].RoleRef.Kind).To(Equal("ClusterRole"))
+					Expect(resources.Shared.RoleBindings[1].RoleRef.APIGroup).To(Equal("rbac.authorization.k8s.io"))
+					Expect(resources.Shared.RoleBindings[1].Subjects).To(ConsistOf(rbacv1.Subject{
 						Kind:      rbacv1.ServiceAccountKind,
 						Namespace: resources.Shared.ServiceAccount.GetNamespace(),
 						Name:      resources.Shared.ServiceAccount.GetName(),
 					}))
+					matchPromisePayloadRoleAndBinding(resources.Shared.Roles, resources.Shared.RoleBindings, factory, resources.Shared.ServiceAccount)
 				})
 			})
 
@@ -991,8 +1051,9 @@ var _ = Describe("Pipeline", func() {
 					Expect(err).ToNot(HaveOccurred())
 					Expect(resources.Name).To(Equal(pipeline.GetName()))
 
-					Expect(resources.Shared.Roles).To(HaveLen(0))
-					Expect(resources.Shared.RoleBindings).To(HaveLen(0))
+					Expect(resources.Shared.Roles).To(HaveLen(1))
+					Expect(resources.Shared.RoleBindings).To(HaveLen(1))
+					matchPromisePayloadRoleAndBinding(resources.Shared.Roles, resources.Shared.RoleBindings, factory, resources.Shared.ServiceAccount)
 
 					Expect(resources.Shared.ClusterRoles).To(HaveLen(2))
 					Expect(resources.Shared.ClusterRoles[1].Rules).To(ConsistOf(rbacv1.PolicyRule{
@@ -1116,12 +1177,13 @@ var _ = Describe("Pipeline", func() {
 				Expect(err).ToNot(HaveOccurred())
 				Expect(resources.Name).To(Equal(pipeline.GetName()))
 
-				Expect(resources.Shared.RoleBindings).To(HaveLen(2))
+				Expect(resources.Shared.RoleBindings).To(HaveLen(3))
 				Expect(resources.Shared.ClusterRoles).To(HaveLen(3))
 				Expect(resources.Shared.ClusterRoleBindings).To(HaveLen(2))
 
-				By("creating the role in the pipeline namespace")
+				By("creating the role in the pipeline namespace, and the role for the workload payload")
 				Expect(resources.Shared.Roles).To(ConsistOf(
+					payloadRoleMatcher(factory),
 					MatchFields(IgnoreExtras, Fields{
 						"ObjectMeta": MatchFields(IgnoreExtras, Fields{
 							"Name":      MatchRegexp(fmt.Sprintf(`^%s-\b\w{5}\b$`, factory.ID)),
@@ -1135,10 +1197,11 @@ var _ = Describe("Pipeline", func() {
 						}),
 					}),
 				))
-				matchUserPermissionsLabels(&resources, resources.Shared.Roles[0].GetLabels())
+				matchUserPermissionsLabels(&resources, resources.Shared.Roles[1].GetLabels())
 
-				By("creating the role bindings for the pipeline and specific namespace")
+				By("creating the role bindings for the pipeline and specific namespace, and for the workload payload")
 				Expect(resources.Shared.RoleBindings).To(ConsistOf(
+					payloadRoleBindingMatcher(factory, resources.Shared.ServiceAccount),
 					MatchFields(IgnoreExtras, Fields{
 						"ObjectMeta": MatchFields(IgnoreExtras, Fields{
 							"Name":      MatchRegexp(fmt.Sprintf(`^%s-\b\w{5}\b$`, factory.ID)),
@@ -1317,6 +1380,45 @@ func matchPromiseClusterRolesAndBindings(clusterRoles []rbacv1.ClusterRole, clus
 	}))
 }
 
+func matchPromisePayloadRoleAndBinding(roles []rbacv1.Role, bindings []rbacv1.RoleBinding, factory *v1alpha1.PipelineFactory, sa *corev1.ServiceAccount) {
+	ExpectWithOffset(1, roles).To(ContainElement(payloadRoleMatcher(factory)))
+	ExpectWithOffset(1, bindings).To(ContainElement(payloadRoleBindingMatcher(factory, sa)))
+}
+
+func payloadRoleMatcher(factory *v1alpha1.PipelineFactory) OmegaMatcher {
+	return MatchFields(IgnoreExtras, Fields{
+		"ObjectMeta": MatchFields(IgnoreExtras, Fields{
+			"Name":      Equal(factory.ID),
+			"Namespace": Equal(v1alpha1.SystemNamespace),
+			"Labels":    HaveKeyWithValue(v1alpha1.PromiseNameLabel, factory.Promise.GetName()),
+		}),
+		"Rules": ConsistOf(rbacv1.PolicyRule{
+			APIGroups: []string{""},
+			Resources: []string{"configmaps"},
+			Verbs:     []string{"get", "create"},
+		}),
+	})
+}
+
+func payloadRoleBindingMatcher(factory *v1alpha1.PipelineFactory, sa *corev1.ServiceAccount) OmegaMatcher {
+	return MatchFields(IgnoreExtras, Fields{
+		"ObjectMeta": MatchFields(IgnoreExtras, Fields{
+			"Name":      Equal(factory.ID),
+			"Namespace": Equal(v1alpha1.SystemNamespace),
+		}),
+		"RoleRef": Equal(rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     factory.ID,
+		}),
+		"Subjects": ConsistOf(rbacv1.Subject{
+			Kind:      rbacv1.ServiceAccountKind,
+			Namespace: sa.GetNamespace(),
+			Name:      sa.GetName(),
+		}),
+	})
+}
+
 func matchResourceRolesAndBindings(roles []rbacv1.Role, bindings []rbacv1.RoleBinding, factory *v1alpha1.PipelineFactory, sa *corev1.ServiceAccount, promiseCrd *apiextensionsv1.CustomResourceDefinition) {
 	Expect(roles).To(HaveLen(1))
 	Expect(roles[0].GetName()).To(Equal(factory.ID))
@@ -1330,6 +1432,10 @@ func matchResourceRolesAndBindings(roles []rbacv1.Role, bindings []rbacv1.RoleBi
 		APIGroups: []string{v1alpha1.GroupVersion.Group},
 		Resources: []string{"works"},
 		Verbs:     []string{"*"},
+	}, rbacv1.PolicyRule{
+		APIGroups: []string{""},
+		Resources: []string{"configmaps"},
+		Verbs:     []string{"get", "create"},
 	}))
 
 	Expect(bindings).To(HaveLen(1))
@@ -62,8 +62,186 @@ type PromiseSpec struct {
 	// A collection of prerequisites that enable the creation of a Resource.
 	Dependencies Dependencies `json:"dependencies,omitempty"`
 
-	// A list of key and value pairs (labels) used for scheduling.
+	// A list of key and value pairs (labels) and label expressions used for
+	// scheduling.
 	DestinationSelectors []PromiseScheduling `json:"destinationSelectors,omitempty"`
+
+	// Configures how the Promise's resources are scheduled to Destinations.
+	// +kubebuilder:validation:Optional
+	SchedulingPolicy SchedulingPolicy `json:"schedulingPolicy,omitempty"`
+
+	// What happens to the State Store content of a resource when its
+	// resource request is deleted. Delete removes it; Orphan leaves it in
+	// place, removing only the Works and WorkPlacements without running the
+	// delete workflows, and records what was orphaned in the State Store.
+	// Resource requests can override it with the kratix.io/deletion-policy
+	// annotation. Defaults to Delete.
+	// +kubebuilder:validation:Enum:={Delete,Orphan}
+	// +kubebuilder:validation:Optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+const (
+	// The scheduling strategies built into Kratix. Others can be registered
+	// with the Scheduler.
+	SchedulingStrategyRandom         = "random"
+	SchedulingStrategyRoundRobin     = "roundRobin"
+	SchedulingStrategyLeastLoaded    = "leastLoaded"
+	SchedulingStrategyConsistentHash = "consistentHash"
+)
+
+type SchedulingPolicy struct {
+	// strategy chooses the Destination for a resource when more than one
+	// Destination matches, and can be set to either:
+	// - random: a random matching Destination
+	// - roundRobin: each matching Destination in turn
+	// - leastLoaded: the matching Destination with the fewest WorkPlacements
+	// - consistentHash: the same matching Destination for a given resource
+	// - the name of any other strategy registered with the Kratix scheduler
+	// When unset, the strategy configured for Kratix is used, which defaults to random.
+	// Resources of a Promise whose strategy is not registered are not scheduled.
+	// +kubebuilder:validation:Optional
+	Strategy string `json:"strategy,omitempty"`
+
+	// The amount of each Destination capacity dimension consumed by each
+	// WorkPlacement of the Promise's resources, for example `databases: 1`.
+	// See Destination spec.capacity.dimensions.
+	// +kubebuilder:validation:Optional
+	CapacityRequests map[string]int `json:"capacityRequests,omitempty"`
+
+	// The number of Destinations each of the Promise's resources is scheduled
+	// to. Each replica is scheduled to a different Destination. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	Replicas *int `json:"replicas,omitempty"`
+
+	// Constraints on how the replicas of each resource are spread across
+	// Destination topology domains.
+	// +kubebuilder:validation:Optional
+	TopologySpreadConstraints []TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// Configures whether resources are moved when the Destination they are
+	// scheduled to no longer matches their destinationSelectors.
+	// +kubebuilder:validation:Optional
+	Rescheduling ReschedulingPolicy `json:"rescheduling,omitempty"`
+
+	// Configures how changes to the Promise dependencies are rolled out to
+	// the Destinations they are already scheduled to. When unset, every
+	// Destination is updated at once.
+	// +kubebuilder:validation:Optional
+	DependencyRollout *DependencyRolloutPolicy `json:"dependencyRollout,omitempty"`
+
+	// If true, resource requests can constrain the Destinations they are
+	// scheduled to with the kratix.io/destination-selectors annotation. The
+	// annotation takes priority over the resource workflow destination
+	// selectors, but not over the Promise or Promise workflow ones.
+	// +kubebuilder:validation:Optional
+	AllowResourceRequestDestinationSelectors bool `json:"allowResourceRequestDestinationSelectors,omitempty"`
+}
+
+// ResumeDependencyRolloutAnnotation resumes a Promise dependency rollout that
+// was paused because writes to a Destination failed. It is removed once the
+// rollout resumes.
+const ResumeDependencyRolloutAnnotation = KratixPrefix + "resume-dependency-rollout"
+
+type DependencyRolloutPolicy struct {
+	// The Destination label whose values order the waves of the rollout, for
+	// example `rollout-wave`. Waves are rolled out in the lexical order of
+	// the label values, and Destinations without the label are updated in
+	// the last wave. When unset, all Destinations are in a single wave.
+	// +kubebuilder:validation:Optional
+	WaveLabel string `json:"waveLabel,omitempty"`
+
+	// The maximum number of Destinations updated at once. A batch never
+	// spans more than one wave. When unset, each wave is updated at once.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	BatchSize int `json:"batchSize,omitempty"`
+
+	// How long to wait after a batch is updated before updating the next.
+	// The next batch is only updated once every Destination of the previous
+	// batch has been written to.
+	// +kubebuilder:validation:Optional
+	Pause *metav1.Duration `json:"pause,omitempty"`
+
+	// If true, the rollout is paused when writing the dependencies to any
+	// Destination of a batch fails. A paused rollout is resumed by setting the
+	// kratix.io/resume-dependency-rollout annotation on the Promise.
+	// +kubebuilder:validation:Optional
+	HaltOnFailure bool `json:"haltOnFailure,omitempty"`
+}
+
+// Returns the Promise dependency rollout policy, or nil if every Destination
+// is updated at once
+func (p *Promise) GetDependencyRollout() *DependencyRolloutPolicy {
+	if p == nil {
+		return nil
+	}
+	return p.Spec.SchedulingPolicy.DependencyRollout
+}
+
+const (
+	// if modifying these dont forget to edit below where they are written as a
+	// kubebuilder comment for setting the default and Enum values.
+	ReschedulingPolicyNever           = "never"
+	ReschedulingPolicyOnMismatch      = "onMismatch"
+	ReschedulingPolicyOnMismatchAfter = "onMismatchAfter"
+)
+
+// +kubebuilder:validation:XValidation:rule="!has(self.policy) || self.policy != 'onMismatchAfter' || has(self.after)",message="rescheduling.after is required when rescheduling.policy is onMismatchAfter"
+type ReschedulingPolicy struct {
+	// policy can be set to either:
+	// - never (default): resources stay on Destinations that no longer match, and are marked as misscheduled
+	// - onMismatch: resources are moved as soon as their Destination no longer matches
+	// - onMismatchAfter: resources are moved once their Destination has not matched for rescheduling.after
+	// A resource is moved by scheduling it to a new Destination, and only
+	// removing it from the old Destination once it is written to the new one.
+	// Promise dependencies are not moved.
+	// +kubebuilder:validation:Enum:={never,onMismatch,onMismatchAfter}
+	// +kubebuilder:default:="never"
+	Policy string `json:"policy,omitempty"`
+
+	// How long a Destination must not match before resources are moved, when
+	// policy is onMismatchAfter.
+	// +kubebuilder:validation:Optional
+	After *metav1.Duration `json:"after,omitempty"`
+}
+
+const (
+	// if modifying these dont forget to edit below where they are written as a
+	// kubebuilder comment for setting the default and Enum values.
+	WhenUnsatisfiableDoNotSchedule  = "DoNotSchedule"
+	WhenUnsatisfiableScheduleAnyway = "ScheduleAnyway"
+)
+
+type TopologySpreadConstraint struct {
+	// The Destination label whose values are the topology domains, for
+	// example `region`.
+	// +kubebuilder:validation:MinLength=1
+	TopologyKey string `json:"topologyKey"`
+
+	// The maximum permitted difference between the number of replicas of a
+	// resource in any two topology domains.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default:=1
+	MaxSkew int `json:"maxSkew,omitempty"`
+
+	// whenUnsatisfiable can be set to either:
+	// - DoNotSchedule (default): replicas are not scheduled if they would exceed maxSkew,
+	//   and Destinations without the topologyKey label are not used
+	// - ScheduleAnyway: replicas are scheduled to the Destinations that minimise the skew,
+	//   but may exceed maxSkew; Destinations without the topologyKey label are still used
+	// +kubebuilder:validation:Enum:={DoNotSchedule,ScheduleAnyway}
+	// +kubebuilder:default:="DoNotSchedule"
+	WhenUnsatisfiable string `json:"whenUnsatisfiable,omitempty"`
+}
+
+// Returns the number of Destinations each resource is scheduled to
+func (p *Promise) GetReplicas() int {
+	if p == nil || p.Spec.SchedulingPolicy.Replicas == nil {
+		return 1
+	}
+	return *p.Spec.SchedulingPolicy.Replicas
 }
 
 type RequiredPromise struct {
@@ -97,15 +275,110 @@ type Dependency struct {
 
 type PromiseScheduling struct {
 	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+	// A list of label selector requirements, all of which a Destination's
+	// labels must satisfy. Supported operators are In, NotIn, Exists and
+	// DoesNotExist.
+	// +optional
+	MatchExpressions []metav1.LabelSelectorRequirement `json:"matchExpressions,omitempty"`
+	// A list of Destination taints the Promise's works tolerate.
+	// +optional
+	Tolerations []Toleration `json:"tolerations,omitempty"`
+	// Soft selectors: resources are scheduled to the matching Destination with
+	// the highest total weight of matching preferred selectors. Destinations
+	// that match none of them can still be selected.
+	// +optional
+	Preferred []PreferredDestinationSelector `json:"preferred,omitempty"`
+	// Resources are only scheduled to Destinations that the resource requests
+	// selected by each term are already scheduled to.
+	// +optional
+	ResourceAffinity []ResourceAffinityTerm `json:"resourceAffinity,omitempty"`
+	// Resources are not scheduled to Destinations that the resource requests
+	// selected by any term are already scheduled to.
+	// +optional
+	ResourceAntiAffinity []ResourceAffinityTerm `json:"resourceAntiAffinity,omitempty"`
+	// Only selects the members of the named DestinationGroup.
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	DestinationGroup string `json:"destinationGroup,omitempty"`
+}
+
+// Returns the matchLabels, including the label of the DestinationGroup members
+// when a destinationGroup is set
+func (s PromiseScheduling) GetMatchLabels() map[string]string {
+	return withDestinationGroupLabel(s.MatchLabels, s.DestinationGroup)
+}
+
+// ResourceAffinityTerm selects other resource requests by their labels. Terms
+// only affect where resources are scheduled; resources already scheduled are
+// not moved when the selected resource requests move.
+type ResourceAffinityTerm struct {
+	// Selects resource requests by their labels.
+	// +optional
+	LabelSelector metav1.LabelSelector `json:"labelSelector,omitempty" yaml:"labelselector,omitempty"`
+	// Label keys whose values on the resource request being scheduled are added
+	// to labelSelector, for example to select the resource requests of the same
+	// team.
+	// +optional
+	MatchLabelKeys []string `json:"matchLabelKeys,omitempty" yaml:"matchlabelkeys,omitempty"`
+	// The Promises of the selected resource requests. Empty means any Promise.
+	// +optional
+	Promises []string `json:"promises,omitempty" yaml:"promises,omitempty"`
+	// The namespaces of the selected resource requests. Empty means the
+	// namespace of the resource request being scheduled.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+}
+
+type PreferredDestinationSelector struct {
+	// Added to the score of each Destination the selector matches, in the
+	// range 1-100.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Weight int32 `json:"weight" yaml:"weight"`
+	// +optional
+	MatchLabels map[string]string `json:"matchLabels,omitempty" yaml:"matchlabels,omitempty"`
+	// +optional
+	MatchExpressions []metav1.LabelSelectorRequirement `json:"matchExpressions,omitempty" yaml:"matchexpressions,omitempty"`
 }
 
 // For /kratix/metadata/destination-selectors.yaml
 type WorkflowDestinationSelectors struct {
 	MatchLabels map[string]string `json:"matchLabels,omitempty"`
 	// +optional
+	MatchExpressions []metav1.LabelSelectorRequirement `json:"matchExpressions,omitempty"`
+	// +optional
+	Tolerations []Toleration `json:"tolerations,omitempty"`
+	// +optional
+	Preferred []PreferredDestinationSelector `json:"preferred,omitempty"`
+	// +optional
+	ResourceAffinity []ResourceAffinityTerm `json:"resourceAffinity,omitempty"`
+	// +optional
+	ResourceAntiAffinity []ResourceAffinityTerm `json:"resourceAntiAffinity,omitempty"`
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	DestinationGroup string `json:"destinationGroup,omitempty"`
+	// +optional
 	Directory string `json:"directory,omitempty"`
 }
 
+// Returns the matchLabels, including the label of the DestinationGroup members
+// when a destinationGroup is set
+func (s WorkflowDestinationSelectors) GetMatchLabels() map[string]string {
+	return withDestinationGroupLabel(s.MatchLabels, s.DestinationGroup)
+}
+
+func withDestinationGroupLabel(matchLabels map[string]string, groupName string) map[string]string {
+	if groupName == "" {
+		return matchLabels
+	}
+
+	withGroup := map[string]string{DestinationGroupLabel(groupName): "true"}
+	for key, value := range matchLabels {
+		withGroup[key] = value
+	}
+	return withGroup
+}
+
 // PromiseStatus defines the observed state of Promise
 type PromiseStatus struct {
 	Conditions         []metav1.Condition      `json:"conditions,omitempty"`
@@ -117,6 +390,48 @@ type PromiseStatus struct {
 	RequiredPromises   []RequiredPromiseStatus `json:"requiredPromises,omitempty"`
 	RequiredBy         []RequiredBy            `json:"requiredBy,omitempty"`
 	LastAvailableTime  *metav1.Time            `json:"lastAvailableTime,omitempty"`
+	// The progress of rolling out the latest change to the Promise
+	// dependencies, when schedulingPolicy.dependencyRollout is set
+	// +optional
+	DependencyRollout *DependencyRolloutStatus `json:"dependencyRollout,omitempty"`
+	// The write status of the Promise dependencies and Promise workflow
+	// outputs on each Destination they are scheduled to
+	// +optional
+	Destinations []DestinationWriteStatus `json:"destinations,omitempty"`
+}
+
+const (
+	DependencyRolloutProgressing = "Progressing"
+	DependencyRolloutPaused      = "Paused"
+	DependencyRolloutCompleted   = "Completed"
+)
+
+type DependencyRolloutStatus struct {
+	// phase can be one of:
+	// - Progressing: batches of Destinations are being updated
+	// - Paused: writes to a Destination failed and the rollout is waiting to be resumed
+	// - Completed: every Destination has been updated
+	Phase string `json:"phase"`
+	// Identifies the dependencies being rolled out
+	Revision string `json:"revision"`
+	// The number of Destinations updated with the dependencies being rolled out
+	UpdatedDestinations int `json:"updatedDestinations"`
+	// The number of Destinations the dependencies are scheduled to
+	TotalDestinations int `json:"totalDestinations"`
+	// The value of the wave label of the Destinations being updated
+	// +optional
+	CurrentWave string `json:"currentWave,omitempty"`
+	// The Destinations of the batch being updated
+	// +optional
+	CurrentBatch []string `json:"currentBatch,omitempty"`
+	// When the current batch started being updated
+	// +optional
+	LastBatchTime *metav1.Time `json:"lastBatchTime,omitempty"`
+	// When the next batch will be updated, while pausing between batches
+	// +optional
+	NextBatchTime *metav1.Time `json:"nextBatchTime,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 type PromiseSummary struct {
@@ -162,13 +477,83 @@ func SquashPromiseScheduling(scheduling []PromiseScheduling) map[string]string {
 	labels := map[string]string{}
 	//Reverse order, first item in the array gets priority this way
 	for i := len(scheduling) - 1; i >= 0; i-- {
-		for key, value := range scheduling[i].MatchLabels {
+		for key, value := range scheduling[i].GetMatchLabels() {
 			labels[key] = value
 		}
 	}
 	return labels
 }
 
+// Returns the match expressions of all the scheduling entries; a Destination
+// must satisfy all of them
+func SquashPromiseSchedulingExpressions(scheduling []PromiseScheduling) []metav1.LabelSelectorRequirement {
+	var expressions []metav1.LabelSelectorRequirement
+	for _, s := range scheduling {
+		expressions = append(expressions, s.MatchExpressions...)
+	}
+	return expressions
+}
+
+// Returns the tolerations of all the scheduling entries
+func SquashPromiseSchedulingTolerations(scheduling []PromiseScheduling) []Toleration {
+	var tolerations []Toleration
+	for _, s := range scheduling {
+		tolerations = append(tolerations, s.Tolerations...)
+	}
+	return tolerations
+}
+
+// Returns the preferred selectors of all the scheduling entries
+func SquashPromiseSchedulingPreferences(scheduling []PromiseScheduling) []PreferredDestinationSelector {
+	var preferred []PreferredDestinationSelector
+	for _, s := range scheduling {
+		preferred = append(preferred, s.Preferred...)
+	}
+	return preferred
+}
+
+// Returns the resource affinity and anti-affinity terms of all the scheduling
+// entries
+func SquashPromiseSchedulingResourceAffinity(scheduling []PromiseScheduling) ([]ResourceAffinityTerm, []ResourceAffinityTerm) {
+	var affinity, antiAffinity []ResourceAffinityTerm
+	for _, s := range scheduling {
+		affinity = append(affinity, s.ResourceAffinity...)
+		antiAffinity = append(antiAffinity, s.ResourceAntiAffinity...)
+	}
+	return affinity, antiAffinity
+}
+
+// Returns an error if the label selector of any of the terms is invalid
+func ValidateResourceAffinityTerms(terms []ResourceAffinityTerm) error {
+	for i, term := range terms {
+		if _, err := metav1.LabelSelectorAsSelector(&term.LabelSelector); err != nil {
+			return fmt.Errorf("[%d].labelSelector: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Returns an error if any of the preferred selectors has a weight outside of
+// 1-100, or match expressions that are not valid label selector requirements
+func ValidatePreferredDestinationSelectors(preferred []PreferredDestinationSelector) error {
+	for i, selector := range preferred {
+		if selector.Weight < 1 || selector.Weight > 100 {
+			return fmt.Errorf("preferred[%d]: weight must be between 1 and 100, got %d", i, selector.Weight)
+		}
+		if err := ValidateMatchExpressions(selector.MatchExpressions); err != nil {
+			return fmt.Errorf("preferred[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Returns an error if the match expressions are not valid label selector
+// requirements
+func ValidateMatchExpressions(expressions []metav1.LabelSelectorRequirement) error {
+	_, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchExpressions: expressions})
+	return err
+}
+
 func (p *Promise) GetSchedulingSelectors() map[string]string {
 	return generateLabelSelectorsFromScheduling(p.Spec.DestinationSelectors)
 }
@@ -178,7 +563,7 @@ func generateLabelSelectorsFromScheduling(scheduling []PromiseScheduling) map[st
 	//		 different target options.
 	schedulingSelectors := map[string]string{}
 	for _, schedulingConfig := range scheduling {
-		schedulingSelectors = labels.Merge(schedulingConfig.MatchLabels, schedulingSelectors)
+		schedulingSelectors = labels.Merge(schedulingConfig.GetMatchLabels(), schedulingSelectors)
 	}
 	return schedulingSelectors
 }
@@ -311,8 +696,13 @@ func (p *Promise) GetWorkloadGroupScheduling() []WorkloadGroupScheduling {
 	workloadGroupScheduling := []WorkloadGroupScheduling{}
 	for _, scheduling := range p.Spec.DestinationSelectors {
 		workloadGroupScheduling = append(workloadGroupScheduling, WorkloadGroupScheduling{
-			MatchLabels: scheduling.MatchLabels,
-			Source:      "promise",
+			MatchLabels:          scheduling.GetMatchLabels(),
+			MatchExpressions:     scheduling.MatchExpressions,
+			Tolerations:          scheduling.Tolerations,
+			Preferred:            scheduling.Preferred,
+			ResourceAffinity:     scheduling.ResourceAffinity,
+			ResourceAntiAffinity: scheduling.ResourceAntiAffinity,
+			Source:               "promise",
 		})
 	}
 
@@ -4,6 +4,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	platformv1alpha1 "github.com/syntasso/kratix/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
@@ -39,6 +40,38 @@ var _ = Describe("Promise", func() {
 			selectors := promise.GetSchedulingSelectors()
 			Expect(labels.FormatLabels(selectors)).To(Equal(`environment=dev,pci=false,secure=false`))
 		})
+
+		It("selects the members of a destinationGroup", func() {
+			promise := platformv1alpha1.Promise{
+				Spec: platformv1alpha1.PromiseSpec{
+					DestinationSelectors: []platformv1alpha1.PromiseScheduling{
+						{DestinationGroup: "edge", MatchLabels: map[string]string{"environment": "prod"}},
+					},
+				},
+			}
+
+			Expect(labels.FormatLabels(promise.GetSchedulingSelectors())).To(Equal(`destinationgroup.kratix.io/edge=true,environment=prod`))
+			Expect(promise.GetWorkloadGroupScheduling()[0].MatchLabels).To(Equal(map[string]string{
+				"destinationgroup.kratix.io/edge": "true",
+				"environment":                     "prod",
+			}))
+		})
 	})
 
+	Describe("GetDeletionPolicy", func() {
+		It("returns the resource request annotation, or the Promise policy", func() {
+			promise := &platformv1alpha1.Promise{}
+			resourceRequest := &metav1.ObjectMeta{}
+			Expect(promise.GetDeletionPolicy(resourceRequest)).To(Equal(platformv1alpha1.DeletionPolicyDelete))
+
+			promise.Spec.DeletionPolicy = platformv1alpha1.DeletionPolicyOrphan
+			Expect(promise.GetDeletionPolicy(resourceRequest)).To(Equal(platformv1alpha1.DeletionPolicyOrphan))
+
+			resourceRequest.SetAnnotations(map[string]string{"kratix.io/deletion-policy": "Delete"})
+			Expect(promise.GetDeletionPolicy(resourceRequest)).To(Equal(platformv1alpha1.DeletionPolicyDelete))
+
+			resourceRequest.SetAnnotations(map[string]string{"kratix.io/deletion-policy": "invalid"})
+			Expect(promise.GetDeletionPolicy(resourceRequest)).To(Equal(platformv1alpha1.DeletionPolicyOrphan))
+		})
+	})
 })
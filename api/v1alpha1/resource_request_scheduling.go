@@ -0,0 +1,57 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ResourceRequestDestinationSelectorsAnnotation constrains the Destinations
+	// a resource request is scheduled to, when its Promise sets
+	// schedulingPolicy.allowResourceRequestDestinationSelectors. The value is
+	// a label selector in YAML or JSON, for example
+	// `{"matchLabels": {"region": "eu-west"}}`.
+	ResourceRequestDestinationSelectorsAnnotation = KratixPrefix + "destination-selectors"
+
+	// SchedulingSourceResourceRequest is the source of the WorkloadGroup
+	// scheduling set by ResourceRequestDestinationSelectorsAnnotation
+	SchedulingSourceResourceRequest = "resource-request"
+
+	// ResourceRequestDestinationSelectorsEnvVar passes the resource request
+	// scheduling to the work-creator, as JSON
+	ResourceRequestDestinationSelectorsEnvVar = "KRATIX_RESOURCE_REQUEST_DESTINATION_SELECTORS"
+)
+
+// Returns the scheduling the resource request sets with the
+// ResourceRequestDestinationSelectorsAnnotation, or nil if it sets none or the
+// Promise does not allow it
+func (p *Promise) GetResourceRequestScheduling(resourceRequest *unstructured.Unstructured) (*WorkloadGroupScheduling, error) {
+	if p == nil || resourceRequest == nil || !p.Spec.SchedulingPolicy.AllowResourceRequestDestinationSelectors {
+		return nil, nil
+	}
+
+	value, found := resourceRequest.GetAnnotations()[ResourceRequestDestinationSelectorsAnnotation]
+	if !found {
+		return nil, nil
+	}
+
+	selector := metav1.LabelSelector{}
+	if err := yaml.UnmarshalStrict([]byte(value), &selector); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", ResourceRequestDestinationSelectorsAnnotation, err)
+	}
+	if _, err := metav1.LabelSelectorAsSelector(&selector); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", ResourceRequestDestinationSelectorsAnnotation, err)
+	}
+
+	if len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0 {
+		return nil, nil
+	}
+	return &WorkloadGroupScheduling{
+		MatchLabels:      selector.MatchLabels,
+		MatchExpressions: selector.MatchExpressions,
+		Source:           SchedulingSourceResourceRequest,
+	}, nil
+}
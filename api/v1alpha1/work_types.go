@@ -18,6 +18,9 @@ package v1alpha1
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
+
 	"github.com/syntasso/kratix/lib/hash"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -44,6 +47,130 @@ const (
 // WorkStatus defines the observed state of Work
 type WorkStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// The scheduling status of each WorkloadGroup
+	// +optional
+	WorkloadGroups []WorkloadGroupStatus `json:"workloadGroups,omitempty"`
+	// Whether the workloads of each of the Work's WorkPlacements have been
+	// written to their Destination
+	// +optional
+	Placements []PlacementStatus `json:"placements,omitempty"`
+}
+
+const (
+	WriteStatusWritten = "Written"
+	WriteStatusPending = "Pending"
+	WriteStatusFailed  = "Failed"
+
+	SyncStatusSynced    = "Synced"
+	SyncStatusOutOfSync = "OutOfSync"
+	SyncStatusUnknown   = "Unknown"
+
+	HealthStatusHealthy     = "Healthy"
+	HealthStatusProgressing = "Progressing"
+	HealthStatusDegraded    = "Degraded"
+	HealthStatusUnknown     = "Unknown"
+)
+
+type PlacementStatus struct {
+	WorkPlacement   string `json:"workPlacement"`
+	WorkloadGroupID string `json:"workloadGroupID"`
+	Destination     string `json:"destination"`
+	// writeStatus can be one of:
+	// - Written: the current workloads have been written to the Destination
+	// - Pending: the current workloads have not been written yet
+	// - Failed: writing the current workloads to the Destination failed
+	WriteStatus string `json:"writeStatus"`
+	// The version of the workloads last written, if the State Store is
+	// versioned
+	// +optional
+	VersionID string `json:"versionID,omitempty"`
+	// Whether the Destination applied the workloads, as reported back by
+	// the Destination when it has healthFeedback: Synced, OutOfSync or
+	// Unknown
+	// +optional
+	Sync string `json:"sync,omitempty"`
+	// The health of the workloads on the Destination, as reported back by
+	// the Destination when it has healthFeedback: Healthy, Progressing,
+	// Degraded or Unknown
+	// +optional
+	Health string `json:"health,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// DestinationWriteStatus summarises the write status of all the workloads of a
+// resource request or Promise on a Destination
+type DestinationWriteStatus struct {
+	Name string `json:"name"`
+	// writeStatus can be one of:
+	// - Written: all the workloads have been written to the Destination
+	// - Pending: some of the workloads have not been written yet
+	// - Failed: writing some of the workloads to the Destination failed
+	WriteStatus string `json:"writeStatus"`
+	// The least synced of the workloads, when the Destination reports it
+	// +optional
+	Sync string `json:"sync,omitempty"`
+	// The least healthy of the workloads, when the Destination reports it
+	// +optional
+	Health string `json:"health,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type WorkloadGroupStatus struct {
+	ID string `json:"id"`
+	// The Destinations the WorkloadGroup is scheduled to
+	// +optional
+	Destinations []string `json:"destinations,omitempty"`
+	// Explains why the WorkloadGroup could not be fully scheduled. Only set
+	// while the WorkloadGroup is not fully scheduled.
+	// +optional
+	Explanation *SchedulingExplanation `json:"explanation,omitempty"`
+}
+
+const (
+	// if modifying these dont forget to update the documentation of
+	// RejectedDestination.Reason below.
+	RejectionReasonDeleting            = "Deleting"
+	RejectionReasonLabelMismatch       = "LabelMismatch"
+	RejectionReasonStrictMatchLabels   = "StrictMatchLabels"
+	RejectionReasonCordoned            = "Cordoned"
+	RejectionReasonNoStateStore        = "NoStateStore"
+	RejectionReasonUntoleratedTaint    = "UntoleratedTaint"
+	RejectionReasonAtCapacity          = "AtCapacity"
+	RejectionReasonResourceAffinity    = "ResourceAffinity"
+	RejectionReasonNamespaceNotAllowed = "NamespaceNotAllowed"
+)
+
+type SchedulingExplanation struct {
+	// The selector resolved from all destinationSelectors sources
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// The source of the destinationSelectors that set each label key of the
+	// selector: promise, promise-workflow, resource-workflow or
+	// resource-request
+	// +optional
+	LabelSources map[string]string `json:"labelSources,omitempty"`
+	// The Destinations the WorkloadGroup cannot be scheduled to, and why
+	// +optional
+	RejectedDestinations []RejectedDestination `json:"rejectedDestinations,omitempty"`
+}
+
+type RejectedDestination struct {
+	Name string `json:"name"`
+	// reason is one of:
+	// - Deleting: the destination is being deleted
+	// - LabelMismatch: the destination's labels don't match the selector
+	// - StrictMatchLabels: the destination has strictMatchLabels and the selector is empty
+	// - Cordoned: the destination is cordoned or draining
+	// - NoStateStore: the destination has no stateStoreRef to write the workloads to
+	// - UntoleratedTaint: the destination has NoSchedule or NoExecute taints that are not tolerated
+	// - NamespaceNotAllowed: the destination does not allow resource requests from the namespace
+	// - ResourceAffinity: the destination does not satisfy the resource affinity or anti-affinity
+	// - AtCapacity: the destination has no capacity for the resource
+	Reason string `json:"reason"`
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -107,8 +234,11 @@ func NewPromiseDependenciesWork(promise *Promise, name string) (*Work, error) {
 	if len(promise.Spec.DestinationSelectors) > 0 {
 		work.Spec.WorkloadGroups[0].DestinationSelectors = []WorkloadGroupScheduling{
 			{
-				MatchLabels: SquashPromiseScheduling(promise.Spec.DestinationSelectors),
-				Source:      "promise",
+				MatchLabels:      SquashPromiseScheduling(promise.Spec.DestinationSelectors),
+				MatchExpressions: SquashPromiseSchedulingExpressions(promise.Spec.DestinationSelectors),
+				Tolerations:      SquashPromiseSchedulingTolerations(promise.Spec.DestinationSelectors),
+				Preferred:        SquashPromiseSchedulingPreferences(promise.Spec.DestinationSelectors),
+				Source:           "promise",
 			},
 		}
 	}
@@ -124,6 +254,52 @@ func (w *Work) IsDependency() bool {
 	return w.Spec.ResourceName == ""
 }
 
+// Returns the write status of the Works' placements on each Destination,
+// sorted by Destination name. A Destination is Failed if any write to it
+// failed, and Pending if any write to it has not happened yet. The sync and
+// health reported by the Destination are those of the least synced and least
+// healthy workloads.
+func SummariseDestinations(works []Work) []DestinationWriteStatus {
+	severity := map[string]int{WriteStatusWritten: 0, WriteStatusPending: 1, WriteStatusFailed: 2}
+	syncSeverity := map[string]int{SyncStatusSynced: 1, SyncStatusUnknown: 2, SyncStatusOutOfSync: 3}
+	healthSeverity := map[string]int{HealthStatusHealthy: 1, HealthStatusUnknown: 2, HealthStatusProgressing: 3, HealthStatusDegraded: 4}
+
+	byName := map[string]*DestinationWriteStatus{}
+	var names []string
+	for _, work := range works {
+		for _, placement := range work.Status.Placements {
+			status, found := byName[placement.Destination]
+			if !found {
+				status = &DestinationWriteStatus{Name: placement.Destination, WriteStatus: WriteStatusWritten}
+				byName[placement.Destination] = status
+				names = append(names, placement.Destination)
+			}
+			if severity[placement.WriteStatus] > severity[status.WriteStatus] {
+				status.WriteStatus = placement.WriteStatus
+				status.Message = placement.Message
+			}
+			if syncSeverity[placement.Sync] > syncSeverity[status.Sync] {
+				status.Sync = placement.Sync
+			}
+			if healthSeverity[placement.Health] > healthSeverity[status.Health] {
+				status.Health = placement.Health
+			}
+		}
+	}
+
+	sort.Strings(names)
+	var destinations []DestinationWriteStatus
+	for _, name := range names {
+		destinations = append(destinations, *byName[name])
+	}
+	return destinations
+}
+
+// Returns true if the Work holds the static dependencies of a Promise
+func (w *Work) IsStaticDependency() bool {
+	return w.GetLabels()[WorkTypeLabel] == WorkTypeStaticDependency
+}
+
 // WorkloadGroup represents the workloads in a particular directory that should
 // be scheduled to a Destination
 type WorkloadGroup struct {
@@ -138,8 +314,13 @@ type WorkloadGroup struct {
 }
 
 type WorkloadGroupScheduling struct {
-	MatchLabels map[string]string `json:"matchLabels,omitempty"`
-	Source      string            `json:"source,omitempty"`
+	MatchLabels          map[string]string                 `json:"matchLabels,omitempty"`
+	MatchExpressions     []metav1.LabelSelectorRequirement `json:"matchExpressions,omitempty" yaml:"matchexpressions,omitempty"`
+	Tolerations          []Toleration                      `json:"tolerations,omitempty" yaml:"tolerations,omitempty"`
+	Preferred            []PreferredDestinationSelector    `json:"preferred,omitempty" yaml:"preferred,omitempty"`
+	ResourceAffinity     []ResourceAffinityTerm            `json:"resourceAffinity,omitempty" yaml:"resourceaffinity,omitempty"`
+	ResourceAntiAffinity []ResourceAffinityTerm            `json:"resourceAntiAffinity,omitempty" yaml:"resourceantiaffinity,omitempty"`
+	Source               string                            `json:"source,omitempty"`
 }
 
 // Workload represents the manifest workload to be deployed on destination
@@ -148,6 +329,19 @@ type Workload struct {
 	Filepath string `json:"filepath,omitempty"`
 	// Content of the workload, which is base64 encoded and compressed with gzip.
 	Content string `json:"content,omitempty"`
+	// References the content of the workload when it is too large to be held
+	// in the Work, in which case Content is empty
+	// +optional
+	ContentRef *WorkloadContentRef `json:"contentRef,omitempty"`
+}
+
+// WorkloadContentRef references workload content split across ConfigMaps in
+// the namespace of the Work
+type WorkloadContentRef struct {
+	// The sha256 digest of the content, which names the ConfigMaps
+	Digest string `json:"digest"`
+	// The number of ConfigMaps the content is split across
+	Chunks int `json:"chunks"`
 }
 
 //+kubebuilder:object:root=true
@@ -183,3 +377,30 @@ func (w *Work) GetWorkloadGroupScheduling(source, directory string) *WorkloadGro
 func (w *Work) GetDefaultScheduling(source string) *WorkloadGroupScheduling {
 	return w.GetWorkloadGroupScheduling(source, DefaultWorkloadGroupDirectory)
 }
+
+// Sets the scheduling with the resource-request source on every WorkloadGroup,
+// replacing any previously set, or removes it when scheduling is nil. Returns
+// true if the Work changed.
+func (w *Work) SetResourceRequestScheduling(scheduling *WorkloadGroupScheduling) bool {
+	var changed bool
+	for i, wg := range w.Spec.WorkloadGroups {
+		var destinationSelectors []WorkloadGroupScheduling
+		var current *WorkloadGroupScheduling
+		for _, selectors := range wg.DestinationSelectors {
+			if selectors.Source == SchedulingSourceResourceRequest {
+				current = &selectors
+				continue
+			}
+			destinationSelectors = append(destinationSelectors, selectors)
+		}
+		if scheduling != nil {
+			destinationSelectors = append(destinationSelectors, *scheduling)
+		}
+
+		if !reflect.DeepEqual(current, scheduling) {
+			w.Spec.WorkloadGroups[i].DestinationSelectors = destinationSelectors
+			changed = true
+		}
+	}
+	return changed
+}
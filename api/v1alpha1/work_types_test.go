@@ -65,4 +65,43 @@ var _ = Describe("Work", func() {
 		})
 	})
 
+	Describe("SummariseDestinations", func() {
+		It("reports the worst write status of the placements on each Destination", func() {
+			works := []v1alpha1.Work{
+				{Status: v1alpha1.WorkStatus{Placements: []v1alpha1.PlacementStatus{
+					{Destination: "prod", WriteStatus: v1alpha1.WriteStatusWritten},
+					{Destination: "dev", WriteStatus: v1alpha1.WriteStatusPending},
+				}}},
+				{Status: v1alpha1.WorkStatus{Placements: []v1alpha1.PlacementStatus{
+					{Destination: "prod", WriteStatus: v1alpha1.WriteStatusFailed, Message: "push rejected"},
+					{Destination: "dev", WriteStatus: v1alpha1.WriteStatusWritten},
+					{Destination: "staging", WriteStatus: v1alpha1.WriteStatusWritten},
+				}}},
+			}
+
+			Expect(v1alpha1.SummariseDestinations(works)).To(Equal([]v1alpha1.DestinationWriteStatus{
+				{Name: "dev", WriteStatus: v1alpha1.WriteStatusPending},
+				{Name: "prod", WriteStatus: v1alpha1.WriteStatusFailed, Message: "push rejected"},
+				{Name: "staging", WriteStatus: v1alpha1.WriteStatusWritten},
+			}))
+		})
+
+		It("reports the least synced and least healthy placements on each Destination", func() {
+			works := []v1alpha1.Work{
+				{Status: v1alpha1.WorkStatus{Placements: []v1alpha1.PlacementStatus{
+					{Destination: "prod", WriteStatus: v1alpha1.WriteStatusWritten, Sync: v1alpha1.SyncStatusSynced, Health: v1alpha1.HealthStatusHealthy},
+					{Destination: "dev", WriteStatus: v1alpha1.WriteStatusWritten},
+				}}},
+				{Status: v1alpha1.WorkStatus{Placements: []v1alpha1.PlacementStatus{
+					{Destination: "prod", WriteStatus: v1alpha1.WriteStatusWritten, Sync: v1alpha1.SyncStatusOutOfSync, Health: v1alpha1.HealthStatusProgressing},
+					{Destination: "prod", WriteStatus: v1alpha1.WriteStatusWritten, Sync: v1alpha1.SyncStatusSynced, Health: v1alpha1.HealthStatusDegraded},
+				}}},
+			}
+
+			Expect(v1alpha1.SummariseDestinations(works)).To(Equal([]v1alpha1.DestinationWriteStatus{
+				{Name: "dev", WriteStatus: v1alpha1.WriteStatusWritten},
+				{Name: "prod", WriteStatus: v1alpha1.WriteStatusWritten, Sync: v1alpha1.SyncStatusOutOfSync, Health: v1alpha1.HealthStatusDegraded},
+			}))
+		})
+	})
 })
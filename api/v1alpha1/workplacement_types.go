@@ -22,6 +22,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// VersionIDAnnotation records the VersionID of the last write to the State
+// Store as soon as it happens, so Status.VersionID can be set from it even
+// if updating the status fails or Kratix restarts
+const VersionIDAnnotation = KratixPrefix + "version-id"
+
 // WorkPlacementSpec defines the desired state of WorkPlacement
 type WorkPlacementSpec struct {
 	TargetDestinationName string `json:"targetDestinationName,omitempty"`
@@ -42,8 +47,37 @@ type WorkPlacementStatus struct {
 	// +optional
 	// VersionID contains the version identifier of the last applied workplacement
 	// For Git StateStores, this is the SHA of the last applied commit
-	// For Bucket StateStores, this is always empty
+	// For Bucket StateStores, this is a digest of the versions of the objects
+	// last written
+	// It is kept when a write changes nothing, and is recorded in the
+	// kratix.io/version-id annotation before the status is updated
 	VersionID string `json:"versionID,omitempty"`
+
+	// +optional
+	// PendingChangesSince is when the WorkPlacement first had changes that
+	// could not be written as writes are suspended
+	PendingChangesSince *metav1.Time `json:"pendingChangesSince,omitempty"`
+
+	// +optional
+	// WrittenPathsHash is a hash of the State Store, Destination path and
+	// workload paths the workloads were last written to, so the workloads are
+	// written again instead of checked for drift when any of them changes
+	WrittenPathsHash string `json:"writtenPathsHash,omitempty"`
+
+	// +optional
+	// Approval of the workloads, when the Destination requires it
+	Approval *WorkPlacementApproval `json:"approval,omitempty"`
+}
+
+type WorkPlacementApproval struct {
+	// The revision of the workloads, to approve with an Approval
+	Revision string `json:"revision"`
+	// The changes the workloads make to the files in the State Store
+	// +optional
+	Changes []string `json:"changes,omitempty"`
+	// Who approved the revision; empty while it is pending approval
+	// +optional
+	ApprovedBy string `json:"approvedBy,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,17 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SuspendWritesAnnotation freezes what Kratix writes to the State Stores when
+// set to "true". It is honoured on Destinations, Promises, resource requests
+// and Works: nothing is written or deleted for the WorkPlacements they cover
+// until the annotation is removed, and the changes made in the meantime are
+// then applied.
+const SuspendWritesAnnotation = KratixPrefix + "suspend-writes"
+
+// Returns true if the object suspends writes with the SuspendWritesAnnotation
+func WritesSuspended(obj metav1.Object) bool {
+	return obj.GetAnnotations()[SuspendWritesAnnotation] == "true"
+}
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Rolls out a change to the static dependencies of a Promise to the
+// Destinations they are already written to, in the batches set by the
+// Promise's dependencyRollout policy, and records the progress on the Promise
+// status. Returns the Destinations whose WorkPlacements must keep their
+// current workloads for now, or nil if every Destination can be updated.
+func (s *Scheduler) reconcileDependencyRollout(workloadGroup v1alpha1.WorkloadGroup, work *v1alpha1.Work, existingWorkplacements []v1alpha1.WorkPlacement, destinations *destinationCache) (map[string]bool, error) {
+	promise, err := s.getPromise(work)
+	if err != nil || promise == nil {
+		return nil, err
+	}
+
+	policy := promise.GetDependencyRollout()
+	if policy == nil {
+		return nil, s.updateDependencyRolloutStatus(promise, nil)
+	}
+
+	revision, err := v1alpha1.WorkloadsRevision(workloadGroup.Workloads)
+	if err != nil {
+		return nil, err
+	}
+
+	status := v1alpha1.DependencyRolloutStatus{Phase: v1alpha1.DependencyRolloutProgressing, Revision: revision}
+	if current := promise.Status.DependencyRollout; current != nil && current.Revision == revision {
+		status = *current.DeepCopy()
+	}
+
+	if _, resume := promise.GetAnnotations()[v1alpha1.ResumeDependencyRolloutAnnotation]; resume {
+		delete(promise.Annotations, v1alpha1.ResumeDependencyRolloutAnnotation)
+		if err := s.Client.Update(context.Background(), promise); err != nil {
+			return nil, err
+		}
+		if status.Phase == v1alpha1.DependencyRolloutPaused {
+			s.Log.Info("resuming dependency rollout", "promise", promise.GetName())
+			status.Phase = v1alpha1.DependencyRolloutProgressing
+			status.CurrentBatch = nil
+		}
+	}
+
+	updated := map[string]v1alpha1.WorkPlacement{}
+	var outdated []v1alpha1.WorkPlacement
+	for _, wp := range existingWorkplacements {
+		if !wp.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if equality.Semantic.DeepEqual(wp.Spec.Workloads, workloadGroup.Workloads) {
+			updated[wp.Spec.TargetDestinationName] = wp
+		} else {
+			outdated = append(outdated, wp)
+		}
+	}
+
+	heldBack := map[string]bool{}
+	for _, wp := range outdated {
+		heldBack[wp.Spec.TargetDestinationName] = true
+	}
+
+	status.TotalDestinations = len(updated) + len(outdated)
+	status.UpdatedDestinations = len(updated)
+	status.NextBatchTime = nil
+
+	if status.Phase == v1alpha1.DependencyRolloutPaused {
+		return heldBack, s.updateDependencyRolloutStatus(promise, &status)
+	}
+	status.Message = ""
+
+	failed, pending := checkDependencyRolloutBatch(status.CurrentBatch, updated)
+	switch {
+	case len(failed) > 0 && policy.HaltOnFailure:
+		s.Log.Info("pausing dependency rollout as writes failed", "promise", promise.GetName(), "destinations", failed)
+		status.Phase = v1alpha1.DependencyRolloutPaused
+		status.Message = fmt.Sprintf("writing the dependencies to Destinations %v failed; set the %s annotation on the Promise to resume the rollout", failed, v1alpha1.ResumeDependencyRolloutAnnotation)
+		return heldBack, s.updateDependencyRolloutStatus(promise, &status)
+	case len(pending) > 0:
+		status.Message = fmt.Sprintf("waiting for the dependencies to be written to Destinations %v", pending)
+		return heldBack, s.updateDependencyRolloutStatus(promise, &status)
+	case len(outdated) == 0:
+		status.Phase = v1alpha1.DependencyRolloutCompleted
+		status.CurrentWave = ""
+		status.CurrentBatch = nil
+		return nil, s.updateDependencyRolloutStatus(promise, &status)
+	}
+
+	if policy.Pause != nil && status.LastBatchTime != nil {
+		next := status.LastBatchTime.Add(policy.Pause.Duration)
+		if time.Now().Before(next) {
+			status.NextBatchTime = &metav1.Time{Time: next}
+			status.Message = "pausing before updating the next batch of Destinations"
+			return heldBack, s.updateDependencyRolloutStatus(promise, &status)
+		}
+	}
+
+	wave, batch, err := nextDependencyRolloutBatch(policy, outdated, destinations)
+	if err != nil {
+		return nil, err
+	}
+	for _, destination := range batch {
+		delete(heldBack, destination)
+	}
+
+	s.Log.Info("rolling out dependencies to the next batch of Destinations", "promise", promise.GetName(), "wave", wave, "destinations", batch)
+	status.CurrentWave = wave
+	status.CurrentBatch = batch
+	status.LastBatchTime = &metav1.Time{Time: time.Now()}
+	status.UpdatedDestinations += len(batch)
+	return heldBack, s.updateDependencyRolloutStatus(promise, &status)
+}
+
+func (s *Scheduler) updateDependencyRolloutStatus(promise *v1alpha1.Promise, status *v1alpha1.DependencyRolloutStatus) error {
+	if reflect.DeepEqual(promise.Status.DependencyRollout, status) {
+		return nil
+	}
+	promise.Status.DependencyRollout = status
+	return s.Client.Status().Update(context.Background(), promise)
+}
+
+// Returns the Destinations of the batch that failed to be written to, and
+// those that have not been written to yet
+func checkDependencyRolloutBatch(batch []string, updated map[string]v1alpha1.WorkPlacement) ([]string, []string) {
+	var failed, pending []string
+	for _, destination := range batch {
+		wp, found := updated[destination]
+		if !found {
+			continue
+		}
+
+		condition := meta.FindStatusCondition(wp.Status.Conditions, writeSucceededConditionType)
+		switch {
+		case writeSucceeded(wp):
+		case condition != nil &&
+			condition.Status == metav1.ConditionFalse &&
+			condition.ObservedGeneration == wp.GetGeneration():
+			failed = append(failed, destination)
+		default:
+			pending = append(pending, destination)
+		}
+	}
+	return failed, pending
+}
+
+// Returns the wave and the Destinations of the next batch to update. Waves
+// are ordered by the value of the wave label, with Destinations without the
+// label last.
+func nextDependencyRolloutBatch(policy *v1alpha1.DependencyRolloutPolicy, outdated []v1alpha1.WorkPlacement, destinations *destinationCache) (string, []string, error) {
+	type candidate struct {
+		destination string
+		wave        string
+		labelled    bool
+	}
+
+	candidates := make([]candidate, 0, len(outdated))
+	for _, wp := range outdated {
+		c := candidate{destination: wp.Spec.TargetDestinationName}
+		destination, err := destinations.get(c.destination)
+		if err != nil {
+			return "", nil, err
+		}
+		if destination != nil && policy.WaveLabel != "" {
+			c.wave, c.labelled = destination.GetLabels()[policy.WaveLabel]
+		}
+		candidates = append(candidates, c)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].labelled != candidates[j].labelled {
+			return candidates[i].labelled
+		}
+		if candidates[i].wave != candidates[j].wave {
+			return candidates[i].wave < candidates[j].wave
+		}
+		return candidates[i].destination < candidates[j].destination
+	})
+
+	first := candidates[0]
+	var batch []string
+	for _, c := range candidates {
+		if c.labelled != first.labelled || c.wave != first.wave {
+			break
+		}
+		if policy.BatchSize > 0 && len(batch) == policy.BatchSize {
+			break
+		}
+		batch = append(batch, c.destination)
+	}
+	return first.wave, batch, nil
+}
@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Returns the labels of the namespace the resource request's Work is in, which
+// Destinations use to allow or deny access. Returns nil labels when the
+// namespace doesn't exist.
+func (s *Scheduler) getNamespaceLabels(namespace string) (map[string]string, error) {
+	ns := &corev1.Namespace{}
+	err := s.Client.Get(context.Background(), client.ObjectKey{Name: namespace}, ns)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ns.GetLabels(), nil
+}
+
+// Returns the Destinations that allow resource requests from the namespace
+func filterDestinationsByNamespaceAccess(destinations []v1alpha1.Destination, namespace string, namespaceLabels map[string]string) []v1alpha1.Destination {
+	var allowed []v1alpha1.Destination
+	for _, destination := range destinations {
+		if destination.AllowsNamespace(namespace, namespaceLabels) {
+			allowed = append(allowed, destination)
+		}
+	}
+	return allowed
+}
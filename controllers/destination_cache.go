@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// destinationCache lists the Destinations once per Work reconciliation, so each
+// WorkloadGroup and WorkPlacement of the Work is scheduled against the same
+// Destinations without listing them again. It also holds the resource request
+// lookups made to resolve resource affinity for the Work.
+type destinationCache struct {
+	client           client.Client
+	destinations     []v1alpha1.Destination
+	listed           bool
+	resourceRequests *resourceRequestCache
+}
+
+func newDestinationCache(c client.Client) *destinationCache {
+	return &destinationCache{client: c, resourceRequests: newResourceRequestCache(c)}
+}
+
+func (c *destinationCache) list() ([]v1alpha1.Destination, error) {
+	if c.listed {
+		return c.destinations, nil
+	}
+
+	destinationList := &v1alpha1.DestinationList{}
+	if err := c.client.List(context.Background(), destinationList); err != nil {
+		return nil, err
+	}
+	c.destinations = destinationList.Items
+	c.listed = true
+	return c.destinations, nil
+}
+
+// Returns the Destination with the given name, or nil if it doesn't exist
+func (c *destinationCache) get(name string) (*v1alpha1.Destination, error) {
+	destinations, err := c.list()
+	if err != nil {
+		return nil, err
+	}
+	for i := range destinations {
+		if destinations[i].Name == name {
+			return &destinations[i], nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Returns the Destinations that have room for one more WorkPlacement of a
+// resource from the given Promise. Only resource WorkPlacements count towards
+// a Destination's capacity, and those being deleted no longer do, so a
+// draining or rescheduled Destination makes room for their replacements.
+func (s *Scheduler) filterDestinationsWithCapacity(promise *v1alpha1.Promise, destinations []v1alpha1.Destination) ([]v1alpha1.Destination, error) {
+	if !anyDestinationHasCapacity(destinations) {
+		return destinations, nil
+	}
+
+	capacityRequests := promiseCapacityRequests{}
+	if promise != nil {
+		capacityRequests[promise.GetName()] = promise.Spec.SchedulingPolicy.CapacityRequests
+	}
+
+	var available []v1alpha1.Destination
+	for _, destination := range destinations {
+		hasCapacity, err := s.destinationHasCapacity(destination, promise, capacityRequests)
+		if err != nil {
+			return nil, err
+		}
+		if !hasCapacity {
+			s.Log.Info("Destination is at capacity", "destination", destination.Name)
+			continue
+		}
+		available = append(available, destination)
+	}
+	return available, nil
+}
+
+func (s *Scheduler) destinationHasCapacity(destination v1alpha1.Destination, promise *v1alpha1.Promise, capacityRequests promiseCapacityRequests) (bool, error) {
+	capacity := destination.Spec.Capacity
+	if capacity == nil {
+		return true, nil
+	}
+
+	workPlacements := &v1alpha1.WorkPlacementList{}
+	if err := s.Client.List(context.Background(), workPlacements, client.MatchingLabels{targetDestinationNameLabel: destination.Name}); err != nil {
+		return false, err
+	}
+
+	var placed int
+	used := map[string]int{}
+	for _, wp := range workPlacements.Items {
+		if !isPlacedResource(wp) {
+			continue
+		}
+		placed++
+
+		requests, err := capacityRequests.get(s.Client, wp.Spec.PromiseName)
+		if err != nil {
+			return false, err
+		}
+		for dimension, amount := range requests {
+			used[dimension] += amount
+		}
+	}
+
+	if capacity.MaxWorkPlacements != nil && placed+1 > *capacity.MaxWorkPlacements {
+		return false, nil
+	}
+
+	if promise == nil {
+		return true, nil
+	}
+
+	for dimension, amount := range promise.Spec.SchedulingPolicy.CapacityRequests {
+		available, limited := capacity.Dimensions[dimension]
+		if limited && used[dimension]+amount > available {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func anyDestinationHasCapacity(destinations []v1alpha1.Destination) bool {
+	for _, destination := range destinations {
+		if destination.Spec.Capacity != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// promiseCapacityRequests caches the capacity requests of each Promise, so
+// each Promise is fetched at most once when calculating capacity
+type promiseCapacityRequests map[string]map[string]int
+
+func (p promiseCapacityRequests) get(k8sClient client.Client, promiseName string) (map[string]int, error) {
+	if requests, found := p[promiseName]; found {
+		return requests, nil
+	}
+
+	promise := &v1alpha1.Promise{}
+	if err := k8sClient.Get(context.Background(), client.ObjectKey{Name: promiseName}, promise); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	p[promiseName] = promise.Spec.SchedulingPolicy.CapacityRequests
+	return p[promiseName], nil
+}
@@ -27,6 +27,7 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 
 	"github.com/go-logr/logr"
 	"github.com/syntasso/kratix/api/v1alpha1"
@@ -36,8 +37,10 @@ import (
 )
 
 const (
-	canaryWorkload              = "kratix-canary"
-	destinationCleanupFinalizer = v1alpha1.KratixPrefix + "destination-cleanup"
+	canaryWorkload                     = "kratix-canary"
+	destinationCleanupFinalizer        = v1alpha1.KratixPrefix + "destination-cleanup"
+	filepathTemplateValidConditionType = "FilepathTemplateValid"
+	canaryWrittenConditionType         = "CanaryWritten"
 )
 
 // DestinationReconciler reconciles a Destination object
@@ -80,7 +83,22 @@ func (r *DestinationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, nil
 	}
 
+	var drainResult ctrl.Result
+	if destination.DeletionTimestamp.IsZero() {
+		var err error
+		if drainResult, err = r.reconcileDrain(opts, destination); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	writer, err := newWriter(opts, *destination)
+	if err == errNoStateStoreRef {
+		if !destination.DeletionTimestamp.IsZero() {
+			return r.deleteDestination(opts, destination, nil)
+		}
+		logger.Info("Destination has no stateStoreRef; waiting for one to be set")
+		return defaultRequeue, nil
+	}
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return defaultRequeue, nil
@@ -97,17 +115,88 @@ func (r *DestinationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	logger = logger.WithValues("path", path)
 	filePathMode := destination.GetFilepathMode()
 
-	if err = r.createDependenciesPathWithExample(writer, filePathMode); err != nil {
-		logger.Error(err, "unable to write dependencies to state store")
+	if err = r.reconcileFilepathTemplateCondition(opts, destination); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err = r.reconcileCanaryFiles(opts, writer, destination, filePathMode); err != nil {
+		logger.Error(err, "unable to write canary files to state store")
 		return defaultRequeue, nil
 	}
 
-	if err = r.createResourcePathWithExample(writer, filePathMode); err != nil {
-		logger.Error(err, "unable to write dependencies to state store")
+	feedbackResult, err := r.reconcileHealthFeedback(opts, writer, destination)
+	if err != nil {
+		logger.Error(err, "unable to read the health feedback from the state store")
 		return defaultRequeue, nil
 	}
 
-	return ctrl.Result{}, nil
+	if drainResult.RequeueAfter == 0 || (feedbackResult.RequeueAfter != 0 && feedbackResult.RequeueAfter < drainResult.RequeueAfter) {
+		return feedbackResult, nil
+	}
+	return drainResult, nil
+}
+
+// Reports on the Destination status whether its filepath template can be used
+// to write WorkPlacements
+func (r *DestinationReconciler) reconcileFilepathTemplateCondition(o opts, destination *v1alpha1.Destination) error {
+	var changed bool
+	if destination.GetFilepathMode() != v1alpha1.FilepathModeTemplate {
+		changed = meta.RemoveStatusCondition(&destination.Status.Conditions, filepathTemplateValidConditionType)
+	} else {
+		condition := metav1.Condition{
+			Type:               filepathTemplateValidConditionType,
+			Status:             metav1.ConditionTrue,
+			Reason:             "TemplateValid",
+			Message:            "WorkPlacements are written to the paths rendered from filepath.template",
+			ObservedGeneration: destination.GetGeneration(),
+		}
+		if err := validateFilepathTemplate(*destination); err != nil {
+			o.logger.Error(err, "workplacements will not be written to this destination until the filepath template is fixed")
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "TemplateInvalid"
+			condition.Message = err.Error()
+		}
+		changed = meta.SetStatusCondition(&destination.Status.Conditions, condition)
+	}
+
+	if !changed {
+		return nil
+	}
+	return r.Client.Status().Update(o.ctx, destination)
+}
+
+// Writes the canary files to the State Store, and reports on the Destination
+// status whether they were written. They are only written again when the
+// Destination changes, and not each time its health feedback is read.
+func (r *DestinationReconciler) reconcileCanaryFiles(o opts, writer writers.StateStoreWriter, destination *v1alpha1.Destination, filePathMode string) error {
+	current := meta.FindStatusCondition(destination.Status.Conditions, canaryWrittenConditionType)
+	if current != nil && current.Status == metav1.ConditionTrue && current.ObservedGeneration == destination.GetGeneration() {
+		return nil
+	}
+
+	writeErr := r.createDependenciesPathWithExample(writer, filePathMode)
+	if writeErr == nil {
+		writeErr = r.createResourcePathWithExample(writer, filePathMode)
+	}
+
+	condition := metav1.Condition{
+		Type:               canaryWrittenConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "CanaryWritten",
+		Message:            "Canary files written to the State Store",
+		ObservedGeneration: destination.GetGeneration(),
+	}
+	if writeErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "CanaryWriteFailed"
+		condition.Message = writeErr.Error()
+	}
+	if meta.SetStatusCondition(&destination.Status.Conditions, condition) {
+		if err := r.Client.Status().Update(o.ctx, destination); err != nil && writeErr == nil {
+			return err
+		}
+	}
+	return writeErr
 }
 
 func (r *DestinationReconciler) needsFinalizerUpdate(destination *v1alpha1.Destination) bool {
@@ -181,8 +270,11 @@ func (r *DestinationReconciler) deleteDestination(o opts, destination *v1alpha1.
 			return defaultRequeue, nil
 		}
 
-		if err := r.deleteStateStoreContents(o, writer); err != nil {
-			return defaultRequeue, nil
+		// a Destination without a stateStoreRef has nothing to clean up
+		if writer != nil {
+			if err := r.deleteStateStoreContents(o, writer); err != nil {
+				return defaultRequeue, nil
+			}
 		}
 
 		controllerutil.RemoveFinalizer(destination, destinationCleanupFinalizer)
@@ -18,6 +18,7 @@ package controllers_test
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
@@ -27,6 +28,7 @@ import (
 	"github.com/syntasso/kratix/lib/writers"
 	"github.com/syntasso/kratix/lib/writers/writersfakes"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -81,6 +83,247 @@ var _ = Describe("DestinationReconciler", func() {
 		})
 	})
 
+	When("the destination has no stateStoreRef", func() {
+		BeforeEach(func() {
+			testDestination.Spec.StateStoreRef = nil
+			Expect(fakeK8sClient.Create(ctx, testDestination)).To(Succeed())
+		})
+
+		It("requeues until a stateStoreRef is set", func() {
+			result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: testDestinationName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{RequeueAfter: 15 * time.Second}))
+			Expect(fakeWriter.UpdateFilesCallCount()).To(BeZero())
+		})
+
+		It("can still be deleted", func() {
+			testDestination.Spec.Cleanup = v1alpha1.DestinationCleanupAll
+			Expect(fakeK8sClient.Update(ctx, testDestination)).To(Succeed())
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: testDestinationName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeK8sClient.Get(ctx, testDestinationName, testDestination)).To(Succeed())
+			Expect(testDestination.GetFinalizers()).NotTo(BeEmpty())
+
+			Expect(fakeK8sClient.Delete(ctx, testDestination)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: testDestinationName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeK8sClient.Get(ctx, testDestinationName, testDestination)).To(MatchError(ContainSubstring("not found")))
+		})
+	})
+
+	When("draining a destination", func() {
+		reconcile := func() {
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: testDestinationName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeK8sClient.Get(ctx, testDestinationName, testDestination)).To(Succeed())
+		}
+
+		isDraining := func(name string) bool {
+			wp := &v1alpha1.WorkPlacement{}
+			Expect(fakeK8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, wp)).To(Succeed())
+			_, found := wp.GetLabels()["kratix.io/draining"]
+			return found
+		}
+
+		deleteWorkPlacement := func(name string) {
+			wp := &v1alpha1.WorkPlacement{}
+			Expect(fakeK8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, wp)).To(Succeed())
+			Expect(fakeK8sClient.Delete(ctx, wp)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			testDestination.Spec.Drain = true
+			testDestination.Spec.StateStoreRef = &v1alpha1.StateStoreReference{Kind: "BucketStateStore", Name: "test-state-store"}
+			Expect(fakeK8sClient.Create(ctx, testDestination)).To(Succeed())
+
+			for name, resourceName := range map[string]string{"rr-a": "rr", "rr-b": "rr", "dependency": ""} {
+				Expect(fakeK8sClient.Create(ctx, &v1alpha1.WorkPlacement{
+					ObjectMeta: v1.ObjectMeta{
+						Name:      name,
+						Namespace: "default",
+						Labels:    map[string]string{v1alpha1.KratixPrefix + "targetDestinationName": testDestination.Name},
+					},
+					Spec: v1alpha1.WorkPlacementSpec{TargetDestinationName: testDestination.Name, ResourceName: resourceName},
+				})).To(Succeed())
+			}
+		})
+
+		It("moves the resource workplacements off the destination one at a time", func() {
+			reconcile()
+			Expect(isDraining("rr-a")).To(BeTrue())
+			Expect(isDraining("rr-b")).To(BeFalse())
+			Expect(isDraining("dependency")).To(BeFalse())
+			Expect(testDestination.Status.Drain).To(Equal(&v1alpha1.DrainStatus{Phase: v1alpha1.DrainPhaseDraining, Remaining: 2, Migrating: "rr-a"}))
+
+			reconcile()
+			Expect(isDraining("rr-b")).To(BeFalse())
+
+			deleteWorkPlacement("rr-a")
+			reconcile()
+			Expect(isDraining("rr-b")).To(BeTrue())
+			Expect(testDestination.Status.Drain).To(Equal(&v1alpha1.DrainStatus{Phase: v1alpha1.DrainPhaseDraining, Remaining: 1, Migrating: "rr-b"}))
+
+			deleteWorkPlacement("rr-b")
+			reconcile()
+			Expect(testDestination.Status.Drain).To(Equal(&v1alpha1.DrainStatus{Phase: v1alpha1.DrainPhaseDrained, Remaining: 0}))
+		})
+
+		It("keeps the workplacements on the destination when drain is turned off", func() {
+			reconcile()
+			Expect(isDraining("rr-a")).To(BeTrue())
+
+			testDestination.Spec.Drain = false
+			Expect(fakeK8sClient.Update(ctx, testDestination)).To(Succeed())
+			reconcile()
+			Expect(isDraining("rr-a")).To(BeFalse())
+			Expect(testDestination.Status.Drain).To(BeNil())
+		})
+	})
+
+	When("the destination has health feedback enabled", func() {
+		getCondition := func(name, conditionType string) *v1.Condition {
+			wp := &v1alpha1.WorkPlacement{}
+			Expect(fakeK8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, wp)).To(Succeed())
+			return meta.FindStatusCondition(wp.Status.Conditions, conditionType)
+		}
+
+		BeforeEach(func() {
+			testDestination.Spec.HealthFeedback = &v1alpha1.HealthFeedback{}
+			testDestination.Spec.StateStoreRef = &v1alpha1.StateStoreReference{Kind: "BucketStateStore", Name: "test-state-store"}
+			Expect(fakeK8sClient.Create(ctx, testDestination)).To(Succeed())
+			controllers.SetNewS3Writer(func(logger logr.Logger, stateStoreSpec v1alpha1.BucketStateStoreSpec, destination v1alpha1.Destination,
+				creds map[string][]byte) (writers.StateStoreWriter, error) {
+				return fakeWriter, nil
+			})
+			Expect(fakeK8sClient.Create(ctx, &v1alpha1.BucketStateStore{
+				ObjectMeta: v1.ObjectMeta{Name: "test-state-store"},
+				Spec:       v1alpha1.BucketStateStoreSpec{BucketName: "test-bucket", Endpoint: "localhost:9000"},
+			})).To(Succeed())
+
+			for _, name := range []string{"reported", "unreported"} {
+				workPlacement := &v1alpha1.WorkPlacement{
+					ObjectMeta: v1.ObjectMeta{
+						Name:      name,
+						Namespace: "default",
+						Labels:    map[string]string{v1alpha1.KratixPrefix + "targetDestinationName": testDestination.Name},
+					},
+					Spec: v1alpha1.WorkPlacementSpec{TargetDestinationName: testDestination.Name},
+				}
+				Expect(fakeK8sClient.Create(ctx, workPlacement)).To(Succeed())
+				workPlacement.Status.Conditions = []v1.Condition{{
+					Type:               "WriteSucceeded",
+					Status:             v1.ConditionTrue,
+					Reason:             "WorkloadsWritten",
+					LastTransitionTime: v1.Now(),
+				}}
+				Expect(fakeK8sClient.Status().Update(ctx, workPlacement)).To(Succeed())
+			}
+
+			fakeWriter.ReadFilesReturns(map[string][]byte{
+				".kratix/status/default-reported.yaml": []byte("sync: Synced\nhealth: Degraded\nmessage: pod crashlooping\n"),
+			}, nil)
+		})
+
+		It("reads the results for all of its workplacements at once", func() {
+			result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: testDestinationName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+
+			Expect(fakeWriter.ReadFilesCallCount()).To(Equal(1))
+			Expect(fakeWriter.ReadFilesArgsForCall(0)).To(Equal([]string{".kratix/status/"}))
+
+			synced := getCondition("reported", "Synced")
+			Expect(synced.Status).To(Equal(v1.ConditionTrue))
+			Expect(synced.Reason).To(Equal("Synced"))
+			healthy := getCondition("reported", "Healthy")
+			Expect(healthy.Status).To(Equal(v1.ConditionFalse))
+			Expect(healthy.Reason).To(Equal("Degraded"))
+			Expect(healthy.Message).To(Equal("pod crashlooping"))
+
+			By("reporting Unknown until the destination reports results")
+			for _, conditionType := range []string{"Synced", "Healthy"} {
+				condition := getCondition("unreported", conditionType)
+				Expect(condition.Status).To(Equal(v1.ConditionUnknown))
+				Expect(condition.Message).To(ContainSubstring("No results reported"))
+			}
+		})
+
+		It("only writes the canary files again when the destination changes", func() {
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: testDestinationName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeWriter.UpdateFilesCallCount()).To(Equal(2))
+			Expect(fakeK8sClient.Get(ctx, testDestinationName, testDestination)).To(Succeed())
+			condition := meta.FindStatusCondition(testDestination.Status.Conditions, "CanaryWritten")
+			Expect(condition.Status).To(Equal(v1.ConditionTrue))
+
+			By("only reading the health feedback when it is polled again")
+			_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: testDestinationName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeWriter.UpdateFilesCallCount()).To(Equal(2))
+			Expect(fakeWriter.ReadFilesCallCount()).To(Equal(2))
+
+			testDestination.Spec.Path = "new-path"
+			testDestination.Generation++
+			Expect(fakeK8sClient.Update(ctx, testDestination)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: testDestinationName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeWriter.UpdateFilesCallCount()).To(Equal(4))
+		})
+
+		It("removes the conditions when health feedback is turned off", func() {
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: testDestinationName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeK8sClient.Get(ctx, testDestinationName, testDestination)).To(Succeed())
+			testDestination.Spec.HealthFeedback = nil
+			Expect(fakeK8sClient.Update(ctx, testDestination)).To(Succeed())
+			result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: testDestinationName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+
+			Expect(getCondition("reported", "Synced")).To(BeNil())
+			Expect(getCondition("reported", "Healthy")).To(BeNil())
+		})
+	})
+
+	When("the destination has filepath mode of template", func() {
+		BeforeEach(func() {
+			testDestination.Spec.Filepath = v1alpha1.Filepath{
+				Mode:     v1alpha1.FilepathModeTemplate,
+				Template: "{{ .ResourceRequest.Labels.team }}/{{ .Workload.Filepath }}",
+			}
+			testDestination.Spec.StateStoreRef = &v1alpha1.StateStoreReference{Kind: "BucketStateStore", Name: "test-state-store"}
+			Expect(fakeK8sClient.Create(ctx, testDestination)).To(Succeed())
+			controllers.SetNewS3Writer(func(logger logr.Logger, stateStoreSpec v1alpha1.BucketStateStoreSpec, destination v1alpha1.Destination,
+				creds map[string][]byte) (writers.StateStoreWriter, error) {
+				return fakeWriter, nil
+			})
+			Expect(fakeK8sClient.Create(ctx, &v1alpha1.BucketStateStore{
+				ObjectMeta: v1.ObjectMeta{Name: "test-state-store"},
+				Spec:       v1alpha1.BucketStateStoreSpec{BucketName: "test-bucket", Endpoint: "localhost:9000"},
+			})).To(Succeed())
+		})
+
+		It("reports on its status whether the template can be used", func() {
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: testDestinationName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeK8sClient.Get(ctx, testDestinationName, testDestination)).To(Succeed())
+			condition := meta.FindStatusCondition(testDestination.Status.Conditions, "FilepathTemplateValid")
+			Expect(condition.Status).To(Equal(v1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("TemplateInvalid"))
+			Expect(condition.Message).To(ContainSubstring("guard it with {{ with .ResourceRequest }}"))
+
+			testDestination.Spec.Filepath.Template = "{{ with .ResourceRequest }}{{ .Labels.team }}/{{ end }}{{ .Workload.Filepath }}"
+			Expect(fakeK8sClient.Update(ctx, testDestination)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: testDestinationName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeK8sClient.Get(ctx, testDestinationName, testDestination)).To(Succeed())
+			condition = meta.FindStatusCondition(testDestination.Status.Conditions, "FilepathTemplateValid")
+			Expect(condition.Status).To(Equal(v1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("TemplateValid"))
+		})
+	})
+
 	When("deleting a destination", func() {
 		BeforeEach(func() {
 			Expect(fakeK8sClient.Create(ctx, &corev1.Secret{
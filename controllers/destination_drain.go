@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// drainingLabel marks the WorkPlacement that is being moved off a draining
+// Destination. The Scheduler moves WorkPlacements with this label to another
+// Destination.
+const drainingLabel = v1alpha1.KratixPrefix + "draining"
+
+// Moves the resource WorkPlacements off a draining Destination one at a time,
+// by labelling the next one to move once the previous one is gone, and reports
+// progress on the Destination status. When drain is turned off, the
+// WorkPlacement being moved stays on the Destination.
+func (r *DestinationReconciler) reconcileDrain(o opts, destination *v1alpha1.Destination) (ctrl.Result, error) {
+	workPlacementList := &v1alpha1.WorkPlacementList{}
+	if err := r.Client.List(o.ctx, workPlacementList, client.MatchingLabels{targetDestinationNameLabel: destination.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var remaining []v1alpha1.WorkPlacement
+	for _, wp := range workPlacementList.Items {
+		if wp.Spec.ResourceName != "" && wp.DeletionTimestamp.IsZero() {
+			remaining = append(remaining, wp)
+		}
+	}
+	sort.Slice(remaining, func(i, j int) bool {
+		return remaining[i].Name < remaining[j].Name
+	})
+
+	if !destination.Spec.Drain {
+		for _, wp := range remaining {
+			if _, draining := wp.GetLabels()[drainingLabel]; draining {
+				o.logger.Info("destination is no longer draining; keeping workplacement on destination", "workPlacementName", wp.Name, "namespace", wp.Namespace)
+				delete(wp.Labels, drainingLabel)
+				if err := r.Client.Update(o.ctx, &wp); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+		}
+		return ctrl.Result{}, r.updateDrainStatus(o, destination, nil)
+	}
+
+	status := &v1alpha1.DrainStatus{Phase: v1alpha1.DrainPhaseDrained, Remaining: len(remaining)}
+	if len(remaining) == 0 {
+		o.logger.Info("destination is drained")
+		return ctrl.Result{}, r.updateDrainStatus(o, destination, status)
+	}
+
+	status.Phase = v1alpha1.DrainPhaseDraining
+	for _, wp := range remaining {
+		if _, draining := wp.GetLabels()[drainingLabel]; draining {
+			status.Migrating = wp.Name
+			break
+		}
+	}
+
+	if status.Migrating == "" {
+		next := remaining[0]
+		o.logger.Info("moving workplacement off draining destination", "workPlacementName", next.Name, "namespace", next.Namespace)
+		if next.Labels == nil {
+			next.Labels = map[string]string{}
+		}
+		next.Labels[drainingLabel] = "true"
+		if err := r.Client.Update(o.ctx, &next); err != nil {
+			return ctrl.Result{}, err
+		}
+		status.Migrating = next.Name
+	}
+
+	return defaultRequeue, r.updateDrainStatus(o, destination, status)
+}
+
+func (r *DestinationReconciler) updateDrainStatus(o opts, destination *v1alpha1.Destination, status *v1alpha1.DrainStatus) error {
+	if reflect.DeepEqual(destination.Status.Drain, status) {
+		return nil
+	}
+	destination.Status.Drain = status
+	return r.Client.Status().Update(o.ctx, destination)
+}
+
+// Returns the WorkPlacements that are being moved off a draining Destination
+func drainingWorkPlacements(workPlacements []v1alpha1.WorkPlacement) []v1alpha1.WorkPlacement {
+	var draining []v1alpha1.WorkPlacement
+	for _, wp := range workPlacements {
+		if _, found := wp.GetLabels()[drainingLabel]; found {
+			draining = append(draining, wp)
+		}
+	}
+	return draining
+}
+
+// Returns the Destinations new works can be scheduled to: those that are not
+// cordoned and have a StateStore to write the works to
+func schedulableDestinations(destinations []v1alpha1.Destination) []v1alpha1.Destination {
+	var schedulable []v1alpha1.Destination
+	for _, destination := range destinations {
+		if !destination.IsCordoned() && destination.Spec.StateStoreRef != nil {
+			schedulable = append(schedulable, destination)
+		}
+	}
+	return schedulable
+}
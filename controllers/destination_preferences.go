@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"github.com/syntasso/kratix/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Returns the preferred selectors from all sources of the WorkloadGroup. The
+// weights of every matching selector are added up, whatever their source.
+func resolvePreferredSelectorsForWorkloadGroup(workloadGroup v1alpha1.WorkloadGroup) []v1alpha1.PreferredDestinationSelector {
+	var preferred []v1alpha1.PreferredDestinationSelector
+	for _, scheduling := range workloadGroup.DestinationSelectors {
+		preferred = append(preferred, scheduling.Preferred...)
+	}
+	return preferred
+}
+
+// Returns the Destinations with the highest total weight of matching preferred
+// selectors. All Destinations are returned when there are no preferred
+// selectors, or none of them match.
+func preferHighestScoredDestinations(destinations []v1alpha1.Destination, preferred []v1alpha1.PreferredDestinationSelector) []v1alpha1.Destination {
+	if len(preferred) == 0 {
+		return destinations
+	}
+
+	var highest []v1alpha1.Destination
+	highestScore := -1
+	for _, destination := range destinations {
+		score := scoreDestination(destination, preferred)
+		if score > highestScore {
+			highest = nil
+			highestScore = score
+		}
+		if score == highestScore {
+			highest = append(highest, destination)
+		}
+	}
+	return highest
+}
+
+// Preferred selectors with invalid match expressions never match
+func scoreDestination(destination v1alpha1.Destination, preferred []v1alpha1.PreferredDestinationSelector) int {
+	var score int
+	for _, selector := range preferred {
+		labelSelector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
+			MatchLabels:      selector.MatchLabels,
+			MatchExpressions: selector.MatchExpressions,
+		})
+		if err != nil {
+			continue
+		}
+		if labelSelector.Matches(labels.Set(destination.GetLabels())) {
+			score += int(selector.Weight)
+		}
+	}
+	return score
+}
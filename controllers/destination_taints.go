@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Returns the tolerations from all sources of the WorkloadGroup. A taint is
+// tolerated if any source tolerates it.
+func resolveTolerationsForWorkloadGroup(workloadGroup v1alpha1.WorkloadGroup) []v1alpha1.Toleration {
+	var tolerations []v1alpha1.Toleration
+	for _, scheduling := range workloadGroup.DestinationSelectors {
+		tolerations = append(tolerations, scheduling.Tolerations...)
+	}
+	return tolerations
+}
+
+// Returns the Destinations that works with the given tolerations can be
+// scheduled to
+func filterDestinationsByTaints(destinations []v1alpha1.Destination, tolerations []v1alpha1.Toleration) []v1alpha1.Destination {
+	var schedulable []v1alpha1.Destination
+	for _, destination := range destinations {
+		if len(destination.UntoleratedTaints(tolerations, v1alpha1.TaintEffectNoSchedule, v1alpha1.TaintEffectNoExecute)) == 0 {
+			schedulable = append(schedulable, destination)
+		}
+	}
+	return schedulable
+}
+
+// Returns the Destinations without PreferNoSchedule taints that are not
+// tolerated, or all Destinations if every one of them has such a taint
+func preferDestinationsWithoutTaints(destinations []v1alpha1.Destination, tolerations []v1alpha1.Toleration) []v1alpha1.Destination {
+	var preferred []v1alpha1.Destination
+	for _, destination := range destinations {
+		if len(destination.UntoleratedTaints(tolerations, v1alpha1.TaintEffectPreferNoSchedule)) == 0 {
+			preferred = append(preferred, destination)
+		}
+	}
+
+	if len(preferred) == 0 {
+		return destinations
+	}
+	return preferred
+}
+
+// Splits the WorkPlacements into those that can stay on their Destination and
+// those that must be evicted, as their Destination has a NoExecute taint that
+// is not tolerated
+func (s *Scheduler) partitionEvictedWorkPlacements(workPlacements []v1alpha1.WorkPlacement, tolerations []v1alpha1.Toleration, destinations *destinationCache) ([]v1alpha1.WorkPlacement, []v1alpha1.WorkPlacement, error) {
+	var kept, evicted []v1alpha1.WorkPlacement
+	for _, wp := range workPlacements {
+		destination, err := destinations.get(wp.Spec.TargetDestinationName)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if destination != nil && len(destination.UntoleratedTaints(tolerations, v1alpha1.TaintEffectNoExecute)) > 0 {
+			evicted = append(evicted, wp)
+			continue
+		}
+		kept = append(kept, wp)
+	}
+	return kept, evicted, nil
+}
+
+// Deletes the WorkPlacements of dependencies from Destinations with NoExecute
+// taints they do not tolerate. Resource WorkPlacements are instead moved to
+// another Destination, like misscheduled ones.
+func (s *Scheduler) evictWorkPlacements(workPlacements []v1alpha1.WorkPlacement) error {
+	for _, wp := range workPlacements {
+		s.Log.Info("evicting workplacement from destination with NoExecute taint", "workPlacementName", wp.Name, "namespace", wp.Namespace, "destination", wp.Spec.TargetDestinationName)
+		if err := s.Client.Delete(context.Background(), &wp); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2021 Syntasso.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const destinationGroupCleanupFinalizer = v1alpha1.KratixPrefix + "destination-group-cleanup"
+
+// DestinationGroupReconciler reconciles a DestinationGroup object
+type DestinationGroupReconciler struct {
+	Client client.Client
+	Log    logr.Logger
+}
+
+// destinationGroupStamp records what a DestinationGroup stamped onto a member,
+// in an annotation on the member, so that exactly that is removed when the
+// member leaves the group or the group changes
+type destinationGroupStamp struct {
+	Labels            map[string]string             `json:"labels,omitempty"`
+	StateStoreRef     *v1alpha1.StateStoreReference `json:"stateStoreRef,omitempty"`
+	Taints            []v1alpha1.DestinationTaint   `json:"taints,omitempty"`
+	StrictMatchLabels bool                          `json:"strictMatchLabels,omitempty"`
+}
+
+//+kubebuilder:rbac:groups=platform.kratix.io,resources=destinationgroups,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=platform.kratix.io,resources=destinationgroups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=platform.kratix.io,resources=destinationgroups/finalizers,verbs=update
+
+func (r *DestinationGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Log.WithValues("destinationGroup", req.Name)
+
+	group := &v1alpha1.DestinationGroup{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: req.Name}, group); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	o := opts{
+		client: r.Client,
+		ctx:    ctx,
+		logger: logger,
+	}
+
+	if !group.DeletionTimestamp.IsZero() {
+		return r.deleteDestinationGroup(o, group)
+	}
+
+	if !controllerutil.ContainsFinalizer(group, destinationGroupCleanupFinalizer) {
+		return addFinalizers(o, group, []string{destinationGroupCleanupFinalizer})
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&group.Spec.Selector)
+	if err != nil {
+		logger.Error(err, "invalid selector; destination group will have no members")
+		selector = labels.Nothing()
+	}
+
+	destinationList := &v1alpha1.DestinationList{}
+	if err := r.Client.List(ctx, destinationList); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var members []v1alpha1.Destination
+	for _, destination := range destinationList.Items {
+		original := destination.DeepCopy()
+
+		unstampDestinationGroup(&destination, group.Name)
+		// labels stamped by the group were removed above, so they don't
+		// affect whether the destination is a member
+		if selector.Matches(labels.Set(destination.GetLabels())) {
+			if err := stampDestinationGroup(&destination, group); err != nil {
+				return ctrl.Result{}, err
+			}
+			members = append(members, destination)
+		}
+
+		if !equality.Semantic.DeepEqual(original.ObjectMeta, destination.ObjectMeta) ||
+			!equality.Semantic.DeepEqual(original.Spec, destination.Spec) {
+			logger.Info("updating destination group stamp on destination", "destination", destination.Name)
+			if err := r.Client.Update(ctx, &destination); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	if err := r.updateStatus(o, group, members); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// member health also depends on their WorkPlacements, which aren't watched
+	return slowRequeue, nil
+}
+
+func (r *DestinationGroupReconciler) updateStatus(o opts, group *v1alpha1.DestinationGroup, members []v1alpha1.Destination) error {
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Name < members[j].Name
+	})
+
+	status := v1alpha1.DestinationGroupStatus{MemberCount: len(members)}
+	for _, destination := range members {
+		member, err := r.memberHealth(o, destination)
+		if err != nil {
+			return err
+		}
+		if member.Health == v1alpha1.DestinationGroupMemberReady {
+			status.ReadyCount++
+		}
+		status.Members = append(status.Members, member)
+	}
+
+	if reflect.DeepEqual(group.Status, status) {
+		return nil
+	}
+	group.Status = status
+	return r.Client.Status().Update(o.ctx, group)
+}
+
+func (r *DestinationGroupReconciler) memberHealth(o opts, destination v1alpha1.Destination) (v1alpha1.DestinationGroupMember, error) {
+	member := v1alpha1.DestinationGroupMember{Name: destination.Name}
+	switch {
+	case !destination.DeletionTimestamp.IsZero():
+		member.Health = v1alpha1.DestinationGroupMemberDeleting
+		return member, nil
+	case destination.IsCordoned():
+		member.Health = v1alpha1.DestinationGroupMemberCordoned
+		return member, nil
+	case destination.Spec.StateStoreRef == nil:
+		member.Health = v1alpha1.DestinationGroupMemberNotReady
+		member.Message = "destination has no stateStoreRef"
+		return member, nil
+	}
+
+	workPlacementList := &v1alpha1.WorkPlacementList{}
+	if err := r.Client.List(o.ctx, workPlacementList, client.MatchingLabels{targetDestinationNameLabel: destination.Name}); err != nil {
+		return member, err
+	}
+
+	var failed int
+	for _, wp := range workPlacementList.Items {
+		if meta.IsStatusConditionFalse(wp.Status.Conditions, writeSucceededConditionType) {
+			failed++
+		}
+	}
+
+	member.Health = v1alpha1.DestinationGroupMemberReady
+	if failed > 0 {
+		member.Health = v1alpha1.DestinationGroupMemberNotReady
+		member.Message = fmt.Sprintf("%d workplacements failed to be written to the destination", failed)
+	}
+	return member, nil
+}
+
+func (r *DestinationGroupReconciler) deleteDestinationGroup(o opts, group *v1alpha1.DestinationGroup) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(group, destinationGroupCleanupFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	destinationList := &v1alpha1.DestinationList{}
+	if err := r.Client.List(o.ctx, destinationList, client.HasLabels{v1alpha1.DestinationGroupLabel(group.Name)}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	for _, destination := range destinationList.Items {
+		o.logger.Info("removing destination group stamp from destination", "destination", destination.Name)
+		unstampDestinationGroup(&destination, group.Name)
+		if err := r.Client.Update(o.ctx, &destination); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(group, destinationGroupCleanupFinalizer)
+	return ctrl.Result{}, r.Client.Update(o.ctx, group)
+}
+
+// Removes the group's membership label, and the labels, stateStoreRef and
+// scheduling policy it stamped onto the destination that the destination
+// hasn't changed since
+func unstampDestinationGroup(destination *v1alpha1.Destination, groupName string) {
+	key := v1alpha1.DestinationGroupLabel(groupName)
+	delete(destination.Labels, key)
+
+	value, found := destination.Annotations[key]
+	if !found {
+		return
+	}
+	delete(destination.Annotations, key)
+
+	stamp := destinationGroupStamp{}
+	if err := json.Unmarshal([]byte(value), &stamp); err != nil {
+		return
+	}
+
+	for label, stampedValue := range stamp.Labels {
+		if destination.Labels[label] == stampedValue {
+			delete(destination.Labels, label)
+		}
+	}
+
+	if stamp.StateStoreRef != nil && reflect.DeepEqual(destination.Spec.StateStoreRef, stamp.StateStoreRef) {
+		destination.Spec.StateStoreRef = nil
+	}
+
+	var taints []v1alpha1.DestinationTaint
+	for _, taint := range destination.Spec.Taints {
+		if !slices.Contains(stamp.Taints, taint) {
+			taints = append(taints, taint)
+		}
+	}
+	destination.Spec.Taints = taints
+
+	if stamp.StrictMatchLabels {
+		destination.Spec.StrictMatchLabels = false
+	}
+}
+
+// Stamps the group's membership label, and its labels, stateStoreRef and
+// scheduling policy where the destination doesn't set its own, onto the
+// destination
+func stampDestinationGroup(destination *v1alpha1.Destination, group *v1alpha1.DestinationGroup) error {
+	if destination.Labels == nil {
+		destination.Labels = map[string]string{}
+	}
+	if destination.Annotations == nil {
+		destination.Annotations = map[string]string{}
+	}
+
+	stamp := destinationGroupStamp{}
+	for label, value := range group.Spec.Labels {
+		if _, set := destination.Labels[label]; !set {
+			destination.Labels[label] = value
+			if stamp.Labels == nil {
+				stamp.Labels = map[string]string{}
+			}
+			stamp.Labels[label] = value
+		}
+	}
+
+	if destination.Spec.StateStoreRef == nil && group.Spec.StateStoreRef != nil {
+		destination.Spec.StateStoreRef = group.Spec.StateStoreRef.DeepCopy()
+		stamp.StateStoreRef = group.Spec.StateStoreRef.DeepCopy()
+	}
+
+	if scheduling := group.Spec.Scheduling; scheduling != nil {
+		for _, taint := range scheduling.Taints {
+			if !slices.ContainsFunc(destination.Spec.Taints, func(t v1alpha1.DestinationTaint) bool {
+				return t.Key == taint.Key && t.Effect == taint.Effect
+			}) {
+				destination.Spec.Taints = append(destination.Spec.Taints, taint)
+				stamp.Taints = append(stamp.Taints, taint)
+			}
+		}
+
+		if scheduling.StrictMatchLabels && !destination.Spec.StrictMatchLabels {
+			destination.Spec.StrictMatchLabels = true
+			stamp.StrictMatchLabels = true
+		}
+	}
+
+	stampJSON, err := json.Marshal(stamp)
+	if err != nil {
+		return err
+	}
+
+	key := v1alpha1.DestinationGroupLabel(group.Name)
+	destination.Labels[key] = "true"
+	destination.Annotations[key] = string(stampJSON)
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DestinationGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.DestinationGroup{}).
+		Watches(
+			&v1alpha1.Destination{},
+			handler.EnqueueRequestsFromMapFunc(r.requestReconcilationOfDestinationGroups),
+		).
+		Complete(r)
+}
+
+// Any Destination change can change the members of any DestinationGroup or
+// their health
+func (r *DestinationGroupReconciler) requestReconcilationOfDestinationGroups(ctx context.Context, _ client.Object) []reconcile.Request {
+	groupList := &v1alpha1.DestinationGroupList{}
+	if err := r.Client.List(ctx, groupList); err != nil {
+		r.Log.Error(err, "Error listing DestinationGroups")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, group := range groupList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&group)})
+	}
+	return requests
+}
@@ -0,0 +1,166 @@
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"github.com/syntasso/kratix/controllers"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("DestinationGroupReconciler", func() {
+	var (
+		ctx        context.Context
+		reconciler *controllers.DestinationGroupReconciler
+		group      *v1alpha1.DestinationGroup
+		groupName  = types.NamespacedName{Name: "edge"}
+		memberKey  = v1alpha1.DestinationGroupLabel("edge")
+		stateStore = &v1alpha1.StateStoreReference{Kind: "BucketStateStore", Name: "edge-store"}
+		edgeTaint  = v1alpha1.DestinationTaint{Key: "edge", Effect: v1alpha1.TaintEffectPreferNoSchedule}
+	)
+
+	reconcile := func() {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: groupName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeK8sClient.Get(ctx, groupName, group)).To(Succeed())
+	}
+
+	getDestination := func(name string) *v1alpha1.Destination {
+		destination := &v1alpha1.Destination{}
+		Expect(fakeK8sClient.Get(ctx, types.NamespacedName{Name: name}, destination)).To(Succeed())
+		return destination
+	}
+
+	createDestination := func(name string, labels map[string]string, stateStoreRef *v1alpha1.StateStoreReference) {
+		Expect(fakeK8sClient.Create(ctx, &v1alpha1.Destination{
+			ObjectMeta: v1.ObjectMeta{Name: name, Labels: labels},
+			Spec:       v1alpha1.DestinationSpec{StateStoreRef: stateStoreRef},
+		})).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		reconciler = &controllers.DestinationGroupReconciler{
+			Client: fakeK8sClient,
+			Log:    ctrl.Log.WithName("controllers").WithName("DestinationGroup"),
+		}
+
+		createDestination("edge-1", map[string]string{"fleet": "edge"}, nil)
+		createDestination("edge-2", map[string]string{"fleet": "edge", "environment": "staging"}, &v1alpha1.StateStoreReference{Kind: "GitStateStore", Name: "own-store"})
+		createDestination("core", map[string]string{"fleet": "core"}, nil)
+
+		group = &v1alpha1.DestinationGroup{
+			ObjectMeta: v1.ObjectMeta{Name: groupName.Name},
+			Spec: v1alpha1.DestinationGroupSpec{
+				Selector:      v1.LabelSelector{MatchLabels: map[string]string{"fleet": "edge"}},
+				Labels:        map[string]string{"environment": "prod", "region": "eu"},
+				StateStoreRef: stateStore,
+				Scheduling: &v1alpha1.DestinationGroupScheduling{
+					Taints:            []v1alpha1.DestinationTaint{edgeTaint},
+					StrictMatchLabels: true,
+				},
+			},
+		}
+		Expect(fakeK8sClient.Create(ctx, group)).To(Succeed())
+
+		reconcile()
+		Expect(group.GetFinalizers()).To(ContainElement("kratix.io/destination-group-cleanup"))
+		reconcile()
+	})
+
+	It("stamps the group onto the member Destinations without overwriting what they set", func() {
+		edge1 := getDestination("edge-1")
+		Expect(edge1.GetLabels()).To(Equal(map[string]string{
+			"fleet": "edge", "environment": "prod", "region": "eu", memberKey: "true",
+		}))
+		Expect(edge1.Spec.StateStoreRef).To(Equal(stateStore))
+		Expect(edge1.Spec.Taints).To(ConsistOf(edgeTaint))
+		Expect(edge1.Spec.StrictMatchLabels).To(BeTrue())
+
+		edge2 := getDestination("edge-2")
+		Expect(edge2.GetLabels()).To(HaveKeyWithValue("environment", "staging"))
+		Expect(edge2.GetLabels()).To(HaveKeyWithValue("region", "eu"))
+		Expect(edge2.Spec.StateStoreRef.Name).To(Equal("own-store"))
+
+		core := getDestination("core")
+		Expect(core.GetLabels()).To(Equal(map[string]string{"fleet": "core"}))
+		Expect(core.Spec.StateStoreRef).To(BeNil())
+		Expect(core.Spec.Taints).To(BeEmpty())
+	})
+
+	It("lists the members and their health on the group status", func() {
+		Expect(group.Status.MemberCount).To(Equal(2))
+		Expect(group.Status.ReadyCount).To(Equal(2))
+		Expect(group.Status.Members).To(Equal([]v1alpha1.DestinationGroupMember{
+			{Name: "edge-1", Health: v1alpha1.DestinationGroupMemberReady},
+			{Name: "edge-2", Health: v1alpha1.DestinationGroupMemberReady},
+		}))
+
+		wp := &v1alpha1.WorkPlacement{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "failing",
+				Namespace: "default",
+				Labels:    map[string]string{"kratix.io/targetDestinationName": "edge-1"},
+			},
+			Spec: v1alpha1.WorkPlacementSpec{TargetDestinationName: "edge-1"},
+		}
+		Expect(fakeK8sClient.Create(ctx, wp)).To(Succeed())
+		meta.SetStatusCondition(&wp.Status.Conditions, v1.Condition{Type: "WriteSucceeded", Status: v1.ConditionFalse, Reason: "WorkloadsFailedWrite"})
+		Expect(fakeK8sClient.Status().Update(ctx, wp)).To(Succeed())
+
+		edge2 := getDestination("edge-2")
+		edge2.Spec.Cordoned = true
+		Expect(fakeK8sClient.Update(ctx, edge2)).To(Succeed())
+
+		reconcile()
+		Expect(group.Status.ReadyCount).To(Equal(0))
+		Expect(group.Status.Members).To(Equal([]v1alpha1.DestinationGroupMember{
+			{Name: "edge-1", Health: v1alpha1.DestinationGroupMemberNotReady, Message: "1 workplacements failed to be written to the destination"},
+			{Name: "edge-2", Health: v1alpha1.DestinationGroupMemberCordoned},
+		}))
+	})
+
+	It("removes the stamp from Destinations that leave the group", func() {
+		edge1 := getDestination("edge-1")
+		edge1.Labels["fleet"] = "core"
+		Expect(fakeK8sClient.Update(ctx, edge1)).To(Succeed())
+
+		reconcile()
+		edge1 = getDestination("edge-1")
+		Expect(edge1.GetLabels()).To(Equal(map[string]string{"fleet": "core"}))
+		Expect(edge1.GetAnnotations()).NotTo(HaveKey(memberKey))
+		Expect(edge1.Spec.StateStoreRef).To(BeNil())
+		Expect(edge1.Spec.Taints).To(BeEmpty())
+		Expect(edge1.Spec.StrictMatchLabels).To(BeFalse())
+		Expect(group.Status.MemberCount).To(Equal(1))
+	})
+
+	It("updates the stamp when the group changes", func() {
+		group.Spec.Labels = map[string]string{"region": "us"}
+		group.Spec.Scheduling = nil
+		Expect(fakeK8sClient.Update(ctx, group)).To(Succeed())
+
+		reconcile()
+		edge1 := getDestination("edge-1")
+		Expect(edge1.GetLabels()).To(Equal(map[string]string{"fleet": "edge", "region": "us", memberKey: "true"}))
+		Expect(edge1.Spec.Taints).To(BeEmpty())
+		Expect(edge1.Spec.StrictMatchLabels).To(BeFalse())
+	})
+
+	It("removes the stamp from all members when the group is deleted", func() {
+		Expect(fakeK8sClient.Delete(ctx, group)).To(Succeed())
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: groupName})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeK8sClient.Get(ctx, groupName, group)).To(MatchError(ContainSubstring("not found")))
+		edge2 := getDestination("edge-2")
+		Expect(edge2.GetLabels()).To(Equal(map[string]string{"fleet": "edge", "environment": "staging"}))
+		Expect(edge2.Spec.StateStoreRef.Name).To(Equal("own-store"))
+		Expect(edge2.Spec.Taints).To(BeEmpty())
+	})
+})
@@ -22,6 +22,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"fmt"
+	"reflect"
 
 	"github.com/go-logr/logr"
 	"github.com/syntasso/kratix/api/v1alpha1"
@@ -41,6 +42,9 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const workFinalizer = v1alpha1.KratixPrefix + "work-cleanup"
@@ -162,6 +166,14 @@ func (r *DynamicResourceRequestController) Reconcile(ctx context.Context, req ct
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcileResourceRequestScheduling(opts, promise, rr); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if updated, err := r.updateDestinationsStatus(opts, rr); err != nil || updated {
+		return ctrl.Result{}, err
+	}
+
 	if rr.GetGeneration() != resourceutil.GetObservedGeneration(rr) {
 		resourceutil.SetStatus(rr, logger, "observedGeneration", rr.GetGeneration())
 		return ctrl.Result{}, opts.client.Status().Update(opts.ctx, rr)
@@ -186,6 +198,15 @@ func (r *DynamicResourceRequestController) deleteResources(o opts, promise *v1al
 		return ctrl.Result{}, nil
 	}
 
+	deletionPolicy := promise.GetDeletionPolicy(resourceRequest)
+	if controllerutil.ContainsFinalizer(resourceRequest, runDeleteWorkflowsFinalizer) && deletionPolicy == v1alpha1.DeletionPolicyOrphan {
+		// delete workflows usually deprovision what the resource's workloads
+		// created, which orphaning is meant to keep
+		o.logger.Info("skipping delete workflows of resource request being orphaned")
+		controllerutil.RemoveFinalizer(resourceRequest, runDeleteWorkflowsFinalizer)
+		return ctrl.Result{}, o.client.Update(o.ctx, resourceRequest)
+	}
+
 	if controllerutil.ContainsFinalizer(resourceRequest, runDeleteWorkflowsFinalizer) {
 		pipelineResources, err := promise.GenerateResourcePipelines(v1alpha1.WorkflowActionDelete, resourceRequest, o.logger)
 		if err != nil {
@@ -209,7 +230,7 @@ func (r *DynamicResourceRequestController) deleteResources(o opts, promise *v1al
 	}
 
 	if controllerutil.ContainsFinalizer(resourceRequest, workFinalizer) {
-		err := r.deleteWork(o, resourceRequest, resourceRequestIdentifier, workFinalizer)
+		err := r.deleteWork(o, resourceRequest, resourceRequestIdentifier, workFinalizer, deletionPolicy)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
@@ -227,7 +248,86 @@ func (r *DynamicResourceRequestController) deleteResources(o opts, promise *v1al
 	return fastRequeue, nil
 }
 
-func (r *DynamicResourceRequestController) deleteWork(o opts, resourceRequest *unstructured.Unstructured, workName string, finalizer string) error {
+// Keeps the scheduling the resource request sets with its
+// kratix.io/destination-selectors annotation up to date on its Works, so
+// changing the annotation doesn't require the workflows to run again
+func (r *DynamicResourceRequestController) reconcileResourceRequestScheduling(o opts, promise *v1alpha1.Promise, resourceRequest *unstructured.Unstructured) error {
+	scheduling, err := promise.GetResourceRequestScheduling(resourceRequest)
+	if err != nil {
+		return err
+	}
+
+	works, err := resourceutil.GetAllWorksForResource(r.Client, resourceRequest.GetNamespace(), r.PromiseIdentifier, resourceRequest.GetName())
+	if err != nil {
+		return err
+	}
+
+	for i := range works {
+		if works[i].SetResourceRequestScheduling(scheduling) {
+			o.logger.Info("updating resource request destination selectors on Work", "work", works[i].GetName())
+			if err := r.Client.Update(o.ctx, &works[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Sets status.destinations on the resource request to the write status of
+// its Works on each Destination, returning whether the status was updated
+func (r *DynamicResourceRequestController) updateDestinationsStatus(o opts, resourceRequest *unstructured.Unstructured) (bool, error) {
+	works, err := resourceutil.GetAllWorksForResource(r.Client, resourceRequest.GetNamespace(), r.PromiseIdentifier, resourceRequest.GetName())
+	if err != nil {
+		return false, err
+	}
+
+	var destinations []interface{}
+	for _, destination := range v1alpha1.SummariseDestinations(works) {
+		value, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&destination)
+		if err != nil {
+			return false, err
+		}
+		destinations = append(destinations, value)
+	}
+
+	current, _, err := unstructured.NestedSlice(resourceRequest.Object, "status", "destinations")
+	if err != nil {
+		return false, err
+	}
+	if reflect.DeepEqual(current, destinations) {
+		return false, nil
+	}
+
+	o.logger.Info("updating destinations status", "destinations", destinations)
+	if destinations == nil {
+		unstructured.RemoveNestedField(resourceRequest.Object, "status", "destinations")
+	} else {
+		resourceutil.SetStatus(resourceRequest, o.logger, "destinations", destinations)
+	}
+	return true, r.Client.Status().Update(o.ctx, resourceRequest)
+}
+
+// Requests reconciliation of the resource request a Work belongs to
+func (r *DynamicResourceRequestController) requestReconciliationOfResourceRequest(_ context.Context, obj client.Object) []reconcile.Request {
+	work := obj.(*v1alpha1.Work)
+	if !work.IsResourceRequest() || work.Spec.PromiseName != r.PromiseIdentifier {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: work.GetNamespace(), Name: work.Spec.ResourceName}}}
+}
+
+// Works only affect the status of the resource request or Promise they
+// belong to when the write status of their placements changes
+var workPlacementsChangedPredicate = predicate.Funcs{
+	CreateFunc: func(event.CreateEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return !reflect.DeepEqual(e.ObjectOld.(*v1alpha1.Work).Status.Placements, e.ObjectNew.(*v1alpha1.Work).Status.Placements)
+	},
+	DeleteFunc:  func(event.DeleteEvent) bool { return true },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+func (r *DynamicResourceRequestController) deleteWork(o opts, resourceRequest *unstructured.Unstructured, workName string, finalizer string, deletionPolicy v1alpha1.DeletionPolicy) error {
 	works, err := resourceutil.GetAllWorksForResource(r.Client, resourceRequest.GetNamespace(), r.PromiseIdentifier, resourceRequest.GetName())
 	if err != nil {
 		return err
@@ -243,6 +343,11 @@ func (r *DynamicResourceRequestController) deleteWork(o opts, resourceRequest *u
 	}
 
 	for _, work := range works {
+		if deletionPolicy == v1alpha1.DeletionPolicyOrphan {
+			if err := r.orphanWork(o, &work); err != nil {
+				return err
+			}
+		}
 		err = r.Client.Delete(o.ctx, &work)
 		if err != nil {
 			if !errors.IsNotFound(err) {
@@ -256,6 +361,24 @@ func (r *DynamicResourceRequestController) deleteWork(o opts, resourceRequest *u
 	return nil
 }
 
+// Marks the Work to be orphaned before it is deleted. The Scheduler copies the
+// mark onto the WorkPlacements it creates, and the Work controller onto every
+// WorkPlacement of the Work before deleting them, so a WorkPlacement created
+// while the Work is being deleted is orphaned too.
+func (r *DynamicResourceRequestController) orphanWork(o opts, work *v1alpha1.Work) error {
+	if v1alpha1.Orphaned(work) {
+		return nil
+	}
+	annotations := work.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[v1alpha1.DeletionPolicyAnnotation] = string(v1alpha1.DeletionPolicyOrphan)
+	work.SetAnnotations(annotations)
+	o.logger.Info("orphaning work", "work", work.GetName())
+	return r.Client.Update(o.ctx, work)
+}
+
 func (r *DynamicResourceRequestController) deleteWorkflows(o opts, resourceRequest *unstructured.Unstructured, resourceRequestIdentifier, finalizer string) error {
 	jobGVK := schema.GroupVersionKind{
 		Group:   batchv1.SchemeGroupVersion.Group,
@@ -21,10 +21,12 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/syntasso/kratix/api/v1alpha1"
 	"github.com/syntasso/kratix/controllers"
 	"github.com/syntasso/kratix/lib/resourceutil"
+	"github.com/syntasso/kratix/lib/workflow"
 )
 
 var _ = Describe("DynamicResourceRequestController", func() {
@@ -128,6 +130,11 @@ var _ = Describe("DynamicResourceRequestController", func() {
 						APIGroups: []string{"platform.kratix.io"},
 						Resources: []string{"works"},
 					},
+					rbacv1.PolicyRule{
+						Verbs:     []string{"get", "create"},
+						APIGroups: []string{""},
+						Resources: []string{"configmaps"},
+					},
 				))
 				Expect(role.GetLabels()).To(Equal(resourceLabels))
 			})
@@ -259,6 +266,154 @@ var _ = Describe("DynamicResourceRequestController", func() {
 			Expect(works.Items).To(HaveLen(0))
 			Expect(jobs.Items).To(HaveLen(0))
 		})
+
+		It("does not run the delete workflows when the deletion policy is Orphan", func() {
+			deleteWorkflowRuns := 0
+			controllers.SetReconcileDeleteWorkflow(func(workflow.Opts) (bool, error) {
+				deleteWorkflowRuns++
+				return true, nil
+			})
+
+			resReq.SetAnnotations(map[string]string{"kratix.io/deletion-policy": "Orphan"})
+			Expect(fakeK8sClient.Update(ctx, resReq)).To(Succeed())
+			Expect(fakeK8sClient.Delete(ctx, resReq)).To(Succeed())
+			result, err := t.reconcileUntilCompletion(reconciler, resReq)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+
+			Expect(deleteWorkflowRuns).To(BeZero())
+			Expect(fakeK8sClient.Get(ctx, resReqNameNamespace, resReq)).To(MatchError(ContainSubstring("not found")))
+		})
+
+		It("marks the Works to be orphaned when the deletion policy is Orphan", func() {
+			work := &v1alpha1.Work{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "rr-work",
+					Namespace: resReq.GetNamespace(),
+					Labels: map[string]string{
+						"kratix.io/promise-name":  promise.GetName(),
+						"kratix.io/resource-name": resReq.GetName(),
+					},
+					// kept until the Work controller orphans its WorkPlacements
+					Finalizers: []string{"kratix.io/work-cleanup"},
+				},
+				Spec: v1alpha1.WorkSpec{PromiseName: promise.GetName(), ResourceName: resReq.GetName()},
+			}
+			Expect(fakeK8sClient.Create(ctx, work)).To(Succeed())
+
+			resReq.SetAnnotations(map[string]string{"kratix.io/deletion-policy": "Orphan"})
+			Expect(fakeK8sClient.Update(ctx, resReq)).To(Succeed())
+			Expect(fakeK8sClient.Delete(ctx, resReq)).To(Succeed())
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: resReqNameNamespace})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: resReqNameNamespace})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeK8sClient.Get(ctx, client.ObjectKeyFromObject(work), work)).To(Succeed())
+			Expect(work.GetDeletionTimestamp()).NotTo(BeNil())
+			Expect(work.GetAnnotations()).To(HaveKeyWithValue("kratix.io/deletion-policy", "Orphan"))
+		})
+	})
+
+	Describe("Resource request destination selectors", func() {
+		var work *v1alpha1.Work
+
+		setAnnotation := func(value string) {
+			Expect(fakeK8sClient.Get(ctx, resReqNameNamespace, resReq)).To(Succeed())
+			annotations := resReq.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations["kratix.io/destination-selectors"] = value
+			if value == "" {
+				delete(annotations, "kratix.io/destination-selectors")
+			}
+			resReq.SetAnnotations(annotations)
+			Expect(fakeK8sClient.Update(ctx, resReq)).To(Succeed())
+		}
+
+		reconcile := func() {
+			_, err := t.reconcileUntilCompletion(reconciler, resReq)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeK8sClient.Get(ctx, client.ObjectKeyFromObject(work), work)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			setReconcileConfigureWorkflowToReturnFinished()
+			work = &v1alpha1.Work{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "rr-work",
+					Namespace: resReq.GetNamespace(),
+					Labels: map[string]string{
+						"kratix.io/promise-name":  promise.GetName(),
+						"kratix.io/resource-name": resReq.GetName(),
+					},
+				},
+				Spec: v1alpha1.WorkSpec{
+					PromiseName:  promise.GetName(),
+					ResourceName: resReq.GetName(),
+					WorkloadGroups: []v1alpha1.WorkloadGroup{{
+						ID:        "default",
+						Directory: ".",
+						DestinationSelectors: []v1alpha1.WorkloadGroupScheduling{
+							{MatchLabels: map[string]string{"environment": "dev"}, Source: "promise"},
+						},
+					}},
+				},
+			}
+			Expect(fakeK8sClient.Create(ctx, work)).To(Succeed())
+		})
+
+		When("the Promise allows resource requests to set destination selectors", func() {
+			BeforeEach(func() {
+				Expect(fakeK8sClient.Get(ctx, promiseName, promise)).To(Succeed())
+				promise.Spec.SchedulingPolicy.AllowResourceRequestDestinationSelectors = true
+				Expect(fakeK8sClient.Update(ctx, promise)).To(Succeed())
+			})
+
+			It("keeps the selectors set by the annotation up to date on the Works", func() {
+				setAnnotation(`{"matchLabels": {"region": "eu-west"}}`)
+				reconcile()
+				Expect(work.Spec.WorkloadGroups[0].DestinationSelectors).To(ConsistOf(
+					v1alpha1.WorkloadGroupScheduling{MatchLabels: map[string]string{"environment": "dev"}, Source: "promise"},
+					v1alpha1.WorkloadGroupScheduling{MatchLabels: map[string]string{"region": "eu-west"}, Source: "resource-request"},
+				))
+
+				setAnnotation("matchExpressions: [{key: region, operator: In, values: [us-east]}]")
+				reconcile()
+				Expect(work.Spec.WorkloadGroups[0].DestinationSelectors).To(ConsistOf(
+					v1alpha1.WorkloadGroupScheduling{MatchLabels: map[string]string{"environment": "dev"}, Source: "promise"},
+					v1alpha1.WorkloadGroupScheduling{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{Key: "region", Operator: metav1.LabelSelectorOpIn, Values: []string{"us-east"}},
+						},
+						Source: "resource-request",
+					},
+				))
+
+				setAnnotation("")
+				reconcile()
+				Expect(work.Spec.WorkloadGroups[0].DestinationSelectors).To(ConsistOf(
+					v1alpha1.WorkloadGroupScheduling{MatchLabels: map[string]string{"environment": "dev"}, Source: "promise"},
+				))
+			})
+
+			It("errors when the annotation is invalid", func() {
+				setAnnotation(`{"matchExpressions": [{"key": "region", "operator": "Bogus"}]}`)
+				_, err := t.reconcileUntilCompletion(reconciler, resReq)
+				Expect(err).To(MatchError(ContainSubstring("invalid kratix.io/destination-selectors annotation")))
+			})
+		})
+
+		When("the Promise does not allow resource requests to set destination selectors", func() {
+			It("ignores the annotation", func() {
+				setAnnotation(`{"matchLabels": {"region": "eu-west"}}`)
+				reconcile()
+				Expect(work.Spec.WorkloadGroups[0].DestinationSelectors).To(ConsistOf(
+					v1alpha1.WorkloadGroupScheduling{MatchLabels: map[string]string{"environment": "dev"}, Source: "promise"},
+				))
+			})
+		})
 	})
 
 	Describe("Resource Request Status", func() {
@@ -270,6 +425,46 @@ var _ = Describe("DynamicResourceRequestController", func() {
 			setReconcileConfigureWorkflowToReturnFinished()
 		})
 
+		Describe("destinations", func() {
+			It("summarises the write status of the resource request's Works on each Destination", func() {
+				for i, placements := range [][]v1alpha1.PlacementStatus{
+					{
+						{Destination: "dev", WriteStatus: v1alpha1.WriteStatusWritten},
+						{Destination: "prod", WriteStatus: v1alpha1.WriteStatusWritten},
+					},
+					{
+						{Destination: "prod", WriteStatus: v1alpha1.WriteStatusFailed, Message: "push rejected"},
+					},
+				} {
+					work := &v1alpha1.Work{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      fmt.Sprintf("rr-work-%d", i),
+							Namespace: resReq.GetNamespace(),
+							Labels: map[string]string{
+								"kratix.io/promise-name":  promise.GetName(),
+								"kratix.io/resource-name": resReq.GetName(),
+							},
+						},
+						Spec: v1alpha1.WorkSpec{PromiseName: promise.GetName(), ResourceName: resReq.GetName()},
+					}
+					Expect(fakeK8sClient.Create(ctx, work)).To(Succeed())
+					work.Status.Placements = placements
+					Expect(fakeK8sClient.Status().Update(ctx, work)).To(Succeed())
+				}
+
+				_, err := t.reconcileUntilCompletion(reconciler, resReq)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeK8sClient.Get(ctx, resReqNameNamespace, resReq)).To(Succeed())
+
+				destinations, _, err := unstructured.NestedSlice(resReq.Object, "status", "destinations")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(destinations).To(Equal([]interface{}{
+					map[string]interface{}{"name": "dev", "writeStatus": "Written"},
+					map[string]interface{}{"name": "prod", "writeStatus": "Failed", "message": "push rejected"},
+				}))
+			})
+		})
+
 		Describe("lastSuccessfulConfigureWorkflowTime", func() {
 			When("it's empty", func() {
 				It("remains empty when the workflow fails", func() {
@@ -1,10 +1,15 @@
 package controllers
 
 import (
+	"context"
+
 	"github.com/go-logr/logr"
 	"github.com/syntasso/kratix/api/v1alpha1"
 	"github.com/syntasso/kratix/lib/workflow"
 	"github.com/syntasso/kratix/lib/writers"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 func SetReconcileConfigureWorkflow(f func(workflow.Opts) (bool, error)) {
@@ -24,3 +29,27 @@ func SetNewGitWriter(f func(logger logr.Logger, stateStoreSpec v1alpha1.GitState
 	creds map[string][]byte) (writers.StateStoreWriter, error)) {
 	newGitWriter = f
 }
+
+func WorkFieldIndexers() map[string]client.IndexerFunc {
+	return workFieldIndexers
+}
+
+func DestinationSchedulingChangedPredicate() predicate.Predicate {
+	return destinationSchedulingChangedPredicate
+}
+
+func (r *WorkReconciler) RequestReconcilationOfWorksOnDestination(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.requestReconcilationOfWorksOnDestination(ctx, obj)
+}
+
+func (r *WorkReconciler) RequestReconcilationOfUnscheduledWorks(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.requestReconcilationOfUnscheduledWorks(ctx, obj)
+}
+
+func (r *WorkReconciler) RequestReconcilationOfWorksInNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.requestReconcilationOfWorksInNamespace(ctx, obj)
+}
+
+func FilterByTopologySpread(constraints []v1alpha1.TopologySpreadConstraint, destinations, scheduledTo, candidates []v1alpha1.Destination) []v1alpha1.Destination {
+	return newTopologySpread(constraints, destinations, scheduledTo).filter(candidates)
+}
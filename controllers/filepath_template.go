@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const kratixStateDir = ".kratix"
+
+// filepathTemplateData is the data available to a Destination's filepath
+// template. Fields must stay in sync with the documentation on
+// v1alpha1.Filepath.Template.
+type filepathTemplateData struct {
+	WorkPlacement filepathTemplateWorkPlacement
+	// ResourceRequest is nil for Promise dependencies, which have no resource
+	// request
+	ResourceRequest *filepathTemplateResourceRequest
+	Workload        filepathTemplateWorkload
+}
+
+type filepathTemplateWorkPlacement struct {
+	Name            string
+	Namespace       string
+	PromiseName     string
+	ResourceName    string
+	PipelineName    string
+	WorkloadGroupID string
+}
+
+type filepathTemplateResourceRequest struct {
+	Labels map[string]string
+}
+
+type filepathTemplateWorkload struct {
+	Filepath string
+}
+
+func parseFilepathTemplate(destination v1alpha1.Destination) (*template.Template, error) {
+	if destination.Spec.Filepath.Template == "" {
+		return nil, fmt.Errorf("destination %s has filepath mode %s but no filepath.template", destination.Name, v1alpha1.FilepathModeTemplate)
+	}
+
+	tmpl, err := template.New("filepath").Option("missingkey=error").Parse(destination.Spec.Filepath.Template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filepath.template on destination %s: %w", destination.Name, err)
+	}
+	return tmpl, nil
+}
+
+// Checks the Destination's filepath template can be parsed and renders a path
+// for Promise dependencies, which have no .ResourceRequest
+func validateFilepathTemplate(destination v1alpha1.Destination) error {
+	tmpl, err := parseFilepathTemplate(destination)
+	if err != nil {
+		return err
+	}
+
+	example := v1alpha1.WorkPlacement{Spec: v1alpha1.WorkPlacementSpec{PromiseName: "example", ID: "example"}}
+	_, err = renderTemplatedFilepaths(tmpl, example, nil, []v1alpha1.Workload{{Filepath: "example.yaml"}})
+	return err
+}
+
+// Renders the filepath of each workload using the destination template. An
+// error is returned if any rendered path escapes the destination, is reserved
+// by Kratix or collides with the path rendered for another workload.
+func renderTemplatedFilepaths(tmpl *template.Template, workPlacement v1alpha1.WorkPlacement, resourceLabels map[string]string, workloads []v1alpha1.Workload) ([]v1alpha1.Workload, error) {
+	data := filepathTemplateData{
+		WorkPlacement: filepathTemplateWorkPlacement{
+			Name:            workPlacement.GetName(),
+			Namespace:       workPlacement.GetNamespace(),
+			PromiseName:     workPlacement.Spec.PromiseName,
+			ResourceName:    workPlacement.Spec.ResourceName,
+			PipelineName:    workPlacement.PipelineName(),
+			WorkloadGroupID: workPlacement.Spec.ID,
+		},
+	}
+	if workPlacement.Spec.ResourceName != "" {
+		if resourceLabels == nil {
+			resourceLabels = map[string]string{}
+		}
+		data.ResourceRequest = &filepathTemplateResourceRequest{Labels: resourceLabels}
+	}
+
+	renderedFrom := map[string]string{}
+	var rendered []v1alpha1.Workload
+	for _, workload := range workloads {
+		data.Workload = filepathTemplateWorkload{Filepath: workload.Filepath}
+
+		buf := &bytes.Buffer{}
+		if err := tmpl.Execute(buf, data); err != nil {
+			if data.ResourceRequest == nil {
+				return nil, fmt.Errorf("failed to render filepath template for %s of a Promise dependency, which has no .ResourceRequest; guard it with {{ with .ResourceRequest }}: %w", workload.Filepath, err)
+			}
+			return nil, fmt.Errorf("failed to render filepath template for %s: %w", workload.Filepath, err)
+		}
+
+		path, err := validateTemplatedFilepath(buf.String())
+		if err != nil {
+			return nil, fmt.Errorf("invalid filepath rendered for %s: %w", workload.Filepath, err)
+		}
+
+		if original, exists := renderedFrom[path]; exists {
+			return nil, fmt.Errorf("filepath template renders %q for both %s and %s", path, original, workload.Filepath)
+		}
+		renderedFrom[path] = workload.Filepath
+
+		workload.Filepath = path
+		rendered = append(rendered, workload)
+	}
+	return rendered, nil
+}
+
+// Returns an error if a rendered path is already written for another
+// WorkPlacement on the Destination, according to their .kratix state files,
+// keyed by WorkPlacement
+func checkFilepathCollisions(workloads []v1alpha1.Workload, otherStateFiles map[string]StateFile) error {
+	writtenBy := map[string]string{}
+	for workPlacement, stateFile := range otherStateFiles {
+		for _, file := range stateFile.Files {
+			writtenBy[file] = workPlacement
+		}
+	}
+	for _, workload := range workloads {
+		if workPlacement, exists := writtenBy[workload.Filepath]; exists {
+			return fmt.Errorf("filepath template renders %q, which is already written for workplacement %s", workload.Filepath, workPlacement)
+		}
+	}
+	return nil
+}
+
+func validateTemplatedFilepath(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", fmt.Errorf("path is empty")
+	}
+
+	if filepath.IsAbs(path) {
+		return "", fmt.Errorf("path %q must be relative", path)
+	}
+
+	path = filepath.Clean(path)
+	if path == "." || path == ".." || strings.HasPrefix(path, "../") {
+		return "", fmt.Errorf("path %q must be within the destination", path)
+	}
+
+	if path == kratixStateDir || strings.HasPrefix(path, kratixStateDir+"/") {
+		return "", fmt.Errorf("path %q is reserved by Kratix", path)
+	}
+	return path, nil
+}
+
+// Returns the labels of the resource request the WorkPlacement was created
+// for, or nil when the WorkPlacement is for Promise workloads
+func getResourceRequestLabels(ctx context.Context, k8sClient client.Client, workPlacement v1alpha1.WorkPlacement) (map[string]string, error) {
+	if workPlacement.Spec.ResourceName == "" {
+		return nil, nil
+	}
+	return getResourceRequestLabelsFor(ctx, k8sClient, workPlacement.Spec.PromiseName, workPlacement.GetNamespace(), workPlacement.Spec.ResourceName)
+}
+
+func getResourceRequestLabelsFor(ctx context.Context, k8sClient client.Client, promiseName, namespace, resourceName string) (map[string]string, error) {
+	promise := &v1alpha1.Promise{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: promiseName}, promise); err != nil {
+		return nil, err
+	}
+
+	gvk, _, err := promise.GetAPI()
+	if err != nil {
+		return nil, err
+	}
+
+	rr := &unstructured.Unstructured{}
+	rr.SetGroupVersionKind(*gvk)
+	resourceRequestName := types.NamespacedName{
+		Namespace: namespace,
+		Name:      resourceName,
+	}
+	if err := k8sClient.Get(ctx, resourceRequestName, rr); err != nil {
+		return nil, err
+	}
+	return rr.GetLabels(), nil
+}
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"github.com/syntasso/kratix/lib/writers"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	syncedConditionType  = "Synced"
+	healthyConditionType = "Healthy"
+)
+
+// HealthFeedbackFile is what an agent or GitOps tool on a Destination writes
+// back to the State Store for each WorkPlacement, at healthFeedbackPath
+type HealthFeedbackFile struct {
+	// Synced, OutOfSync or Unknown
+	Sync string `yaml:"sync"`
+	// Healthy, Progressing, Degraded or Unknown
+	Health  string `yaml:"health"`
+	Message string `yaml:"message,omitempty"`
+	// The version of the State Store the results are for. Results for a
+	// version other than the one last written are reported as Unknown.
+	VersionID string `yaml:"versionID,omitempty"`
+}
+
+const healthFeedbackDir = ".kratix/status/"
+
+func healthFeedbackPath(workPlacement v1alpha1.WorkPlacement) string {
+	return fmt.Sprintf("%s%s-%s.yaml", healthFeedbackDir, workPlacement.Namespace, workPlacement.Name)
+}
+
+// Reads the results the Destination reported for all of its WorkPlacements in
+// one go, and fans them out into the Synced and Healthy conditions of each.
+// If the Destination does not report results, the conditions are removed.
+// Returns when the results should be read again.
+func (r *DestinationReconciler) reconcileHealthFeedback(o opts, writer writers.StateStoreWriter, destination *v1alpha1.Destination) (ctrl.Result, error) {
+	workPlacementList := &v1alpha1.WorkPlacementList{}
+	if err := r.Client.List(o.ctx, workPlacementList, client.MatchingLabels{targetDestinationNameLabel: destination.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	healthFeedback := destination.GetHealthFeedback()
+	var reported map[string][]byte
+	if healthFeedback != nil {
+		var err error
+		if reported, err = writer.ReadFiles([]string{healthFeedbackDir}); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to read %s: %w", healthFeedbackDir, err)
+		}
+	}
+
+	for i := range workPlacementList.Items {
+		workPlacement := &workPlacementList.Items[i]
+		if !workPlacement.DeletionTimestamp.IsZero() {
+			continue
+		}
+		var changed bool
+		if healthFeedback == nil {
+			changed = meta.RemoveStatusCondition(&workPlacement.Status.Conditions, syncedConditionType)
+			changed = meta.RemoveStatusCondition(&workPlacement.Status.Conditions, healthyConditionType) || changed
+		} else if writeSucceeded(*workPlacement) {
+			content, found := reported[healthFeedbackPath(*workPlacement)]
+			changed = setHealthFeedbackConditions(workPlacement, content, found)
+		}
+		if changed {
+			if err := r.Client.Status().Update(o.ctx, workPlacement); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	if healthFeedback == nil {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: healthFeedback.Interval.Duration}, nil
+}
+
+// Sets the Synced and Healthy conditions from the results reported for the
+// WorkPlacement, returning whether they changed
+func setHealthFeedbackConditions(workPlacement *v1alpha1.WorkPlacement, content []byte, found bool) bool {
+	path := healthFeedbackPath(*workPlacement)
+	feedback := HealthFeedbackFile{
+		Sync:    v1alpha1.SyncStatusUnknown,
+		Health:  v1alpha1.HealthStatusUnknown,
+		Message: fmt.Sprintf("No results reported at %s", path),
+	}
+
+	if found {
+		reported := HealthFeedbackFile{}
+		switch err := yaml.Unmarshal(content, &reported); {
+		case err != nil:
+			feedback.Message = fmt.Sprintf("Invalid results reported at %s: %s", path, err)
+		case reported.VersionID != "" && workPlacement.Status.VersionID != "" && reported.VersionID != workPlacement.Status.VersionID:
+			feedback.Message = fmt.Sprintf("Results reported for version %s, but version %s was last written", reported.VersionID, workPlacement.Status.VersionID)
+		default:
+			feedback = reported
+		}
+	}
+
+	synced := metav1.Condition{
+		Type:               syncedConditionType,
+		Status:             metav1.ConditionUnknown,
+		Reason:             v1alpha1.SyncStatusUnknown,
+		Message:            feedback.Message,
+		ObservedGeneration: workPlacement.GetGeneration(),
+	}
+	switch feedback.Sync {
+	case v1alpha1.SyncStatusSynced:
+		synced.Status, synced.Reason = metav1.ConditionTrue, feedback.Sync
+	case v1alpha1.SyncStatusOutOfSync:
+		synced.Status, synced.Reason = metav1.ConditionFalse, feedback.Sync
+	}
+
+	healthy := metav1.Condition{
+		Type:               healthyConditionType,
+		Status:             metav1.ConditionUnknown,
+		Reason:             v1alpha1.HealthStatusUnknown,
+		Message:            feedback.Message,
+		ObservedGeneration: workPlacement.GetGeneration(),
+	}
+	switch feedback.Health {
+	case v1alpha1.HealthStatusHealthy:
+		healthy.Status, healthy.Reason = metav1.ConditionTrue, feedback.Health
+	case v1alpha1.HealthStatusProgressing, v1alpha1.HealthStatusDegraded:
+		healthy.Status, healthy.Reason = metav1.ConditionFalse, feedback.Health
+	}
+
+	changed := meta.SetStatusCondition(&workPlacement.Status.Conditions, synced)
+	return meta.SetStatusCondition(&workPlacement.Status.Conditions, healthy) || changed
+}
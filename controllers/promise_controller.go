@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"slices"
 	"strings"
 	"sync"
@@ -27,6 +28,7 @@ import (
 	"github.com/syntasso/kratix/lib/migrations"
 	"github.com/syntasso/kratix/lib/objectutil"
 
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -242,6 +244,11 @@ func (r *PromiseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return *ctrlResult, nil
 	}
 
+	destinationsChanged, err := r.setDestinationsStatus(ctx, promise)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	if promise.ContainsAPI() {
 		dynamicControllerCanCreateResources := true
 		for _, req := range promise.Status.RequiredPromises {
@@ -286,6 +293,10 @@ func (r *PromiseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	if originalStatus == v1alpha1.PromiseStatusAvailable {
+		if destinationsChanged {
+			promise.Status.Status = v1alpha1.PromiseStatusAvailable
+			return r.updatePromiseStatus(ctx, promise)
+		}
 		return r.nextReconciliation(promise, logger)
 	}
 
@@ -295,6 +306,34 @@ func (r *PromiseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	return r.updatePromiseStatus(ctx, promise)
 }
 
+// Sets status.destinations on the Promise to the write status of its
+// dependency and Promise workflow Works on each Destination, returning
+// whether it changed. The status is not persisted.
+func (r *PromiseReconciler) setDestinationsStatus(ctx context.Context, promise *v1alpha1.Promise) (bool, error) {
+	works := &v1alpha1.WorkList{}
+	err := r.Client.List(ctx, works,
+		client.InNamespace(v1alpha1.SystemNamespace),
+		client.MatchingLabels{v1alpha1.PromiseNameLabel: promise.GetName()},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	var dependencyWorks []v1alpha1.Work
+	for _, work := range works.Items {
+		if work.IsDependency() {
+			dependencyWorks = append(dependencyWorks, work)
+		}
+	}
+
+	destinations := v1alpha1.SummariseDestinations(dependencyWorks)
+	if reflect.DeepEqual(promise.Status.Destinations, destinations) {
+		return false, nil
+	}
+	promise.Status.Destinations = destinations
+	return true, nil
+}
+
 func (r *PromiseReconciler) nextReconciliation(promise *v1alpha1.Promise, logger logr.Logger) (ctrl.Result, error) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
@@ -542,6 +581,11 @@ func (r *PromiseReconciler) ensureDynamicControllerIsStarted(promise *v1alpha1.P
 	return ctrl.NewControllerManagedBy(r.Manager).
 		For(unstructuredCRD).
 		Owns(&batchv1.Job{}).
+		Watches(
+			&v1alpha1.Work{},
+			handler.EnqueueRequestsFromMapFunc(dynamicResourceRequestController.requestReconciliationOfResourceRequest),
+			builder.WithPredicates(workPlacementsChangedPredicate),
+		).
 		Complete(dynamicResourceRequestController)
 }
 
@@ -914,6 +958,17 @@ func (r *PromiseReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				return resources
 			}),
 		).
+		Watches(
+			&v1alpha1.Work{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+				work := obj.(*v1alpha1.Work)
+				if !work.IsDependency() {
+					return nil
+				}
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: work.Spec.PromiseName}}}
+			}),
+			builder.WithPredicates(workPlacementsChangedPredicate),
+		).
 		Complete(r)
 }
 
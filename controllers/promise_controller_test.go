@@ -437,10 +437,29 @@ var _ = Describe("PromiseController", func() {
 						Expect(strings.TrimSpace(destinationSelectors)).To(Equal(`- matchlabels: environment: dev source: promise`))
 					})
 
+					By("creates a role for the workload payload in the system namespace", func() {
+						Expect(resources[2]).To(BeAssignableToTypeOf(&rbacv1.Role{}))
+						role := resources[2].(*rbacv1.Role)
+						Expect(role.GetNamespace()).To(Equal("kratix-platform-system"))
+						Expect(role.GetLabels()).To(Equal(promiseCommonLabels))
+						Expect(role.Rules).To(ConsistOf(
+							rbacv1.PolicyRule{
+								Verbs:     []string{"get", "create"},
+								APIGroups: []string{""},
+								Resources: []string{"configmaps"},
+							},
+						))
+
+						Expect(resources[3]).To(BeAssignableToTypeOf(&rbacv1.RoleBinding{}))
+						binding := resources[3].(*rbacv1.RoleBinding)
+						Expect(binding.GetNamespace()).To(Equal("kratix-platform-system"))
+						Expect(binding.RoleRef.Name).To(Equal(role.GetName()))
+					})
+
 					promiseResourcesName.Namespace = ""
 					By("creates a role for the pipeline service account", func() {
-						Expect(resources[2]).To(BeAssignableToTypeOf(&rbacv1.ClusterRole{}))
-						role := resources[2].(*rbacv1.ClusterRole)
+						Expect(resources[4]).To(BeAssignableToTypeOf(&rbacv1.ClusterRole{}))
+						role := resources[4].(*rbacv1.ClusterRole)
 						Expect(role.GetLabels()).To(Equal(promiseCommonLabels))
 						Expect(role.Rules).To(ConsistOf(
 							rbacv1.PolicyRule{
@@ -453,8 +472,8 @@ var _ = Describe("PromiseController", func() {
 					})
 
 					By("associates the new role with the new service account", func() {
-						Expect(resources[3]).To(BeAssignableToTypeOf(&rbacv1.ClusterRoleBinding{}))
-						binding := resources[3].(*rbacv1.ClusterRoleBinding)
+						Expect(resources[5]).To(BeAssignableToTypeOf(&rbacv1.ClusterRoleBinding{}))
+						binding := resources[5].(*rbacv1.ClusterRoleBinding)
 						Expect(binding.RoleRef.Name).To(Equal("promise-with-workflow-promise-configure-first-pipeline"))
 						Expect(binding.Subjects).To(HaveLen(1))
 						Expect(binding.Subjects[0]).To(Equal(rbacv1.Subject{
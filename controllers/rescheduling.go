@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Splits the misscheduled WorkPlacements into those the Promise's rescheduling
+// policy allows to be moved now, and those that must wait longer before they
+// can be moved. WorkPlacements are never moved when the policy is never.
+func (s *Scheduler) partitionReschedulableWorkPlacements(promise *v1alpha1.Promise, misscheduled []v1alpha1.WorkPlacement) ([]v1alpha1.WorkPlacement, []v1alpha1.WorkPlacement, error) {
+	if promise == nil || len(misscheduled) == 0 {
+		return nil, nil, nil
+	}
+
+	rescheduling := promise.Spec.SchedulingPolicy.Rescheduling
+	switch rescheduling.Policy {
+	case v1alpha1.ReschedulingPolicyOnMismatch:
+		return misscheduled, nil, nil
+	case v1alpha1.ReschedulingPolicyOnMismatchAfter:
+		var reschedulable, waiting []v1alpha1.WorkPlacement
+		for _, wp := range misscheduled {
+			since, err := s.misscheduledSince(wp)
+			if err != nil {
+				return nil, nil, err
+			}
+			if rescheduling.After == nil || time.Since(since) >= rescheduling.After.Duration {
+				reschedulable = append(reschedulable, wp)
+			} else {
+				waiting = append(waiting, wp)
+			}
+		}
+		return reschedulable, waiting, nil
+	default:
+		return nil, nil, nil
+	}
+}
+
+// Returns when the WorkPlacement was first marked as misscheduled
+func (s *Scheduler) misscheduledSince(workPlacement v1alpha1.WorkPlacement) (time.Time, error) {
+	current := &v1alpha1.WorkPlacement{}
+	if err := s.Client.Get(context.Background(), client.ObjectKeyFromObject(&workPlacement), current); err != nil {
+		return time.Time{}, err
+	}
+
+	condition := meta.FindStatusCondition(current.Status.Conditions, misscheduledConditionType)
+	if condition == nil {
+		return time.Now(), nil
+	}
+	return condition.LastTransitionTime.Time, nil
+}
+
+// Deletes the WorkPlacements being moved, once the WorkPlacements replacing
+// them have been written to their Destinations
+func (s *Scheduler) removeRescheduledWorkPlacements(rescheduled, placed []v1alpha1.WorkPlacement, replicas int) (bool, error) {
+	var written int
+	for _, wp := range placed {
+		if writeSucceeded(wp) {
+			written++
+		}
+	}
+
+	if written < replicas {
+		return false, nil
+	}
+
+	for _, wp := range rescheduled {
+		s.Log.Info("deleting misscheduled workplacement that has been rescheduled", "workPlacementName", wp.Name, "namespace", wp.Namespace, "destination", wp.Spec.TargetDestinationName)
+		if err := s.Client.Delete(context.Background(), &wp); err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+	}
+	return true, nil
+}
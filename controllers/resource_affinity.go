@@ -0,0 +1,270 @@
+package controllers
+
+import (
+	"context"
+	"slices"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// resourceAffinity holds the Destinations that the resource requests selected
+// by a WorkloadGroup's resource affinity and anti-affinity terms are scheduled
+// to
+type resourceAffinity struct {
+	// for each affinity term, the Destinations it allows; nil allows any
+	// Destination
+	affinityDestinations []map[string]bool
+	// the Destinations that any anti-affinity term rejects
+	antiAffinityDestinations map[string]bool
+}
+
+// Resolves the resource affinity and anti-affinity terms from all sources of
+// the WorkloadGroup. An affinity term that only selects the resource request
+// being scheduled allows any Destination, so the first of a group of resources
+// that must be scheduled together can be scheduled.
+func (s *Scheduler) newResourceAffinity(work *v1alpha1.Work, workloadGroup v1alpha1.WorkloadGroup, cache *resourceRequestCache) (*resourceAffinity, error) {
+	var affinity, antiAffinity []v1alpha1.ResourceAffinityTerm
+	for _, scheduling := range workloadGroup.DestinationSelectors {
+		affinity = append(affinity, scheduling.ResourceAffinity...)
+		antiAffinity = append(antiAffinity, scheduling.ResourceAntiAffinity...)
+	}
+
+	result := &resourceAffinity{antiAffinityDestinations: map[string]bool{}}
+	if len(affinity) == 0 && len(antiAffinity) == 0 {
+		return result, nil
+	}
+
+	ownLabels, err := cache.labels(work.Spec.PromiseName, work.Namespace, work.Spec.ResourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, term := range affinity {
+		selector, err := resourceAffinitySelector(term, ownLabels)
+		if err != nil {
+			return nil, err
+		}
+
+		destinations, err := destinationsOfSelectedResources(work, term, selector, cache)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(destinations) == 0 && termSelectsResource(term, selector, work.Namespace, work.Spec.PromiseName, work.Namespace, ownLabels) {
+			destinations = nil
+		}
+		result.affinityDestinations = append(result.affinityDestinations, destinations)
+	}
+
+	for _, term := range antiAffinity {
+		selector, err := resourceAffinitySelector(term, ownLabels)
+		if err != nil {
+			return nil, err
+		}
+
+		destinations, err := destinationsOfSelectedResources(work, term, selector, cache)
+		if err != nil {
+			return nil, err
+		}
+		for destination := range destinations {
+			result.antiAffinityDestinations[destination] = true
+		}
+	}
+	return result, nil
+}
+
+func (a *resourceAffinity) allows(destination v1alpha1.Destination) bool {
+	if a.antiAffinityDestinations[destination.Name] {
+		return false
+	}
+	for _, allowed := range a.affinityDestinations {
+		if allowed != nil && !allowed[destination.Name] {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *resourceAffinity) filter(destinations []v1alpha1.Destination) []v1alpha1.Destination {
+	var allowed []v1alpha1.Destination
+	for _, destination := range destinations {
+		if a.allows(destination) {
+			allowed = append(allowed, destination)
+		}
+	}
+	return allowed
+}
+
+// Returns the Destinations that the resource requests selected by the term,
+// other than the one the Work is for, are scheduled to. Only the WorkPlacements
+// in the namespaces the term selects are listed.
+func destinationsOfSelectedResources(work *v1alpha1.Work, term v1alpha1.ResourceAffinityTerm, selector labels.Selector, cache *resourceRequestCache) (map[string]bool, error) {
+	namespaces := term.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{work.Namespace}
+	}
+
+	destinations := map[string]bool{}
+	for _, namespace := range namespaces {
+		workPlacements, err := cache.workPlacementsIn(namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, wp := range workPlacements {
+			if wp.Spec.ResourceName == "" || !wp.DeletionTimestamp.IsZero() {
+				continue
+			}
+			if wp.Namespace == work.Namespace && wp.Spec.PromiseName == work.Spec.PromiseName && wp.Spec.ResourceName == work.Spec.ResourceName {
+				continue
+			}
+			if !termSelectsResource(term, nil, work.Namespace, wp.Spec.PromiseName, wp.Namespace, nil) {
+				continue
+			}
+
+			resourceLabels, err := cache.labels(wp.Spec.PromiseName, wp.Namespace, wp.Spec.ResourceName)
+			if err != nil {
+				return nil, err
+			}
+			if selector.Matches(labels.Set(resourceLabels)) {
+				destinations[wp.Spec.TargetDestinationName] = true
+			}
+		}
+	}
+	return destinations, nil
+}
+
+// Returns whether the term selects a resource request of the Promise in the
+// namespace. The labels are only checked when a selector is given.
+func termSelectsResource(term v1alpha1.ResourceAffinityTerm, selector labels.Selector, workNamespace, promiseName, namespace string, resourceLabels map[string]string) bool {
+	if len(term.Promises) > 0 && !slices.Contains(term.Promises, promiseName) {
+		return false
+	}
+
+	namespaces := term.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{workNamespace}
+	}
+	if !slices.Contains(namespaces, namespace) {
+		return false
+	}
+
+	return selector == nil || selector.Matches(labels.Set(resourceLabels))
+}
+
+// Returns the term's label selector, with a requirement added for each of the
+// term's matchLabelKeys that the resource request being scheduled has
+func resourceAffinitySelector(term v1alpha1.ResourceAffinityTerm, ownLabels map[string]string) (labels.Selector, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&term.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range term.MatchLabelKeys {
+		value, found := ownLabels[key]
+		if !found {
+			continue
+		}
+		requirement, err := labels.NewRequirement(key, selection.Equals, []string{value})
+		if err != nil {
+			return nil, err
+		}
+		selector = selector.Add(*requirement)
+	}
+	return selector, nil
+}
+
+// resourceRequestCache holds the lookups made to resolve resource affinity
+// while scheduling a Work, so each Promise, namespace of resource requests and
+// namespace of WorkPlacements is only read once
+type resourceRequestCache struct {
+	client client.Client
+	// Promise name to the GVK of its API; nil when the Promise no longer exists
+	// or has no API
+	gvks map[string]*schema.GroupVersionKind
+	// "promise/namespace" to the labels of each resource request of the Promise
+	// in the namespace
+	resourceLabels map[string]map[string]map[string]string
+	// namespace to the WorkPlacements in it
+	workPlacements map[string][]v1alpha1.WorkPlacement
+}
+
+func newResourceRequestCache(c client.Client) *resourceRequestCache {
+	return &resourceRequestCache{
+		client:         c,
+		gvks:           map[string]*schema.GroupVersionKind{},
+		resourceLabels: map[string]map[string]map[string]string{},
+		workPlacements: map[string][]v1alpha1.WorkPlacement{},
+	}
+}
+
+func (c *resourceRequestCache) workPlacementsIn(namespace string) ([]v1alpha1.WorkPlacement, error) {
+	if workPlacements, found := c.workPlacements[namespace]; found {
+		return workPlacements, nil
+	}
+
+	workPlacementList := &v1alpha1.WorkPlacementList{}
+	if err := c.client.List(context.Background(), workPlacementList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	c.workPlacements[namespace] = workPlacementList.Items
+	return workPlacementList.Items, nil
+}
+
+// Returns the labels of the resource request, or nil if it or its Promise no
+// longer exists. The resource requests of a Promise are listed once per
+// namespace.
+func (c *resourceRequestCache) labels(promiseName, namespace, resourceName string) (map[string]string, error) {
+	key := promiseName + "/" + namespace
+	if resourceLabels, found := c.resourceLabels[key]; found {
+		return resourceLabels[resourceName], nil
+	}
+
+	gvk, err := c.promiseGVK(promiseName)
+	if err != nil || gvk == nil {
+		return nil, err
+	}
+
+	resourceRequests := &unstructured.UnstructuredList{}
+	resourceRequests.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := c.client.List(context.Background(), resourceRequests, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	resourceLabels := map[string]map[string]string{}
+	for _, rr := range resourceRequests.Items {
+		resourceLabels[rr.GetName()] = rr.GetLabels()
+	}
+	c.resourceLabels[key] = resourceLabels
+	return resourceLabels[resourceName], nil
+}
+
+func (c *resourceRequestCache) promiseGVK(promiseName string) (*schema.GroupVersionKind, error) {
+	if gvk, found := c.gvks[promiseName]; found {
+		return gvk, nil
+	}
+
+	promise := &v1alpha1.Promise{}
+	if err := c.client.Get(context.Background(), types.NamespacedName{Name: promiseName}, promise); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		c.gvks[promiseName] = nil
+		return nil, nil
+	}
+
+	gvk, _, err := promise.GetAPI()
+	if err != nil {
+		gvk = nil
+	}
+	c.gvks[promiseName] = gvk
+	return gvk, nil
+}
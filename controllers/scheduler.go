@@ -3,10 +3,15 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"math/rand"
+	"reflect"
+	"slices"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -23,6 +28,8 @@ const (
 	workloadGroupIDKey         = v1alpha1.KratixPrefix + "workload-group-id"
 	misscheduledLabel          = v1alpha1.KratixPrefix + "misscheduled"
 	targetDestinationNameLabel = v1alpha1.KratixPrefix + "targetDestinationName"
+
+	misscheduledConditionType = "Misscheduled"
 )
 
 type schedulingStatus string
@@ -31,41 +38,169 @@ const (
 	scheduledStatus    schedulingStatus = "scheduled"
 	unscheduledStatus  schedulingStatus = "unscheduled"
 	misscheduledStatus schedulingStatus = "misscheduled"
+	// some, but not all, replicas of the WorkloadGroup are scheduled
+	partiallyScheduledStatus schedulingStatus = "partiallyScheduled"
+	// the WorkloadGroup matches Destinations, but none of them has capacity
+	atCapacityStatus schedulingStatus = "atCapacity"
+	// the WorkloadGroup is misscheduled, and is being or will be moved to a
+	// matching Destination
+	reschedulingStatus schedulingStatus = "rescheduling"
+	// the WorkloadGroup is being moved off a draining Destination
+	drainingStatus schedulingStatus = "draining"
 )
 
 type Scheduler struct {
 	Client client.Client
 	Log    logr.Logger
+	// DefaultSchedulingStrategy is used for resource requests whose Promise
+	// doesn't set a scheduling strategy. Defaults to random when empty.
+	DefaultSchedulingStrategy string
+
+	strategies map[string]SchedulingStrategy
+	mutex      sync.Mutex
+}
+
+// RegisterSchedulingStrategy makes a strategy available to the Scheduler under
+// the given name, replacing any built-in strategy with the same name
+func (s *Scheduler) RegisterSchedulingStrategy(name string, strategy SchedulingStrategy) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.initSchedulingStrategies()
+	s.strategies[name] = strategy
+}
+
+// HasSchedulingStrategy returns whether a strategy, built-in or registered, is
+// available under the given name
+func (s *Scheduler) HasSchedulingStrategy(name string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.initSchedulingStrategies()
+	_, found := s.strategies[name]
+	return found
+}
+
+func (s *Scheduler) initSchedulingStrategies() {
+	if s.strategies == nil {
+		s.strategies = newBuiltInSchedulingStrategies(s.Client)
+	}
+}
+
+// Returns the Promise the Work belongs to, or nil if it doesn't exist
+func (s *Scheduler) getPromise(work *v1alpha1.Work) (*v1alpha1.Promise, error) {
+	promise := &v1alpha1.Promise{}
+	err := s.Client.Get(context.Background(), client.ObjectKey{Name: work.Spec.PromiseName}, promise)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return promise, nil
+}
+
+func (s *Scheduler) schedulingStrategyFor(promise *v1alpha1.Promise) (SchedulingStrategy, error) {
+	name := s.DefaultSchedulingStrategy
+	if promise != nil && promise.Spec.SchedulingPolicy.Strategy != "" {
+		name = promise.Spec.SchedulingPolicy.Strategy
+	}
+
+	if name == "" {
+		name = v1alpha1.SchedulingStrategyRandom
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.initSchedulingStrategies()
+	strategy, found := s.strategies[name]
+	if !found {
+		return nil, fmt.Errorf("unknown scheduling strategy %q", name)
+	}
+	return strategy, nil
 }
 
 // Reconciles all WorkloadGroups in a Work by scheduling them to Destinations via
-// Workplacements.
+// Workplacements. Returns the IDs of the WorkloadGroups that are not fully
+// scheduled or are being rescheduled, which should be reconciled again later.
 func (s *Scheduler) ReconcileWork(work *v1alpha1.Work) ([]string, error) {
-	unschedulable := []string{}
-	misscheduled := []string{}
+	result := workSchedulingResult{unschedulable: []string{}}
+	destinations := newDestinationCache(s.Client)
 	for _, wg := range work.Spec.WorkloadGroups {
-		schedulingStatus, err := s.reconcileWorkloadGroup(wg, work)
+		schedulingStatus, err := s.reconcileWorkloadGroup(wg, work, destinations)
 		if err != nil {
 			return nil, err
 		}
 
-		if schedulingStatus == unscheduledStatus {
-			unschedulable = append(unschedulable, wg.ID)
+		switch schedulingStatus {
+		case unscheduledStatus:
+			result.unschedulable = append(result.unschedulable, wg.ID)
+			result.noDestinations = append(result.noDestinations, wg.ID)
+		case atCapacityStatus:
+			result.unschedulable = append(result.unschedulable, wg.ID)
+			result.atCapacity = append(result.atCapacity, wg.ID)
+		case partiallyScheduledStatus:
+			result.unschedulable = append(result.unschedulable, wg.ID)
+			result.partiallyScheduled = append(result.partiallyScheduled, wg.ID)
+		case misscheduledStatus:
+			result.misscheduled = append(result.misscheduled, wg.ID)
+		case reschedulingStatus:
+			result.misscheduled = append(result.misscheduled, wg.ID)
+			result.rescheduling = append(result.rescheduling, wg.ID)
+		case drainingStatus:
+			result.rescheduling = append(result.rescheduling, wg.ID)
+		}
+
+		wgStatus, err := s.getWorkloadGroupStatus(work, wg)
+		if err != nil {
+			return nil, err
 		}
 
-		if schedulingStatus == misscheduledStatus {
-			misscheduled = append(misscheduled, wg.ID)
+		switch schedulingStatus {
+		case unscheduledStatus, atCapacityStatus, partiallyScheduledStatus:
+			if wgStatus.Explanation, err = s.explainScheduling(work, wg, destinations); err != nil {
+				return nil, err
+			}
 		}
+		result.workloadGroups = append(result.workloadGroups, wgStatus)
 	}
 
-	if err := s.updateWorkStatus(work, unschedulable, misscheduled); err != nil {
+	if err := s.updateWorkStatus(work, result); err != nil {
 		return nil, err
 	}
 
-	return unschedulable, s.cleanupDanglingWorkplacements(work)
+	return append(result.unschedulable, result.rescheduling...), s.cleanupDanglingWorkplacements(work)
+}
+
+// workSchedulingResult holds the IDs of the WorkloadGroups of a Work by
+// scheduling outcome
+type workSchedulingResult struct {
+	// all the WorkloadGroups that are not fully scheduled
+	unschedulable      []string
+	noDestinations     []string
+	atCapacity         []string
+	partiallyScheduled []string
+	misscheduled       []string
+	// WorkloadGroups that are being or will be moved to another Destination
+	rescheduling   []string
+	workloadGroups []v1alpha1.WorkloadGroupStatus
+}
+
+func (s *Scheduler) getWorkloadGroupStatus(work *v1alpha1.Work, workloadGroup v1alpha1.WorkloadGroup) (v1alpha1.WorkloadGroupStatus, error) {
+	workPlacements, err := s.getExistingWorkPlacementsForWorkloadGroup(work.Namespace, work.Name, workloadGroup)
+	if err != nil {
+		return v1alpha1.WorkloadGroupStatus{}, err
+	}
+
+	status := v1alpha1.WorkloadGroupStatus{ID: workloadGroup.ID}
+	for _, wp := range workPlacements {
+		if wp.DeletionTimestamp.IsZero() {
+			status.Destinations = append(status.Destinations, wp.Spec.TargetDestinationName)
+		}
+	}
+	sort.Strings(status.Destinations)
+	return status, nil
 }
 
-func (s *Scheduler) updateWorkStatus(work *v1alpha1.Work, unscheduledWorkloadGroupIDs, missscheduledWorkloadGroupIDs []string) error {
+func (s *Scheduler) updateWorkStatus(work *v1alpha1.Work, result workSchedulingResult) error {
 	work = work.DeepCopy()
 	conditions := []metav1.Condition{
 		{
@@ -90,15 +225,32 @@ func (s *Scheduler) updateWorkStatus(work *v1alpha1.Work, unscheduledWorkloadGro
 		},
 	}
 
-	if len(unscheduledWorkloadGroupIDs) > 0 {
+	if len(result.unschedulable) > 0 {
 		conditions[0].Status = "False"
-		conditions[0].Message = fmt.Sprintf("No Destinations available work WorkloadGroups: %v", unscheduledWorkloadGroupIDs)
 		conditions[0].Reason = "UnscheduledWorkloadGroups"
+
+		var messages []string
+		if len(result.noDestinations) > 0 {
+			messages = append(messages, fmt.Sprintf("No Destinations available work WorkloadGroups: %v", result.noDestinations))
+		}
+		if len(result.atCapacity) > 0 {
+			messages = append(messages, fmt.Sprintf("No Destinations with available capacity for WorkloadGroups: %v", result.atCapacity))
+			if len(result.atCapacity) == len(result.unschedulable) {
+				conditions[0].Reason = "DestinationsAtCapacity"
+			}
+		}
+		if len(result.partiallyScheduled) > 0 {
+			messages = append(messages, fmt.Sprintf("Not enough Destinations available for all replicas of WorkloadGroups: %v", result.partiallyScheduled))
+			if len(result.partiallyScheduled) == len(result.unschedulable) {
+				conditions[0].Reason = "PartiallyScheduledWorkloadGroups"
+			}
+		}
+		conditions[0].Message = strings.Join(messages, "; ")
 	}
 
-	if len(missscheduledWorkloadGroupIDs) > 0 {
+	if len(result.misscheduled) > 0 {
 		conditions[1].Status = "True"
-		conditions[1].Message = fmt.Sprintf("WorkloadGroup(s) not scheduled to correct Destination(s): %v", missscheduledWorkloadGroupIDs)
+		conditions[1].Message = fmt.Sprintf("WorkloadGroup(s) not scheduled to correct Destination(s): %v", result.misscheduled)
 		conditions[1].Reason = "ScheduledToIncorrectDestinations"
 	}
 
@@ -108,11 +260,13 @@ func (s *Scheduler) updateWorkStatus(work *v1alpha1.Work, unscheduledWorkloadGro
 		work.Status.Conditions[0].Reason == conditions[0].Reason &&
 		work.Status.Conditions[1].Status == conditions[1].Status &&
 		work.Status.Conditions[1].Message == conditions[1].Message &&
-		work.Status.Conditions[1].Reason == conditions[1].Reason {
+		work.Status.Conditions[1].Reason == conditions[1].Reason &&
+		reflect.DeepEqual(work.Status.WorkloadGroups, result.workloadGroups) {
 		return nil
 	}
 
 	work.Status.Conditions = conditions
+	work.Status.WorkloadGroups = result.workloadGroups
 	return s.Client.Status().Update(context.Background(), work)
 }
 
@@ -147,66 +301,179 @@ func (s *Scheduler) cleanupDanglingWorkplacements(work *v1alpha1.Work) error {
 }
 
 // Reconciles a WorkloadGroup by scheduling it to a Destination via a Workplacement.
-func (s *Scheduler) reconcileWorkloadGroup(workloadGroup v1alpha1.WorkloadGroup, work *v1alpha1.Work) (schedulingStatus, error) {
+func (s *Scheduler) reconcileWorkloadGroup(workloadGroup v1alpha1.WorkloadGroup, work *v1alpha1.Work, destinations *destinationCache) (schedulingStatus, error) {
 	existingWorkplacements, err := s.getExistingWorkPlacementsForWorkloadGroup(work.Namespace, work.Name, workloadGroup)
 	if err != nil {
 		return "", err
 	}
 
+	tolerations := resolveTolerationsForWorkloadGroup(workloadGroup)
+	if work.IsDependency() {
+		// dependencies are not replaced on another Destination, so they are
+		// removed from Destinations with NoExecute taints straight away
+		var evictedWorkplacements []v1alpha1.WorkPlacement
+		existingWorkplacements, evictedWorkplacements, err = s.partitionEvictedWorkPlacements(existingWorkplacements, tolerations, destinations)
+		if err != nil {
+			return "", err
+		}
+		if err := s.evictWorkPlacements(evictedWorkplacements); err != nil {
+			return "", err
+		}
+	}
+
+	return s.scheduleWorkloadGroup(workloadGroup, work, existingWorkplacements, tolerations, destinations)
+}
+
+func (s *Scheduler) scheduleWorkloadGroup(workloadGroup v1alpha1.WorkloadGroup, work *v1alpha1.Work, existingWorkplacements []v1alpha1.WorkPlacement, tolerations []v1alpha1.Toleration, destinations *destinationCache) (schedulingStatus, error) {
 	status := scheduledStatus
+	var promise *v1alpha1.Promise
+	// WorkPlacements being moved to another Destination
+	var moving []v1alpha1.WorkPlacement
 	if work.IsResourceRequest() {
-		// If the Work is for a Resource Request, only one Workplacement will be created per
-		// WorkloadGroup. If this Workplacement already exists, it will be updated.
+		var err error
+		promise, err = s.getPromise(work)
+		if err != nil {
+			return "", err
+		}
+
+		// If the Work is for a Resource Request, one Workplacement will be created per
+		// replica of the WorkloadGroup. Existing Workplacements will be updated.
+		var misscheduledWorkplacements []v1alpha1.WorkPlacement
 		if len(existingWorkplacements) > 0 {
 			var errored int
-			for _, existingWorkplacement := range existingWorkplacements {
+			for i := range existingWorkplacements {
 				s.Log.Info("found workplacement for work; will try an update")
-				misscheduled, err := s.updateWorkPlacement(workloadGroup, work, &existingWorkplacement)
+				misscheduled, err := s.updateWorkPlacement(workloadGroup, work, &existingWorkplacements[i], destinations)
 				if err != nil {
-					s.Log.Error(err, "error updating workplacement for work", "workplacement", existingWorkplacement.Name, "work", work.Name, "workloadGroupID", workloadGroup.ID)
+					s.Log.Error(err, "error updating workplacement for work", "workplacement", existingWorkplacements[i].Name, "work", work.Name, "workloadGroupID", workloadGroup.ID)
 					errored++
 				}
 				if misscheduled {
-					status = misscheduledStatus
+					misscheduledWorkplacements = append(misscheduledWorkplacements, existingWorkplacements[i])
 				}
 			}
 
 			if errored > 0 {
 				return "", fmt.Errorf("failed to update %d of %d workplacements for work", errored, len(existingWorkplacements))
 			}
+		}
+
+		// WorkPlacements being rescheduled, drained or evicted by a NoExecute
+		// taint no longer count as replicas, so replacements are scheduled for
+		// them. They are only deleted once their replacements are written.
+		rescheduling, waitingToReschedule, err := s.partitionReschedulableWorkPlacements(promise, misscheduledWorkplacements)
+		if err != nil {
+			return "", err
+		}
+		_, evicted, err := s.partitionEvictedWorkPlacements(withoutWorkPlacements(existingWorkplacements, rescheduling), tolerations, destinations)
+		if err != nil {
+			return "", err
+		}
+		moving = append(slices.Clone(rescheduling), evicted...)
+		moving = append(moving, drainingWorkPlacements(withoutWorkPlacements(existingWorkplacements, moving))...)
+
+		existingWorkplacements, err = s.removeExcessReplicas(withoutWorkPlacements(existingWorkplacements, moving), promise.GetReplicas())
+		if err != nil {
+			return "", err
+		}
 
+		if len(moving) > 0 {
+			removed, err := s.removeRescheduledWorkPlacements(moving, existingWorkplacements, promise.GetReplicas())
+			if err != nil {
+				return "", err
+			}
+			if removed {
+				moving = nil
+			}
+		}
+
+		for _, wp := range existingWorkplacements {
+			if slices.ContainsFunc(misscheduledWorkplacements, func(misscheduled v1alpha1.WorkPlacement) bool {
+				return misscheduled.Name == wp.Name
+			}) {
+				status = misscheduledStatus
+			}
+		}
+		if (len(moving) > 0 && len(rescheduling) > 0) || len(waitingToReschedule) > 0 {
+			status = reschedulingStatus
+		} else if len(moving) > 0 {
+			status = drainingStatus
+		}
+
+		if len(existingWorkplacements) >= promise.GetReplicas() {
 			return status, nil
 		}
 	}
 
 	destinationSelectors := resolveDestinationSelectorsForWorkloadGroup(workloadGroup, work)
-	targetDestinationNames := s.getTargetDestinationNames(destinationSelectors, work)
+	targetDestinationNames, atCapacity, err := s.getTargetDestinationNames(destinationSelectors, tolerations, workloadGroup, work, promise, existingWorkplacements, destinations)
+	if err != nil {
+		return "", err
+	}
 	targetDestinationMap := map[string]bool{}
 	for _, dest := range targetDestinationNames {
 		//false == not misscheduled
 		targetDestinationMap[dest] = false
 	}
 
-	for _, existingWorkplacement := range existingWorkplacements {
-		dest := existingWorkplacement.Spec.TargetDestinationName
-		_, exists := targetDestinationMap[dest]
-		if !exists {
-			//true == misscheduled
-			targetDestinationMap[dest] = true
+	if work.IsDependency() && len(existingWorkplacements) > 0 {
+		// taints that are not NoExecute don't affect existing WorkPlacements, so
+		// they are only misscheduled if the Destination no longer matches
+		matchingDestinations := map[string]bool{}
+		for _, dest := range s.getMatchingDestinations(destinations, destinationSelectors) {
+			matchingDestinations[dest.Name] = true
+		}
+
+		for _, existingWorkplacement := range existingWorkplacements {
+			dest := existingWorkplacement.Spec.TargetDestinationName
+			_, exists := targetDestinationMap[dest]
+			if !exists {
+				//true == misscheduled
+				targetDestinationMap[dest] = !matchingDestinations[dest]
+			}
 		}
 	}
 
 	if len(targetDestinationMap) == 0 {
-		s.Log.Info("no Destinations can be selected for scheduling", "scheduling", destinationSelectors, "workloadGroupDirectory", workloadGroup.Directory, "workloadGroupID", workloadGroup.ID)
+		if len(moving) > 0 {
+			s.Log.Info("no Destinations can be selected to move to; keeping workplacements on their current Destination", "scheduling", metav1.FormatLabelSelector(&destinationSelectors), "workloadGroupDirectory", workloadGroup.Directory, "workloadGroupID", workloadGroup.ID)
+			return status, nil
+		}
+		if len(existingWorkplacements) > 0 {
+			s.Log.Info("not enough Destinations can be selected to schedule all replicas", "scheduling", metav1.FormatLabelSelector(&destinationSelectors), "workloadGroupDirectory", workloadGroup.Directory, "workloadGroupID", workloadGroup.ID)
+			return partiallyScheduledStatus, nil
+		}
+		if atCapacity {
+			s.Log.Info("all Destinations that can be selected for scheduling are at capacity", "scheduling", metav1.FormatLabelSelector(&destinationSelectors), "workloadGroupDirectory", workloadGroup.Directory, "workloadGroupID", workloadGroup.ID)
+			return atCapacityStatus, nil
+		}
+		s.Log.Info("no Destinations can be selected for scheduling", "scheduling", metav1.FormatLabelSelector(&destinationSelectors), "workloadGroupDirectory", workloadGroup.Directory, "workloadGroupID", workloadGroup.ID)
 		return unscheduledStatus, nil
 	}
 
+	// changes to the static dependencies of a Promise may be held back from
+	// some Destinations while they are rolled out
+	var heldBack map[string]bool
+	if work.IsStaticDependency() {
+		if heldBack, err = s.reconcileDependencyRollout(workloadGroup, work, existingWorkplacements, destinations); err != nil {
+			return "", err
+		}
+	}
+
 	s.Log.Info("found available target Destinations", "work", work.GetName(), "destinations", targetDestinationNames)
-	misscheduled, err := s.applyWorkplacementsForTargetDestinations(workloadGroup, work, targetDestinationMap)
+	misscheduled, err := s.applyWorkplacementsForTargetDestinations(workloadGroup, work, targetDestinationMap, heldBack)
 	if err != nil {
 		return "", err
 	}
 
+	if len(moving) > 0 {
+		return status, nil
+	}
+
+	if work.IsResourceRequest() && len(existingWorkplacements)+len(targetDestinationNames) < promise.GetReplicas() {
+		return partiallyScheduledStatus, nil
+	}
+
 	if misscheduled {
 		status = misscheduledStatus
 	}
@@ -214,12 +481,59 @@ func (s *Scheduler) reconcileWorkloadGroup(workloadGroup v1alpha1.WorkloadGroup,
 	return status, nil
 }
 
-func (s *Scheduler) updateWorkPlacement(workloadGroup v1alpha1.WorkloadGroup, work *v1alpha1.Work, workPlacement *v1alpha1.WorkPlacement) (bool, error) {
+// Deletes WorkPlacements when there are more than the number of replicas,
+// starting with misscheduled WorkPlacements, and returns the remaining ones
+func (s *Scheduler) removeExcessReplicas(workPlacements []v1alpha1.WorkPlacement, replicas int) ([]v1alpha1.WorkPlacement, error) {
+	if len(workPlacements) <= replicas {
+		return workPlacements, nil
+	}
+
+	sorted := slices.Clone(workPlacements)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		iMisscheduled := sorted[i].GetLabels()[misscheduledLabel] == "true"
+		jMisscheduled := sorted[j].GetLabels()[misscheduledLabel] == "true"
+		if iMisscheduled != jMisscheduled {
+			return jMisscheduled
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	for _, wp := range sorted[replicas:] {
+		s.Log.Info("deleting workplacement for replica that is no longer required", "workPlacementName", wp.Name, "namespace", wp.Namespace)
+		if err := s.Client.Delete(context.Background(), &wp); err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return sorted[:replicas], nil
+}
+
+func withoutWorkPlacements(workPlacements, remove []v1alpha1.WorkPlacement) []v1alpha1.WorkPlacement {
+	var remaining []v1alpha1.WorkPlacement
+	for _, wp := range workPlacements {
+		if !slices.ContainsFunc(remove, func(removed v1alpha1.WorkPlacement) bool {
+			return removed.Name == wp.Name
+		}) {
+			remaining = append(remaining, wp)
+		}
+	}
+	return remaining
+}
+
+func (s *Scheduler) updateWorkPlacement(workloadGroup v1alpha1.WorkloadGroup, work *v1alpha1.Work, workPlacement *v1alpha1.WorkPlacement, destinations *destinationCache) (bool, error) {
 	misscheduled := true
 	destinationSelectors := resolveDestinationSelectorsForWorkloadGroup(workloadGroup, work)
-	for _, dest := range s.getTargetDestinationNames(destinationSelectors, work) {
-		if dest == workPlacement.Spec.TargetDestinationName {
-			misscheduled = false
+	var namespaceLabels map[string]string
+	if work.IsResourceRequest() {
+		var err error
+		if namespaceLabels, err = s.getNamespaceLabels(work.Namespace); err != nil {
+			return false, err
+		}
+	}
+	for _, dest := range s.getMatchingDestinations(destinations, destinationSelectors) {
+		if dest.Name == workPlacement.Spec.TargetDestinationName {
+			// resource requests are also misscheduled when the Destination no
+			// longer allows their namespace
+			misscheduled = work.IsResourceRequest() && !dest.AllowsNamespace(work.Namespace, namespaceLabels)
 			break
 		}
 	}
@@ -289,7 +603,32 @@ func (s *Scheduler) listWorkplacementWithLabels(namespace string, matchLabels ma
 	return workPlacementList.Items, nil
 }
 
-func (s *Scheduler) applyWorkplacementsForTargetDestinations(workloadGroup v1alpha1.WorkloadGroup, work *v1alpha1.Work, targetDestinationNames map[string]bool) (bool, error) {
+// Returns the WorkPlacement's annotations with the Work's added, keeping the
+// annotations set on the WorkPlacement itself, such as its approval and
+// version. The Work's approval annotations are not copied, as an approval only
+// applies to the WorkPlacement it was made on.
+func mergeWorkAnnotations(workPlacementAnnotations, workAnnotations map[string]string) map[string]string {
+	if len(workAnnotations) == 0 {
+		return workPlacementAnnotations
+	}
+
+	merged := map[string]string{}
+	for key, value := range workPlacementAnnotations {
+		merged[key] = value
+	}
+	for key, value := range workAnnotations {
+		switch key {
+		case v1alpha1.ApproveAnnotation, v1alpha1.ApprovedRevisionAnnotation, v1alpha1.ApprovedByAnnotation:
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// Creates or updates a WorkPlacement for each target Destination. The
+// WorkPlacements for the heldBack Destinations keep their current workloads.
+func (s *Scheduler) applyWorkplacementsForTargetDestinations(workloadGroup v1alpha1.WorkloadGroup, work *v1alpha1.Work, targetDestinationNames map[string]bool, heldBack map[string]bool) (bool, error) {
 	containsMischeduledWorkplacement := false
 	for targetDestinationName, misscheduled := range targetDestinationNames {
 		workPlacement := &v1alpha1.WorkPlacement{}
@@ -297,13 +636,15 @@ func (s *Scheduler) applyWorkplacementsForTargetDestinations(workloadGroup v1alp
 		workPlacement.Name = work.Name + "." + targetDestinationName + "-" + shortID(workloadGroup.ID)
 
 		op, err := controllerutil.CreateOrUpdate(context.Background(), s.Client, workPlacement, func() error {
-			workPlacement.Spec.Workloads = workloadGroup.Workloads
+			if !heldBack[targetDestinationName] {
+				workPlacement.Spec.Workloads = workloadGroup.Workloads
+			}
 			workPlacement.Labels = map[string]string{
 				workLabelKey:               work.Name,
 				workloadGroupIDKey:         workloadGroup.ID,
 				targetDestinationNameLabel: targetDestinationName,
 			}
-			workPlacement.SetAnnotations(work.GetAnnotations())
+			workPlacement.SetAnnotations(mergeWorkAnnotations(workPlacement.GetAnnotations(), work.GetAnnotations()))
 
 			workPlacement.SetPipelineName(work)
 
@@ -344,22 +685,19 @@ func (s *Scheduler) updateStatus(workPlacement *v1alpha1.WorkPlacement, missched
 
 	var needsUpdate bool
 
-	if misscheduled && updatedWorkPlacement.Status.Conditions == nil {
-		updatedWorkPlacement.Status.Conditions = []v1.Condition{
-			{
-				Message:            "Target destination no longer matches destinationSelectors",
-				Reason:             "DestinationSelectorMismatch",
-				Type:               "Misscheduled",
-				Status:             "True",
-				LastTransitionTime: v1.NewTime(time.Now()),
-			},
-		}
+	if misscheduled && meta.FindStatusCondition(updatedWorkPlacement.Status.Conditions, misscheduledConditionType) == nil {
+		meta.SetStatusCondition(&updatedWorkPlacement.Status.Conditions, v1.Condition{
+			Message:            "Target destination no longer matches destinationSelectors",
+			Reason:             "DestinationSelectorMismatch",
+			Type:               misscheduledConditionType,
+			Status:             "True",
+			LastTransitionTime: v1.NewTime(time.Now()),
+		})
 		needsUpdate = true
 	}
 
-	if !misscheduled && len(updatedWorkPlacement.Status.Conditions) > 0 {
-		updatedWorkPlacement.Status.Conditions = nil
-		needsUpdate = true
+	if !misscheduled {
+		needsUpdate = meta.RemoveStatusCondition(&updatedWorkPlacement.Status.Conditions, misscheduledConditionType)
 	}
 
 	if !needsUpdate {
@@ -369,22 +707,69 @@ func (s *Scheduler) updateStatus(workPlacement *v1alpha1.WorkPlacement, missched
 	return s.Client.Status().Update(context.Background(), updatedWorkPlacement)
 }
 
-// Where Work is a Resource Request return one random Destination name, where Work is a
-// DestinationWorkerResource return all Destination names
-func (s *Scheduler) getTargetDestinationNames(destinationSelectors map[string]string, work *v1alpha1.Work) []string {
-	destinations := s.getDestinationsForWorkloadGroup(destinationSelectors)
+// Where Work is a Resource Request return a Destination name for each replica
+// that is not yet scheduled, chosen by the scheduling strategy, where Work is a
+// DestinationWorkerResource return all Destination names. Also returns whether
+// there are matching Destinations that were skipped as they are at capacity.
+func (s *Scheduler) getTargetDestinationNames(destinationSelectors metav1.LabelSelector, tolerations []v1alpha1.Toleration, workloadGroup v1alpha1.WorkloadGroup, work *v1alpha1.Work, promise *v1alpha1.Promise, existingWorkplacements []v1alpha1.WorkPlacement, cache *destinationCache) ([]string, bool, error) {
+	matchingDestinations := s.getMatchingDestinations(cache, destinationSelectors)
+	destinations := filterDestinationsByTaints(schedulableDestinations(matchingDestinations), tolerations)
 
 	if len(destinations) == 0 {
-		return make([]string, 0)
+		return make([]string, 0), false, nil
 	}
 
 	if work.IsResourceRequest() {
 		s.Log.Info("Getting Destination names for Resource Request")
-		var targetDestinationNames = make([]string, 1)
-		randomDestinationIndex := rand.Intn(len(destinations))
-		targetDestinationNames[0] = destinations[randomDestinationIndex].Name
-		s.Log.Info("Adding Destination: " + targetDestinationNames[0])
-		return targetDestinationNames
+		namespaceLabels, err := s.getNamespaceLabels(work.Namespace)
+		if err != nil {
+			return nil, false, err
+		}
+		destinations = filterDestinationsByNamespaceAccess(destinations, work.Namespace, namespaceLabels)
+
+		affinity, err := s.newResourceAffinity(work, workloadGroup, cache.resourceRequests)
+		if err != nil {
+			return nil, false, err
+		}
+		destinations = affinity.filter(destinations)
+
+		destinationsWithCapacity, err := s.filterDestinationsWithCapacity(promise, destinations)
+		if err != nil {
+			return nil, false, err
+		}
+
+		strategy, err := s.schedulingStrategyFor(promise)
+		if err != nil {
+			return nil, false, err
+		}
+
+		var scheduledTo []v1alpha1.Destination
+		for _, destination := range matchingDestinations {
+			if slices.ContainsFunc(existingWorkplacements, func(wp v1alpha1.WorkPlacement) bool {
+				return wp.Spec.TargetDestinationName == destination.Name
+			}) {
+				scheduledTo = append(scheduledTo, destination)
+			}
+		}
+
+		var constraints []v1alpha1.TopologySpreadConstraint
+		if promise != nil {
+			constraints = promise.Spec.SchedulingPolicy.TopologySpreadConstraints
+		}
+		spread := newTopologySpread(constraints, destinations, scheduledTo)
+
+		replicas := promise.GetReplicas() - len(existingWorkplacements)
+		preferred := resolvePreferredSelectorsForWorkloadGroup(workloadGroup)
+		destinationNames, err := chooseReplicaDestinations(strategy, work, workloadGroup, destinationsWithCapacity, scheduledTo, tolerations, preferred, spread, replicas)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, destinationName := range destinationNames {
+			s.Log.Info("Adding Destination: " + destinationName)
+		}
+
+		atCapacity := len(destinationNames) < replicas && len(destinationsWithCapacity) < len(destinations)
+		return destinationNames, atCapacity, nil
 	} else if work.IsDependency() {
 		s.Log.Info("Getting Destination names for dependencies")
 		var targetDestinationNames = make([]string, len(destinations))
@@ -392,38 +777,39 @@ func (s *Scheduler) getTargetDestinationNames(destinationSelectors map[string]st
 			targetDestinationNames[i] = destinations[i].Name
 			s.Log.Info("Adding Destination: " + targetDestinationNames[i])
 		}
-		return targetDestinationNames
+		return targetDestinationNames, false, nil
 	} else {
 		s.Log.Info("Work is neither resource request nor dependency")
-		return make([]string, 0)
+		return make([]string, 0), false, nil
 	}
 }
 
 // By default, all destinations are returned. However, if scheduling is provided, only matching destinations will be returned.
-func (s *Scheduler) getDestinationsForWorkloadGroup(destinationSelectors map[string]string) []v1alpha1.Destination {
-	destinationList := &v1alpha1.DestinationList{}
-	lo := &client.ListOptions{}
-
-	if len(destinationSelectors) > 0 {
-		workloadGroupSelectorLabel := labels.FormatLabels(destinationSelectors)
-		//<none> is valid output from above
-		selector, err := labels.Parse(workloadGroupSelectorLabel)
-
+// Cordoned destinations are included, as works already scheduled to them still
+// match.
+func (s *Scheduler) getMatchingDestinations(cache *destinationCache, destinationSelectors metav1.LabelSelector) []v1alpha1.Destination {
+	var selector labels.Selector
+	hasSelectors := !isEmptyLabelSelector(destinationSelectors)
+	if hasSelectors {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(&destinationSelectors)
 		if err != nil {
+			// an invalid selector must not match every Destination
 			s.Log.Error(err, "error parsing scheduling")
+			return []v1alpha1.Destination{}
 		}
-		lo.LabelSelector = selector
 	}
 
-	err := s.Client.List(context.Background(), destinationList, lo)
+	allDestinations, err := cache.list()
 	if err != nil {
 		s.Log.Error(err, "Error listing available Destinations")
 	}
 
 	destinations := []v1alpha1.Destination{}
-	for _, destination := range destinationList.Items {
+	for _, destination := range allDestinations {
 		if !destination.DeletionTimestamp.IsZero() ||
-			(len(destinationSelectors) == 0 && (destination.Spec.StrictMatchLabels && len(destination.GetLabels()) > 0)) {
+			(hasSelectors && !selector.Matches(labels.Set(destination.GetLabels()))) ||
+			(!hasSelectors && (destination.Spec.StrictMatchLabels && len(destination.GetLabels()) > 0)) {
 			continue
 		}
 		destinations = append(destinations, destination)
@@ -431,45 +817,70 @@ func (s *Scheduler) getDestinationsForWorkloadGroup(destinationSelectors map[str
 	return destinations
 }
 
-func resolveDestinationSelectorsForWorkloadGroup(workloadGroup v1alpha1.WorkloadGroup, work *v1alpha1.Work) map[string]string {
+// Merges the selectors from all sources into a single selector. When several
+// sources constrain the same label key, the highest priority source wins, and
+// both its matchLabels and matchExpressions for that key replace those of the
+// lower priority sources.
+func resolveDestinationSelectorsForWorkloadGroup(workloadGroup v1alpha1.WorkloadGroup, work *v1alpha1.Work) metav1.LabelSelector {
 	sortedWorkloadGroupDestinations := sortWorkloadGroupDestinationsByLowestPriority(workloadGroup.DestinationSelectors)
-	destinationSelectors := map[string]string{}
+	matchLabels := map[string]string{}
+	matchExpressions := map[string][]metav1.LabelSelectorRequirement{}
 
 	for _, scheduling := range sortedWorkloadGroupDestinations {
+		for key := range scheduling.MatchLabels {
+			delete(matchExpressions, key)
+		}
+		for _, expression := range scheduling.MatchExpressions {
+			delete(matchLabels, expression.Key)
+			delete(matchExpressions, expression.Key)
+		}
+
 		for key, value := range scheduling.MatchLabels {
-			destinationSelectors[key] = value
+			matchLabels[key] = value
+		}
+		for _, expression := range scheduling.MatchExpressions {
+			matchExpressions[expression.Key] = append(matchExpressions[expression.Key], expression)
 		}
 	}
 
+	destinationSelectors := metav1.LabelSelector{}
+	if len(matchLabels) > 0 {
+		destinationSelectors.MatchLabels = matchLabels
+	}
+
+	keys := make([]string, 0, len(matchExpressions))
+	for key := range matchExpressions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		destinationSelectors.MatchExpressions = append(destinationSelectors.MatchExpressions, matchExpressions[key]...)
+	}
+
 	return destinationSelectors
 }
 
+func isEmptyLabelSelector(selector metav1.LabelSelector) bool {
+	return len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0
+}
+
+// The priority of each source of WorkloadGroup scheduling; sources not listed
+// have the lowest priority
+var workloadGroupSchedulingSourcePriority = map[string]int{
+	"resource-workflow":                      0,
+	v1alpha1.SchedulingSourceResourceRequest: 1,
+	"promise-workflow":                       2,
+	"promise":                                3,
+}
+
 // Returned in order:
 // Resource-workflow, then
+// Resource-request, then
 // Promise-workflow, then
 // Promise
 func sortWorkloadGroupDestinationsByLowestPriority(selector []v1alpha1.WorkloadGroupScheduling) []v1alpha1.WorkloadGroupScheduling {
 	sort.SliceStable(selector, func(i, j int) bool {
-		iSource := selector[i].Source
-		jSource := selector[j].Source
-		if iSource == "promise" {
-			return false
-		}
-
-		if jSource == "promise" {
-			return true
-		}
-
-		if iSource == "promise-workflow" {
-			return false
-		}
-
-		if jSource == "promise-workflow" {
-			return true
-		}
-
-		//if we get here both are resource-workflow, so just let it take precedent
-		return false
+		return workloadGroupSchedulingSourcePriority[selector[i].Source] < workloadGroupSchedulingSourcePriority[selector[j].Source]
 	})
 	return selector
 }
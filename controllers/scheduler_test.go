@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
@@ -13,8 +14,12 @@ import (
 	. "github.com/syntasso/kratix/api/v1alpha1"
 	"github.com/syntasso/kratix/lib/compression"
 	"github.com/syntasso/kratix/lib/hash"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -52,6 +57,59 @@ var _ = Describe("Controllers/Scheduler", func() {
 		}
 	})
 
+	createDestination := func(name string, labels map[string]string, updates ...func(*Destination)) {
+		destination := newDestination(name, labels)
+		for _, update := range updates {
+			update(&destination)
+		}
+		Expect(fakeK8sClient.Create(context.Background(), &destination)).To(Succeed())
+	}
+
+	// reconciles the latest version of the Work, returning the IDs of the
+	// WorkloadGroups that could not be scheduled
+	reconcileWork := func(work *Work) []string {
+		Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(work), work)).To(Succeed())
+		unschedulable, err := scheduler.ReconcileWork(work)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(work), work)).To(Succeed())
+		return unschedulable
+	}
+
+	// the WorkPlacements of the Work that are not being deleted
+	placementsFor := func(work Work) []WorkPlacement {
+		wps := WorkPlacementList{}
+		Expect(fakeK8sClient.List(context.Background(), &wps, client.MatchingLabels{"kratix.io/work": work.Name})).To(Succeed())
+		var placed []WorkPlacement
+		for _, wp := range wps.Items {
+			if wp.DeletionTimestamp.IsZero() {
+				placed = append(placed, wp)
+			}
+		}
+		return placed
+	}
+
+	destinationsFor := func(work Work) []string {
+		var names []string
+		for _, wp := range placementsFor(work) {
+			names = append(names, wp.Spec.TargetDestinationName)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	// marks the WorkPlacements of the Work as written to their Destinations
+	markWritten := func(work Work) {
+		for _, wp := range placementsFor(work) {
+			meta.SetStatusCondition(&wp.Status.Conditions, v1.Condition{
+				Type:               "WriteSucceeded",
+				Status:             v1.ConditionTrue,
+				Reason:             "WorkloadsWritten",
+				ObservedGeneration: wp.Generation,
+			})
+			Expect(fakeK8sClient.Status().Update(context.Background(), &wp)).To(Succeed())
+		}
+	}
+
 	Describe("#ReconcileWork", func() {
 		Describe("Scheduling Resources", func() {
 			var resourceWork, resourceWorkWithMultipleGroups Work
@@ -89,6 +147,15 @@ var _ = Describe("Controllers/Scheduler", func() {
 					Expect(workPlacement.GetAnnotations()).To(Equal(resourceWork.GetAnnotations()))
 				})
 
+				It("marks new WorkPlacements to be orphaned when the Work is", func() {
+					orphanedWork := newWork("orphaned-work", true, schedulingFor(devDestination))
+					orphanedWork.Annotations[DeletionPolicyAnnotation] = string(DeletionPolicyOrphan)
+					Expect(fakeK8sClient.Update(context.Background(), &orphanedWork)).To(Succeed())
+					reconcileWork(&orphanedWork)
+
+					Expect(placementsFor(orphanedWork)[0].GetAnnotations()).To(HaveKeyWithValue(DeletionPolicyAnnotation, "Orphan"))
+				})
+
 				It("sets the scheduling conditions on the Work", func() {
 					Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(&resourceWork), &resourceWork))
 					Expect(resourceWork.Status.Conditions).To(HaveLen(2))
@@ -536,6 +603,1283 @@ var _ = Describe("Controllers/Scheduler", func() {
 			})
 		})
 
+		Describe("Scheduling Strategies", func() {
+			var promise *Promise
+
+			scheduledDestinationFor := func(work Work) string {
+				wps := WorkPlacementList{}
+				Expect(fakeK8sClient.List(context.Background(), &wps, client.MatchingLabels{"kratix.io/work": work.Name})).To(Succeed())
+				Expect(wps.Items).To(HaveLen(1))
+				return wps.Items[0].Spec.TargetDestinationName
+			}
+
+			scheduleNewWork := func(name string) string {
+				work := newWork(name, true, schedulingFor(devDestination))
+				_, err := scheduler.ReconcileWork(&work)
+				Expect(err).ToNot(HaveOccurred())
+				return scheduledDestinationFor(work)
+			}
+
+			BeforeEach(func() {
+				promise = &Promise{ObjectMeta: v1.ObjectMeta{Name: "promise"}}
+			})
+
+			When("the Promise uses the roundRobin strategy", func() {
+				BeforeEach(func() {
+					promise.Spec.SchedulingPolicy.Strategy = SchedulingStrategyRoundRobin
+					Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+				})
+
+				It("schedules to each matching Destination in turn", func() {
+					Expect([]string{
+						scheduleNewWork("rr-work-1"),
+						scheduleNewWork("rr-work-2"),
+						scheduleNewWork("rr-work-3"),
+					}).To(Equal([]string{"dev-1", "dev-2", "dev-1"}))
+				})
+			})
+
+			When("the Promise uses the leastLoaded strategy", func() {
+				BeforeEach(func() {
+					promise.Spec.SchedulingPolicy.Strategy = SchedulingStrategyLeastLoaded
+					Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+
+					createWorkPlacement := func(name, destination, resourceName string) *WorkPlacement {
+						wp := &WorkPlacement{
+							ObjectMeta: v1.ObjectMeta{
+								Name:       name,
+								Namespace:  "default",
+								Labels:     map[string]string{"kratix.io/targetDestinationName": destination},
+								Finalizers: []string{"test-finalizer"},
+							},
+							Spec: WorkPlacementSpec{TargetDestinationName: destination, ResourceName: resourceName},
+						}
+						Expect(fakeK8sClient.Create(context.Background(), wp)).To(Succeed())
+						return wp
+					}
+					createWorkPlacement("existing-1", "dev-1", "resource-1")
+					createWorkPlacement("existing-2", "dev-1", "resource-2")
+
+					By("not counting dependencies or WorkPlacements being deleted")
+					createWorkPlacement("dependency-1", "dev-2", "")
+					createWorkPlacement("dependency-2", "dev-2", "")
+					createWorkPlacement("dependency-3", "dev-2", "")
+					for _, name := range []string{"deleting-1", "deleting-2", "deleting-3"} {
+						Expect(fakeK8sClient.Delete(context.Background(), createWorkPlacement(name, "dev-2", name))).To(Succeed())
+					}
+				})
+
+				It("schedules to the matching Destination with the fewest resource WorkPlacements", func() {
+					Expect(scheduleNewWork("rr-work-1")).To(Equal("dev-2"))
+					Expect(scheduleNewWork("rr-work-2")).To(Equal("dev-2"))
+					Expect(scheduleNewWork("rr-work-3")).To(Equal("dev-1"))
+				})
+			})
+
+			When("the Promise uses the consistentHash strategy", func() {
+				BeforeEach(func() {
+					promise.Spec.SchedulingPolicy.Strategy = SchedulingStrategyConsistentHash
+					Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+				})
+
+				It("always schedules a resource to the same Destination", func() {
+					work := newWork("rr-work", true, schedulingFor(devDestination))
+					_, err := scheduler.ReconcileWork(&work)
+					Expect(err).ToNot(HaveOccurred())
+					firstDestination := scheduledDestinationFor(work)
+
+					for i := 0; i < 5; i++ {
+						Expect(fakeK8sClient.DeleteAllOf(context.Background(), &WorkPlacement{}, client.InNamespace("default"))).To(Succeed())
+						Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(&work), &work)).To(Succeed())
+						newScheduler := &Scheduler{Client: fakeK8sClient, Log: ctrl.Log}
+						_, err := newScheduler.ReconcileWork(&work)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(scheduledDestinationFor(work)).To(Equal(firstDestination))
+					}
+				})
+			})
+
+			When("the Promise does not set a strategy", func() {
+				BeforeEach(func() {
+					Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+					scheduler.DefaultSchedulingStrategy = SchedulingStrategyRoundRobin
+				})
+
+				It("uses the default strategy of the Scheduler", func() {
+					Expect([]string{
+						scheduleNewWork("rr-work-1"),
+						scheduleNewWork("rr-work-2"),
+					}).To(Equal([]string{"dev-1", "dev-2"}))
+				})
+			})
+
+			When("the Promise strategy differs from the default strategy", func() {
+				BeforeEach(func() {
+					promise.Spec.SchedulingPolicy.Strategy = SchedulingStrategyConsistentHash
+					Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+					scheduler.DefaultSchedulingStrategy = SchedulingStrategyRoundRobin
+				})
+
+				It("uses the Promise strategy", func() {
+					first := scheduleNewWork("rr-work-1")
+					Expect(scheduleNewWork("rr-work-2")).To(Equal(first))
+				})
+			})
+
+			When("a custom strategy is registered", func() {
+				BeforeEach(func() {
+					scheduler.DefaultSchedulingStrategy = "always-dev-2"
+					scheduler.RegisterSchedulingStrategy("always-dev-2", &fixedStrategy{destination: "dev-2"})
+				})
+
+				It("uses the custom strategy", func() {
+					Expect(scheduler.HasSchedulingStrategy("always-dev-2")).To(BeTrue())
+					Expect(scheduleNewWork("rr-work-1")).To(Equal("dev-2"))
+				})
+
+				It("uses the custom strategy when the Promise sets it", func() {
+					scheduler.DefaultSchedulingStrategy = SchedulingStrategyRoundRobin
+					promise.Spec.SchedulingPolicy.Strategy = "always-dev-2"
+					Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+					Expect(scheduleNewWork("rr-work-1")).To(Equal("dev-2"))
+				})
+			})
+
+			When("the strategy is unknown", func() {
+				It("errors", func() {
+					Expect(scheduler.HasSchedulingStrategy("unknown")).To(BeFalse())
+					scheduler.DefaultSchedulingStrategy = "unknown"
+					work := newWork("rr-work", true, schedulingFor(devDestination))
+					_, err := scheduler.ReconcileWork(&work)
+					Expect(err).To(MatchError(`unknown scheduling strategy "unknown"`))
+				})
+			})
+
+			When("a resource is already scheduled to one of several matching Destinations", func() {
+				It("is never considered misscheduled", func() {
+					work := newWork("rr-work", true, schedulingFor(devDestination))
+					_, err := scheduler.ReconcileWork(&work)
+					Expect(err).ToNot(HaveOccurred())
+
+					for i := 0; i < 10; i++ {
+						Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(&work), &work)).To(Succeed())
+						_, err := scheduler.ReconcileWork(&work)
+						Expect(err).ToNot(HaveOccurred())
+					}
+
+					Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(&work), &work)).To(Succeed())
+					Expect(work.Status.Conditions[1].Status).To(Equal(v1.ConditionFalse))
+				})
+			})
+		})
+
+		Describe("Destination Capacity", func() {
+			var promise *Promise
+
+			setCapacity := func(destination Destination, capacity *DestinationCapacity) {
+				Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(&destination), &destination)).To(Succeed())
+				destination.Spec.Capacity = capacity
+				Expect(fakeK8sClient.Update(context.Background(), &destination)).To(Succeed())
+			}
+
+			placeResource := func(name, destination string) {
+				Expect(fakeK8sClient.Create(context.Background(), &WorkPlacement{
+					ObjectMeta: v1.ObjectMeta{
+						Name:      name,
+						Namespace: "default",
+						Labels:    map[string]string{"kratix.io/targetDestinationName": destination},
+					},
+					Spec: WorkPlacementSpec{
+						TargetDestinationName: destination,
+						PromiseName:           "promise",
+						ResourceName:          name,
+					},
+				})).To(Succeed())
+			}
+
+			scheduleNewWork := func(name string) Work {
+				work := newWork(name, true, schedulingFor(devDestination))
+				_, err := scheduler.ReconcileWork(&work)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(&work), &work)).To(Succeed())
+				return work
+			}
+
+			BeforeEach(func() {
+				promise = &Promise{ObjectMeta: v1.ObjectMeta{Name: "promise"}}
+				scheduler.DefaultSchedulingStrategy = SchedulingStrategyRoundRobin
+			})
+
+			When("a Destination has reached its maxWorkPlacements", func() {
+				BeforeEach(func() {
+					Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+					setCapacity(devDestination, &DestinationCapacity{MaxWorkPlacements: ptr.To(1)})
+					placeResource("existing", "dev-1")
+				})
+
+				It("schedules to a Destination with capacity", func() {
+					work := scheduleNewWork("rr-work")
+					wps := WorkPlacementList{}
+					Expect(fakeK8sClient.List(context.Background(), &wps, client.MatchingLabels{"kratix.io/work": work.Name})).To(Succeed())
+					Expect(wps.Items).To(HaveLen(1))
+					Expect(wps.Items[0].Spec.TargetDestinationName).To(Equal("dev-2"))
+				})
+
+				It("does not count Promise WorkPlacements towards the limit", func() {
+					setCapacity(devDestination, &DestinationCapacity{MaxWorkPlacements: ptr.To(2)})
+					Expect(fakeK8sClient.Create(context.Background(), &WorkPlacement{
+						ObjectMeta: v1.ObjectMeta{
+							Name:      "dependency",
+							Namespace: "default",
+							Labels:    map[string]string{"kratix.io/targetDestinationName": "dev-1"},
+						},
+						Spec: WorkPlacementSpec{TargetDestinationName: "dev-1", PromiseName: "promise"},
+					})).To(Succeed())
+
+					work := scheduleNewWork("rr-work")
+					wps := WorkPlacementList{}
+					Expect(fakeK8sClient.List(context.Background(), &wps, client.MatchingLabels{"kratix.io/work": work.Name})).To(Succeed())
+					Expect(wps.Items[0].Spec.TargetDestinationName).To(Equal("dev-1"))
+				})
+
+				It("does not count WorkPlacements being deleted towards the limit", func() {
+					existing := &WorkPlacement{}
+					Expect(fakeK8sClient.Get(context.Background(), client.ObjectKey{Name: "existing", Namespace: "default"}, existing)).To(Succeed())
+					existing.SetFinalizers([]string{"test-finalizer"})
+					Expect(fakeK8sClient.Update(context.Background(), existing)).To(Succeed())
+					Expect(fakeK8sClient.Delete(context.Background(), existing)).To(Succeed())
+					setCapacity(devDestination2, &DestinationCapacity{MaxWorkPlacements: ptr.To(0)})
+
+					work := scheduleNewWork("rr-work")
+					wps := WorkPlacementList{}
+					Expect(fakeK8sClient.List(context.Background(), &wps, client.MatchingLabels{"kratix.io/work": work.Name})).To(Succeed())
+					Expect(wps.Items).To(HaveLen(1))
+					Expect(wps.Items[0].Spec.TargetDestinationName).To(Equal("dev-1"))
+				})
+			})
+
+			When("the Promise requests capacity in a dimension the Destination limits", func() {
+				BeforeEach(func() {
+					promise.Spec.SchedulingPolicy.CapacityRequests = map[string]int{"cpu": 2, "databases": 1}
+					Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+					setCapacity(devDestination, &DestinationCapacity{Dimensions: map[string]int{"cpu": 3}})
+					placeResource("existing", "dev-1")
+				})
+
+				It("schedules to a Destination with enough of that capacity left", func() {
+					work := scheduleNewWork("rr-work")
+					wps := WorkPlacementList{}
+					Expect(fakeK8sClient.List(context.Background(), &wps, client.MatchingLabels{"kratix.io/work": work.Name})).To(Succeed())
+					Expect(wps.Items[0].Spec.TargetDestinationName).To(Equal("dev-2"))
+				})
+			})
+
+			When("all matching Destinations are at capacity", func() {
+				var work Work
+
+				BeforeEach(func() {
+					Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+					setCapacity(devDestination, &DestinationCapacity{MaxWorkPlacements: ptr.To(0)})
+					setCapacity(devDestination2, &DestinationCapacity{MaxWorkPlacements: ptr.To(0)})
+					work = newWork("rr-work", true, schedulingFor(devDestination))
+				})
+
+				It("does not schedule the Work and reports the Destinations are at capacity", func() {
+					unscheduled, err := scheduler.ReconcileWork(&work)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(unscheduled).To(ConsistOf(work.Spec.WorkloadGroups[0].ID))
+
+					Expect(fakeK8sClient.List(context.Background(), &workPlacements)).To(Succeed())
+					Expect(workPlacements.Items).To(BeEmpty())
+
+					Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(&work), &work)).To(Succeed())
+					Expect(work.Status.Conditions[0].Type).To(Equal("Scheduled"))
+					Expect(work.Status.Conditions[0].Status).To(Equal(v1.ConditionFalse))
+					Expect(work.Status.Conditions[0].Reason).To(Equal("DestinationsAtCapacity"))
+					Expect(work.Status.Conditions[0].Message).To(Equal("No Destinations with available capacity for WorkloadGroups: [" + work.Spec.WorkloadGroups[0].ID + "]"))
+				})
+			})
+
+			When("a Destination becomes full after a resource is scheduled to it", func() {
+				It("does not mark the resource as misscheduled", func() {
+					Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+					work := scheduleNewWork("rr-work")
+
+					setCapacity(devDestination, &DestinationCapacity{MaxWorkPlacements: ptr.To(0)})
+					setCapacity(devDestination2, &DestinationCapacity{MaxWorkPlacements: ptr.To(0)})
+
+					_, err := scheduler.ReconcileWork(&work)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(&work), &work)).To(Succeed())
+					Expect(work.Status.Conditions[0].Status).To(Equal(v1.ConditionTrue))
+					Expect(work.Status.Conditions[1].Status).To(Equal(v1.ConditionFalse))
+				})
+			})
+		})
+
+		Describe("Destination Taints", func() {
+			setTaints := func(destination Destination, taints ...DestinationTaint) {
+				Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(&destination), &destination)).To(Succeed())
+				destination.Spec.Taints = taints
+				Expect(fakeK8sClient.Update(context.Background(), &destination)).To(Succeed())
+			}
+
+			devScheduling := func(tolerations ...Toleration) WorkloadGroupScheduling {
+				scheduling := schedulingFor(devDestination)
+				scheduling.Tolerations = tolerations
+				return scheduling
+			}
+
+			gpuTaint := func(effect TaintEffect) DestinationTaint {
+				return DestinationTaint{Key: "gpu", Value: "true", Effect: effect}
+			}
+
+			BeforeEach(func() {
+				scheduler.DefaultSchedulingStrategy = "always-dev-1"
+				scheduler.RegisterSchedulingStrategy("always-dev-1", &preferredStrategy{destination: "dev-1"})
+			})
+
+			When("a Destination has a NoSchedule taint", func() {
+				BeforeEach(func() {
+					setTaints(devDestination, gpuTaint(TaintEffectNoSchedule))
+				})
+
+				It("does not schedule resources that don't tolerate it to the Destination", func() {
+					work := newWork("rr-work", true, devScheduling())
+					reconcileWork(&work)
+					Expect(destinationsFor(work)).To(ConsistOf("dev-2"))
+				})
+
+				It("schedules resources that tolerate it to the Destination", func() {
+					work := newWork("rr-work", true, devScheduling(Toleration{Key: "gpu", Value: "true"}))
+					reconcileWork(&work)
+					Expect(destinationsFor(work)).To(ConsistOf("dev-1"))
+				})
+
+				It("does not schedule dependencies that don't tolerate it to the Destination", func() {
+					work := newWork("dependency-work", false, devScheduling())
+					reconcileWork(&work)
+					Expect(destinationsFor(work)).To(ConsistOf("dev-2"))
+				})
+
+				It("schedules dependencies that tolerate it to the Destination", func() {
+					work := newWork("dependency-work", false, devScheduling(Toleration{Key: "gpu", Operator: TolerationOpExists}))
+					reconcileWork(&work)
+					Expect(destinationsFor(work)).To(ConsistOf("dev-1", "dev-2"))
+				})
+			})
+
+			When("a NoSchedule taint is added after the work is scheduled", func() {
+				It("does not move or misschedule the existing WorkPlacements", func() {
+					setTaints(devDestination)
+					resourceWork := newWork("rr-work", true, devScheduling())
+					dependencyWork := newWork("dependency-work", false, devScheduling())
+					reconcileWork(&resourceWork)
+					reconcileWork(&dependencyWork)
+
+					setTaints(devDestination, gpuTaint(TaintEffectNoSchedule))
+					reconcileWork(&resourceWork)
+					reconcileWork(&dependencyWork)
+
+					Expect(destinationsFor(resourceWork)).To(ConsistOf("dev-1"))
+					Expect(destinationsFor(dependencyWork)).To(ConsistOf("dev-1", "dev-2"))
+					Expect(resourceWork.Status.Conditions[1].Status).To(Equal(v1.ConditionFalse))
+					Expect(dependencyWork.Status.Conditions[1].Status).To(Equal(v1.ConditionFalse))
+				})
+			})
+
+			When("a Destination has a PreferNoSchedule taint", func() {
+				It("schedules resources to other Destinations when possible", func() {
+					setTaints(devDestination, gpuTaint(TaintEffectPreferNoSchedule))
+					work := newWork("rr-work", true, devScheduling())
+					reconcileWork(&work)
+					Expect(destinationsFor(work)).To(ConsistOf("dev-2"))
+				})
+
+				It("schedules resources to the Destination when all Destinations have the taint", func() {
+					setTaints(devDestination, gpuTaint(TaintEffectPreferNoSchedule))
+					setTaints(devDestination2, gpuTaint(TaintEffectPreferNoSchedule))
+					work := newWork("rr-work", true, devScheduling())
+					reconcileWork(&work)
+					Expect(destinationsFor(work)).To(ConsistOf("dev-1"))
+				})
+
+				It("still schedules dependencies to the Destination", func() {
+					setTaints(devDestination, gpuTaint(TaintEffectPreferNoSchedule))
+					work := newWork("dependency-work", false, devScheduling())
+					reconcileWork(&work)
+					Expect(destinationsFor(work)).To(ConsistOf("dev-1", "dev-2"))
+				})
+			})
+
+			When("a NoExecute taint is added after the work is scheduled", func() {
+				var resourceWork, dependencyWork Work
+
+				BeforeEach(func() {
+					resourceWork = newWork("rr-work", true, devScheduling())
+					dependencyWork = newWork("dependency-work", false, devScheduling())
+					reconcileWork(&resourceWork)
+					reconcileWork(&dependencyWork)
+					Expect(destinationsFor(resourceWork)).To(ConsistOf("dev-1"))
+
+					setTaints(devDestination, gpuTaint(TaintEffectNoExecute))
+				})
+
+				It("reschedules resources that don't tolerate it to another Destination once they are written there", func() {
+					Expect(reconcileWork(&resourceWork)).To(ConsistOf(resourceWork.Spec.WorkloadGroups[0].ID))
+					Expect(destinationsFor(resourceWork)).To(ConsistOf("dev-1", "dev-2"))
+
+					markWritten(resourceWork)
+					Expect(reconcileWork(&resourceWork)).To(BeEmpty())
+					Expect(destinationsFor(resourceWork)).To(ConsistOf("dev-2"))
+					Expect(resourceWork.Status.Conditions[0].Status).To(Equal(v1.ConditionTrue))
+				})
+
+				It("removes dependencies that don't tolerate it from the Destination", func() {
+					reconcileWork(&dependencyWork)
+					Expect(destinationsFor(dependencyWork)).To(ConsistOf("dev-2"))
+				})
+
+				It("keeps resources on the Destination when there is no other Destination available", func() {
+					setTaints(devDestination2, gpuTaint(TaintEffectNoExecute))
+					Expect(reconcileWork(&resourceWork)).To(ConsistOf(resourceWork.Spec.WorkloadGroups[0].ID))
+					Expect(destinationsFor(resourceWork)).To(ConsistOf("dev-1"))
+
+					markWritten(resourceWork)
+					Expect(reconcileWork(&resourceWork)).To(ConsistOf(resourceWork.Spec.WorkloadGroups[0].ID))
+					Expect(destinationsFor(resourceWork)).To(ConsistOf("dev-1"))
+				})
+
+				It("does not evict works that tolerate it", func() {
+					resourceWork.Spec.WorkloadGroups[0].DestinationSelectors = []WorkloadGroupScheduling{
+						devScheduling(Toleration{Key: "gpu", Effect: TaintEffectNoExecute, Operator: TolerationOpExists}),
+					}
+					Expect(fakeK8sClient.Update(context.Background(), &resourceWork)).To(Succeed())
+					reconcileWork(&resourceWork)
+					Expect(destinationsFor(resourceWork)).To(ConsistOf("dev-1"))
+				})
+			})
+		})
+
+		Describe("Replicas and Topology Spread", func() {
+			var promise *Promise
+			var work Work
+			var dbScheduling WorkloadGroupScheduling
+
+			regionsOf := func(destinations []string) []string {
+				var regions []string
+				for _, name := range destinations {
+					destination := Destination{}
+					Expect(fakeK8sClient.Get(context.Background(), types.NamespacedName{Name: name}, &destination)).To(Succeed())
+					regions = append(regions, destination.GetLabels()["region"])
+				}
+				return regions
+			}
+
+			setReplicas := func(replicas int) {
+				Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(promise), promise)).To(Succeed())
+				promise.Spec.SchedulingPolicy.Replicas = ptr.To(replicas)
+				Expect(fakeK8sClient.Update(context.Background(), promise)).To(Succeed())
+			}
+
+			BeforeEach(func() {
+				createDestination("db-eu-1", map[string]string{"tier": "db", "region": "eu"})
+				createDestination("db-eu-2", map[string]string{"tier": "db", "region": "eu"})
+				createDestination("db-eu-3", map[string]string{"tier": "db", "region": "eu"})
+				createDestination("db-us-1", map[string]string{"tier": "db", "region": "us"})
+
+				scheduler.DefaultSchedulingStrategy = SchedulingStrategyRoundRobin
+				promise = &Promise{ObjectMeta: v1.ObjectMeta{Name: "promise"}}
+				dbScheduling = WorkloadGroupScheduling{MatchLabels: map[string]string{"tier": "db"}, Source: "promise"}
+				work = newWork("rr-work", true, dbScheduling)
+			})
+
+			When("the Promise sets replicas", func() {
+				BeforeEach(func() {
+					promise.Spec.SchedulingPolicy.Replicas = ptr.To(3)
+					Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+					Expect(reconcileWork(&work)).To(BeEmpty())
+				})
+
+				It("schedules each replica to a different Destination", func() {
+					destinations := destinationsFor(work)
+					Expect(destinations).To(HaveLen(3))
+					Expect(destinations).To(HaveEach(HavePrefix("db-")))
+				})
+
+				It("reports the chosen Destinations on the Work status", func() {
+					Expect(work.Status.WorkloadGroups).To(ConsistOf(WorkloadGroupStatus{
+						ID:           work.Spec.WorkloadGroups[0].ID,
+						Destinations: destinationsFor(work),
+					}))
+					Expect(work.Status.Conditions[0].Status).To(Equal(v1.ConditionTrue))
+				})
+
+				It("schedules more replicas when replicas is increased", func() {
+					before := destinationsFor(work)
+					setReplicas(4)
+					Expect(reconcileWork(&work)).To(BeEmpty())
+					Expect(destinationsFor(work)).To(ConsistOf("db-eu-1", "db-eu-2", "db-eu-3", "db-us-1"))
+					Expect(destinationsFor(work)).To(ContainElements(before))
+				})
+
+				It("removes replicas when replicas is decreased", func() {
+					setReplicas(1)
+					Expect(reconcileWork(&work)).To(BeEmpty())
+					Expect(destinationsFor(work)).To(HaveLen(1))
+					Expect(work.Status.WorkloadGroups[0].Destinations).To(HaveLen(1))
+				})
+			})
+
+			When("there are fewer matching Destinations than replicas", func() {
+				It("schedules as many replicas as it can and reports the WorkloadGroup as partially scheduled", func() {
+					promise.Spec.SchedulingPolicy.Replicas = ptr.To(5)
+					Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+
+					Expect(reconcileWork(&work)).To(ConsistOf(work.Spec.WorkloadGroups[0].ID))
+					Expect(destinationsFor(work)).To(HaveLen(4))
+					Expect(work.Status.Conditions[0].Status).To(Equal(v1.ConditionFalse))
+					Expect(work.Status.Conditions[0].Reason).To(Equal("PartiallyScheduledWorkloadGroups"))
+					Expect(work.Status.Conditions[0].Message).To(Equal("Not enough Destinations available for all replicas of WorkloadGroups: [" + work.Spec.WorkloadGroups[0].ID + "]"))
+				})
+			})
+
+			When("the Promise has a topology spread constraint", func() {
+				BeforeEach(func() {
+					promise.Spec.SchedulingPolicy.TopologySpreadConstraints = []TopologySpreadConstraint{
+						{TopologyKey: "region", MaxSkew: 1},
+					}
+				})
+
+				It("spreads the replicas across the topology domains", func() {
+					promise.Spec.SchedulingPolicy.Replicas = ptr.To(2)
+					Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+
+					Expect(reconcileWork(&work)).To(BeEmpty())
+					Expect(regionsOf(destinationsFor(work))).To(ConsistOf("eu", "us"))
+				})
+
+				It("does not schedule replicas that would exceed maxSkew", func() {
+					promise.Spec.SchedulingPolicy.Replicas = ptr.To(4)
+					Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+
+					Expect(reconcileWork(&work)).To(ConsistOf(work.Spec.WorkloadGroups[0].ID))
+					Expect(regionsOf(destinationsFor(work))).To(ConsistOf("eu", "eu", "us"))
+				})
+
+				It("schedules replicas that exceed maxSkew when the constraint is ScheduleAnyway", func() {
+					promise.Spec.SchedulingPolicy.Replicas = ptr.To(4)
+					promise.Spec.SchedulingPolicy.TopologySpreadConstraints[0].WhenUnsatisfiable = WhenUnsatisfiableScheduleAnyway
+					Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+
+					Expect(reconcileWork(&work)).To(BeEmpty())
+					Expect(regionsOf(destinationsFor(work))).To(ConsistOf("eu", "eu", "eu", "us"))
+				})
+
+				It("prefers the Destinations that minimise the skew when a ScheduleAnyway constraint is exceeded", func() {
+					inRegion := func(name, region string) Destination {
+						return Destination{ObjectMeta: v1.ObjectMeta{Name: name, Labels: map[string]string{"region": region}}}
+					}
+					euScheduled := []Destination{inRegion("eu-1", "eu"), inRegion("eu-2", "eu"), inRegion("eu-3", "eu")}
+					usScheduled := inRegion("us-1", "us")
+					candidates := []Destination{inRegion("eu-4", "eu"), inRegion("us-2", "us")}
+					destinations := append(append(append([]Destination{}, euScheduled...), usScheduled, inRegion("ap-1", "ap")), candidates...)
+
+					constraints := []TopologySpreadConstraint{{TopologyKey: "region", MaxSkew: 1, WhenUnsatisfiable: WhenUnsatisfiableScheduleAnyway}}
+					filtered := FilterByTopologySpread(constraints, destinations, append(euScheduled, usScheduled), candidates)
+					Expect(filtered).To(HaveLen(1))
+					Expect(filtered[0].Name).To(Equal("us-2"))
+				})
+
+				It("does not schedule to Destinations without the topology label", func() {
+					createDestination("db-no-region", map[string]string{"tier": "db"})
+					promise.Spec.SchedulingPolicy.Replicas = ptr.To(5)
+					promise.Spec.SchedulingPolicy.TopologySpreadConstraints[0].MaxSkew = 3
+					Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+
+					reconcileWork(&work)
+					Expect(destinationsFor(work)).NotTo(ContainElement("db-no-region"))
+				})
+
+				It("keeps Destinations without the topology label when the constraint is ScheduleAnyway", func() {
+					euScheduled := Destination{ObjectMeta: v1.ObjectMeta{Name: "eu-1", Labels: map[string]string{"region": "eu"}}}
+					usCandidate := Destination{ObjectMeta: v1.ObjectMeta{Name: "us-1", Labels: map[string]string{"region": "us"}}}
+					euCandidate := Destination{ObjectMeta: v1.ObjectMeta{Name: "eu-2", Labels: map[string]string{"region": "eu"}}}
+					unlabelled := Destination{ObjectMeta: v1.ObjectMeta{Name: "no-region"}}
+					destinations := []Destination{euScheduled, usCandidate, euCandidate, unlabelled}
+
+					constraints := []TopologySpreadConstraint{{TopologyKey: "region", MaxSkew: 1, WhenUnsatisfiable: WhenUnsatisfiableScheduleAnyway}}
+					filtered := FilterByTopologySpread(constraints, destinations, []Destination{euScheduled}, []Destination{usCandidate, euCandidate, unlabelled})
+					Expect(filtered).To(ConsistOf(HaveField("Name", "us-1"), HaveField("Name", "no-region")))
+
+					By("still excluding them for DoNotSchedule constraints")
+					constraints[0].WhenUnsatisfiable = WhenUnsatisfiableDoNotSchedule
+					filtered = FilterByTopologySpread(constraints, destinations, []Destination{euScheduled}, []Destination{usCandidate, euCandidate, unlabelled})
+					Expect(filtered).To(ConsistOf(HaveField("Name", "us-1")))
+				})
+			})
+		})
+
+		Describe("Rescheduling", func() {
+			var promise *Promise
+			var work Work
+
+			stopMatching := func(destinationName string) {
+				destination := &Destination{}
+				Expect(fakeK8sClient.Get(context.Background(), types.NamespacedName{Name: destinationName}, destination)).To(Succeed())
+				destination.SetLabels(map[string]string{"tier": "cache"})
+				Expect(fakeK8sClient.Update(context.Background(), destination)).To(Succeed())
+			}
+
+			BeforeEach(func() {
+				createDestination("db-1", map[string]string{"tier": "db"})
+				createDestination("db-2", map[string]string{"tier": "db"})
+
+				scheduler.DefaultSchedulingStrategy = SchedulingStrategyRoundRobin
+				promise = &Promise{ObjectMeta: v1.ObjectMeta{Name: "promise"}}
+				work = newWork("rr-work", true, WorkloadGroupScheduling{MatchLabels: map[string]string{"tier": "db"}, Source: "promise"})
+			})
+
+			JustBeforeEach(func() {
+				Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+				Expect(reconcileWork(&work)).To(BeEmpty())
+				Expect(destinationsFor(work)).To(ConsistOf("db-1"))
+				markWritten(work)
+				stopMatching("db-1")
+			})
+
+			When("the rescheduling policy is never", func() {
+				It("keeps the resource on the Destination and marks it as misscheduled", func() {
+					Expect(reconcileWork(&work)).To(BeEmpty())
+					Expect(destinationsFor(work)).To(ConsistOf("db-1"))
+					Expect(work.Status.Conditions[1].Status).To(Equal(v1.ConditionTrue))
+				})
+			})
+
+			When("the rescheduling policy is onMismatch", func() {
+				BeforeEach(func() {
+					promise.Spec.SchedulingPolicy.Rescheduling.Policy = ReschedulingPolicyOnMismatch
+				})
+
+				It("moves the resource to a matching Destination once it is written there", func() {
+					Expect(reconcileWork(&work)).To(ConsistOf(work.Spec.WorkloadGroups[0].ID))
+					Expect(destinationsFor(work)).To(ConsistOf("db-1", "db-2"))
+					Expect(work.Status.Conditions[1].Status).To(Equal(v1.ConditionTrue))
+
+					Expect(reconcileWork(&work)).To(ConsistOf(work.Spec.WorkloadGroups[0].ID))
+					Expect(destinationsFor(work)).To(ConsistOf("db-1", "db-2"))
+
+					markWritten(work)
+					Expect(reconcileWork(&work)).To(BeEmpty())
+					Expect(destinationsFor(work)).To(ConsistOf("db-2"))
+					Expect(work.Status.Conditions[0].Status).To(Equal(v1.ConditionTrue))
+					Expect(work.Status.Conditions[1].Status).To(Equal(v1.ConditionFalse))
+				})
+
+				It("keeps the resource on the Destination when no other Destination matches", func() {
+					stopMatching("db-2")
+					Expect(reconcileWork(&work)).To(ConsistOf(work.Spec.WorkloadGroups[0].ID))
+					Expect(destinationsFor(work)).To(ConsistOf("db-1"))
+					Expect(work.Status.Conditions[1].Status).To(Equal(v1.ConditionTrue))
+				})
+			})
+
+			When("the rescheduling policy is onMismatchAfter", func() {
+				BeforeEach(func() {
+					promise.Spec.SchedulingPolicy.Rescheduling = ReschedulingPolicy{
+						Policy: ReschedulingPolicyOnMismatchAfter,
+						After:  &v1.Duration{Duration: time.Hour},
+					}
+				})
+
+				It("does not move the resource before the duration has passed", func() {
+					Expect(reconcileWork(&work)).To(ConsistOf(work.Spec.WorkloadGroups[0].ID))
+					Expect(destinationsFor(work)).To(ConsistOf("db-1"))
+				})
+
+				It("moves the resource once the duration has passed", func() {
+					reconcileWork(&work)
+					wp := placementsFor(work)[0]
+					for i := range wp.Status.Conditions {
+						if wp.Status.Conditions[i].Type == "Misscheduled" {
+							wp.Status.Conditions[i].LastTransitionTime = v1.NewTime(time.Now().Add(-2 * time.Hour))
+						}
+					}
+					Expect(fakeK8sClient.Status().Update(context.Background(), &wp)).To(Succeed())
+
+					reconcileWork(&work)
+					Expect(destinationsFor(work)).To(ConsistOf("db-1", "db-2"))
+				})
+			})
+		})
+
+		Describe("Cordoned and draining Destinations", func() {
+			var promise *Promise
+			var work Work
+
+			updateDestination := func(name string, update func(*Destination)) {
+				destination := &Destination{}
+				Expect(fakeK8sClient.Get(context.Background(), types.NamespacedName{Name: name}, destination)).To(Succeed())
+				update(destination)
+				Expect(fakeK8sClient.Update(context.Background(), destination)).To(Succeed())
+			}
+
+			BeforeEach(func() {
+				createDestination("db-1", map[string]string{"tier": "db"})
+				createDestination("db-2", map[string]string{"tier": "db"})
+
+				scheduler.DefaultSchedulingStrategy = SchedulingStrategyRoundRobin
+				promise = &Promise{ObjectMeta: v1.ObjectMeta{Name: "promise"}}
+				Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+				work = newWork("rr-work", true, WorkloadGroupScheduling{MatchLabels: map[string]string{"tier": "db"}, Source: "promise"})
+			})
+
+			It("does not schedule new works to a cordoned Destination", func() {
+				updateDestination("db-1", func(d *Destination) { d.Spec.Cordoned = true })
+
+				Expect(reconcileWork(&work)).To(BeEmpty())
+				Expect(destinationsFor(work)).To(ConsistOf("db-2"))
+			})
+
+			It("does not move or misschedule works already scheduled to a cordoned Destination", func() {
+				Expect(reconcileWork(&work)).To(BeEmpty())
+				Expect(destinationsFor(work)).To(ConsistOf("db-1"))
+
+				updateDestination("db-1", func(d *Destination) { d.Spec.Cordoned = true })
+				Expect(reconcileWork(&work)).To(BeEmpty())
+				Expect(destinationsFor(work)).To(ConsistOf("db-1"))
+				Expect(work.Status.Conditions[1].Status).To(Equal(v1.ConditionFalse))
+			})
+
+			It("moves works being drained to another Destination once they are written there", func() {
+				Expect(reconcileWork(&work)).To(BeEmpty())
+				updateDestination("db-1", func(d *Destination) { d.Spec.Drain = true })
+
+				wp := placementsFor(work)[0]
+				wp.Labels["kratix.io/draining"] = "true"
+				Expect(fakeK8sClient.Update(context.Background(), &wp)).To(Succeed())
+
+				Expect(reconcileWork(&work)).To(ConsistOf(work.Spec.WorkloadGroups[0].ID))
+				Expect(destinationsFor(work)).To(ConsistOf("db-1", "db-2"))
+				Expect(work.Status.Conditions[1].Status).To(Equal(v1.ConditionFalse))
+
+				markWritten(work)
+				Expect(reconcileWork(&work)).To(BeEmpty())
+				Expect(destinationsFor(work)).To(ConsistOf("db-2"))
+			})
+		})
+
+		Describe("Preferred selectors", func() {
+			var work Work
+
+			BeforeEach(func() {
+				Expect(fakeK8sClient.Create(context.Background(), &Promise{ObjectMeta: v1.ObjectMeta{Name: "promise"}})).To(Succeed())
+				createDestination("us-hdd", map[string]string{"tier": "db", "region": "us"})
+				createDestination("eu-hdd", map[string]string{"tier": "db", "region": "eu"})
+				createDestination("us-ssd", map[string]string{"tier": "db", "region": "us", "disk": "ssd"})
+				scheduler.DefaultSchedulingStrategy = SchedulingStrategyRoundRobin
+			})
+
+			newPreferringWork := func(preferred ...PreferredDestinationSelector) Work {
+				return newWork("rr-work", true,
+					WorkloadGroupScheduling{MatchLabels: map[string]string{"tier": "db"}, Preferred: preferred[:1], Source: "promise"},
+					WorkloadGroupScheduling{Preferred: preferred[1:], Source: "resource-workflow"},
+				)
+			}
+
+			It("schedules to the Destination with the highest total weight", func() {
+				work = newPreferringWork(
+					PreferredDestinationSelector{Weight: 30, MatchLabels: map[string]string{"region": "eu"}},
+					PreferredDestinationSelector{Weight: 20, MatchLabels: map[string]string{"disk": "ssd"}},
+					PreferredDestinationSelector{Weight: 20, MatchExpressions: []v1.LabelSelectorRequirement{
+						{Key: "region", Operator: v1.LabelSelectorOpIn, Values: []string{"us"}},
+					}},
+				)
+				Expect(reconcileWork(&work)).To(BeEmpty())
+				Expect(destinationsFor(work)).To(ConsistOf("us-ssd"))
+			})
+
+			It("falls back to the next highest scoring Destination when the preferred one is unavailable", func() {
+				destination := &Destination{}
+				Expect(fakeK8sClient.Get(context.Background(), types.NamespacedName{Name: "us-ssd"}, destination)).To(Succeed())
+				destination.Spec.Capacity = &DestinationCapacity{MaxWorkPlacements: ptr.To(0)}
+				Expect(fakeK8sClient.Update(context.Background(), destination)).To(Succeed())
+
+				work = newPreferringWork(
+					PreferredDestinationSelector{Weight: 10, MatchLabels: map[string]string{"disk": "ssd"}},
+					PreferredDestinationSelector{Weight: 5, MatchLabels: map[string]string{"region": "eu"}},
+				)
+				Expect(reconcileWork(&work)).To(BeEmpty())
+				Expect(destinationsFor(work)).To(ConsistOf("eu-hdd"))
+			})
+
+			It("still schedules when no Destination matches the preferred selectors", func() {
+				work = newPreferringWork(
+					PreferredDestinationSelector{Weight: 10, MatchLabels: map[string]string{"region": "ap"}},
+				)
+				Expect(reconcileWork(&work)).To(BeEmpty())
+				Expect(destinationsFor(work)).To(ConsistOf(BeElementOf("us-hdd", "eu-hdd", "us-ssd")))
+			})
+		})
+
+		Describe("Namespace access", func() {
+			var work Work
+
+			BeforeEach(func() {
+				namespace := &corev1.Namespace{ObjectMeta: v1.ObjectMeta{
+					Name:   "default",
+					Labels: map[string]string{"tenant": "team-a"},
+				}}
+				Expect(fakeK8sClient.Create(context.Background(), namespace)).To(Succeed())
+
+				promise := &Promise{ObjectMeta: v1.ObjectMeta{Name: "promise"}}
+				Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+			})
+
+			It("only schedules resource requests to Destinations that allow their namespace", func() {
+				createDestination("team-b-db", map[string]string{"tier": "db"}, func(d *Destination) {
+					d.Spec.NamespaceAccess = &DestinationNamespaceAccess{Namespaces: []string{"team-b"}}
+				})
+				createDestination("team-a-db", map[string]string{"tier": "db"}, func(d *Destination) {
+					d.Spec.NamespaceAccess = &DestinationNamespaceAccess{
+						NamespaceSelector: &v1.LabelSelector{MatchLabels: map[string]string{"tenant": "team-a"}},
+					}
+				})
+				work = newWork("rr-work", true, WorkloadGroupScheduling{MatchLabels: map[string]string{"tier": "db"}, Source: "promise"})
+
+				Expect(reconcileWork(&work)).To(BeEmpty())
+				Expect(destinationsFor(work)).To(ConsistOf("team-a-db"))
+			})
+
+			It("reports the Destinations that do not allow the namespace", func() {
+				createDestination("team-b-db", map[string]string{"tier": "db"}, func(d *Destination) {
+					d.Spec.NamespaceAccess = &DestinationNamespaceAccess{Namespaces: []string{"team-b"}}
+				})
+				work = newWork("rr-work", true, WorkloadGroupScheduling{MatchLabels: map[string]string{"tier": "db"}, Source: "promise"})
+
+				Expect(reconcileWork(&work)).To(ConsistOf(work.Spec.WorkloadGroups[0].ID))
+				Expect(destinationsFor(work)).To(BeEmpty())
+				Expect(work.Status.WorkloadGroups[0].Explanation.RejectedDestinations).To(ContainElement(RejectedDestination{
+					Name:    "team-b-db",
+					Reason:  RejectionReasonNamespaceNotAllowed,
+					Message: `destination does not allow resource requests from namespace "default"`,
+				}))
+			})
+
+			It("marks resource requests as misscheduled when the Destination stops allowing their namespace", func() {
+				createDestination("db", map[string]string{"tier": "db"})
+				work = newWork("rr-work", true, WorkloadGroupScheduling{MatchLabels: map[string]string{"tier": "db"}, Source: "promise"})
+				Expect(reconcileWork(&work)).To(BeEmpty())
+				Expect(destinationsFor(work)).To(ConsistOf("db"))
+
+				destination := &Destination{}
+				Expect(fakeK8sClient.Get(context.Background(), types.NamespacedName{Name: "db"}, destination)).To(Succeed())
+				destination.Spec.NamespaceAccess = &DestinationNamespaceAccess{Namespaces: []string{"team-b"}}
+				Expect(fakeK8sClient.Update(context.Background(), destination)).To(Succeed())
+
+				Expect(reconcileWork(&work)).To(BeEmpty())
+				Expect(destinationsFor(work)).To(ConsistOf("db"))
+				Expect(work.Status.Conditions[1].Status).To(Equal(v1.ConditionTrue))
+
+				Expect(placementsFor(work)[0].GetLabels()).To(HaveKeyWithValue("kratix.io/misscheduled", "true"))
+			})
+
+			It("does not restrict Promise dependencies", func() {
+				createDestination("team-b-db", map[string]string{"tier": "db"}, func(d *Destination) {
+					d.Spec.NamespaceAccess = &DestinationNamespaceAccess{Namespaces: []string{"team-b"}}
+				})
+				work = newWork("dependency-work", false, WorkloadGroupScheduling{MatchLabels: map[string]string{"tier": "db"}, Source: "promise"})
+
+				Expect(reconcileWork(&work)).To(BeEmpty())
+				Expect(destinationsFor(work)).To(ConsistOf("team-b-db"))
+			})
+		})
+
+		Describe("Resource affinity", func() {
+			var strategy *preferredStrategy
+
+			createResourceRequest := func(name string, labels map[string]string) {
+				rr := &unstructured.Unstructured{}
+				rr.SetAPIVersion("marketplace.kratix.io/v1alpha1")
+				rr.SetKind("redis")
+				rr.SetName(name)
+				rr.SetNamespace("default")
+				rr.SetLabels(labels)
+				Expect(fakeK8sClient.Create(context.Background(), rr)).To(Succeed())
+			}
+
+			newResourceWork := func(resourceName string, affinity, antiAffinity []ResourceAffinityTerm) *Work {
+				work := newWork(resourceName+"-work", true, WorkloadGroupScheduling{
+					MatchLabels:          map[string]string{"tier": "db"},
+					ResourceAffinity:     affinity,
+					ResourceAntiAffinity: antiAffinity,
+					Source:               "promise",
+				})
+				work.Spec.ResourceName = resourceName
+				Expect(fakeK8sClient.Update(context.Background(), &work)).To(Succeed())
+				return &work
+			}
+
+			schedule := func(work *Work) []string {
+				_, err := scheduler.ReconcileWork(work)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(work), work)).To(Succeed())
+				return work.Status.WorkloadGroups[0].Destinations
+			}
+
+			BeforeEach(func() {
+				promise := promiseFromFile(promisePath)
+				promise.SetName("promise")
+				Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+
+				for _, name := range []string{"d-1", "d-2", "d-3"} {
+					destination := newDestination(name, map[string]string{"tier": "db"})
+					Expect(fakeK8sClient.Create(context.Background(), &destination)).To(Succeed())
+				}
+
+				strategy = &preferredStrategy{destination: "d-1"}
+				scheduler.RegisterSchedulingStrategy("preferred", strategy)
+				scheduler.DefaultSchedulingStrategy = "preferred"
+			})
+
+			It("schedules a resource to the Destination of the resources it has affinity with", func() {
+				createResourceRequest("db", map[string]string{"app": "shop", "role": "db"})
+				createResourceRequest("other-db", map[string]string{"app": "blog", "role": "db"})
+				createResourceRequest("cache", map[string]string{"app": "shop", "role": "cache"})
+
+				strategy.destination = "d-2"
+				Expect(schedule(newResourceWork("db", nil, nil))).To(ConsistOf("d-2"))
+				strategy.destination = "d-3"
+				Expect(schedule(newResourceWork("other-db", nil, nil))).To(ConsistOf("d-3"))
+
+				strategy.destination = "d-1"
+				cache := newResourceWork("cache", []ResourceAffinityTerm{{
+					LabelSelector:  v1.LabelSelector{MatchLabels: map[string]string{"role": "db"}},
+					MatchLabelKeys: []string{"app"},
+				}}, nil)
+				Expect(schedule(cache)).To(ConsistOf("d-2"))
+			})
+
+			It("does not schedule a resource until the resources it has affinity with are scheduled", func() {
+				createResourceRequest("cache", map[string]string{"app": "shop", "role": "cache"})
+				cache := newResourceWork("cache", []ResourceAffinityTerm{{
+					LabelSelector: v1.LabelSelector{MatchLabels: map[string]string{"role": "db"}},
+				}}, nil)
+
+				Expect(schedule(cache)).To(BeEmpty())
+				Expect(cache.Status.WorkloadGroups[0].Explanation.RejectedDestinations).To(ContainElement(RejectedDestination{
+					Name:    "d-1",
+					Reason:  RejectionReasonResourceAffinity,
+					Message: "resource affinity or anti-affinity is not satisfied",
+				}))
+			})
+
+			It("schedules the first of a group of resources with affinity to each other", func() {
+				createResourceRequest("web", map[string]string{"app": "web"})
+				web := newResourceWork("web", []ResourceAffinityTerm{{
+					LabelSelector: v1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				}}, nil)
+
+				Expect(schedule(web)).To(ConsistOf("d-1"))
+			})
+
+			It("schedules resources with anti-affinity to each other to different Destinations", func() {
+				antiAffinity := []ResourceAffinityTerm{{
+					LabelSelector: v1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				}}
+
+				var scheduled []string
+				for _, name := range []string{"web-1", "web-2", "web-3"} {
+					createResourceRequest(name, map[string]string{"app": "web"})
+					scheduled = append(scheduled, schedule(newResourceWork(name, nil, antiAffinity))...)
+				}
+				Expect(scheduled).To(ConsistOf("d-1", "d-2", "d-3"))
+
+				createResourceRequest("web-4", map[string]string{"app": "web"})
+				Expect(schedule(newResourceWork("web-4", nil, antiAffinity))).To(BeEmpty())
+			})
+
+			It("only selects resources of the given Promises", func() {
+				createResourceRequest("web-1", map[string]string{"app": "web"})
+				Expect(schedule(newResourceWork("web-1", nil, nil))).To(ConsistOf("d-1"))
+
+				createResourceRequest("web-2", map[string]string{"app": "web"})
+				Expect(schedule(newResourceWork("web-2", nil, []ResourceAffinityTerm{{
+					LabelSelector: v1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+					Promises:      []string{"another-promise"},
+				}}))).To(ConsistOf("d-1"))
+			})
+
+			It("only selects resources in the given namespaces", func() {
+				rr := &unstructured.Unstructured{}
+				rr.SetAPIVersion("marketplace.kratix.io/v1alpha1")
+				rr.SetKind("redis")
+				rr.SetName("web-1")
+				rr.SetNamespace("team-b")
+				rr.SetLabels(map[string]string{"app": "web"})
+				Expect(fakeK8sClient.Create(context.Background(), rr)).To(Succeed())
+				Expect(fakeK8sClient.Create(context.Background(), &WorkPlacement{
+					ObjectMeta: v1.ObjectMeta{
+						Name:      "web-1-d-1",
+						Namespace: "team-b",
+						Labels:    map[string]string{"kratix.io/targetDestinationName": "d-1"},
+					},
+					Spec: WorkPlacementSpec{
+						PromiseName:           "promise",
+						ResourceName:          "web-1",
+						TargetDestinationName: "d-1",
+					},
+				})).To(Succeed())
+
+				createResourceRequest("web-2", map[string]string{"app": "web"})
+				Expect(schedule(newResourceWork("web-2", nil, []ResourceAffinityTerm{{
+					LabelSelector: v1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				}}))).To(ConsistOf("d-1"))
+
+				createResourceRequest("web-3", map[string]string{"app": "web"})
+				strategy.destination = "d-2"
+				Expect(schedule(newResourceWork("web-3", []ResourceAffinityTerm{{
+					LabelSelector: v1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+					Namespaces:    []string{"team-b"},
+				}}, nil))).To(ConsistOf("d-1"))
+			})
+		})
+
+		Describe("Dependency rollout", func() {
+			var work Work
+			var promise *Promise
+			v1Workloads := []Workload{{Content: "key: value"}}
+			v2Workloads := []Workload{{Content: "key: new-value"}}
+
+			reconcile := func() {
+				Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(&work), &work)).To(Succeed())
+				_, err := scheduler.ReconcileWork(&work)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(promise), promise)).To(Succeed())
+			}
+
+			workPlacementFor := func(destination string) WorkPlacement {
+				wps := WorkPlacementList{}
+				Expect(fakeK8sClient.List(context.Background(), &wps, client.MatchingLabels{
+					"kratix.io/work":                  work.Name,
+					"kratix.io/targetDestinationName": destination,
+				})).To(Succeed())
+				Expect(wps.Items).To(HaveLen(1))
+				return wps.Items[0]
+			}
+
+			updatedDestinations := func() []string {
+				var updated []string
+				for _, destination := range []string{"edge-a", "edge-b", "edge-c", "edge-d"} {
+					if wp := workPlacementFor(destination); wp.Spec.Workloads[0].Content == v2Workloads[0].Content {
+						updated = append(updated, destination)
+					}
+				}
+				return updated
+			}
+
+			setWriteSucceeded := func(destination string, status v1.ConditionStatus) {
+				wp := workPlacementFor(destination)
+				meta.SetStatusCondition(&wp.Status.Conditions, v1.Condition{
+					Type:               "WriteSucceeded",
+					Status:             status,
+					Reason:             "Test",
+					ObservedGeneration: wp.GetGeneration(),
+				})
+				Expect(fakeK8sClient.Status().Update(context.Background(), &wp)).To(Succeed())
+			}
+
+			changeDependencies := func() {
+				Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(&work), &work)).To(Succeed())
+				work.Spec.WorkloadGroups[0].Workloads = v2Workloads
+				Expect(fakeK8sClient.Update(context.Background(), &work)).To(Succeed())
+				reconcile()
+			}
+
+			BeforeEach(func() {
+				for name, wave := range map[string]string{"edge-a": "1", "edge-b": "1", "edge-c": "2", "edge-d": ""} {
+					labels := map[string]string{"tier": "edge"}
+					if wave != "" {
+						labels["wave"] = wave
+					}
+					destination := newDestination(name, labels)
+					Expect(fakeK8sClient.Create(context.Background(), &destination)).To(Succeed())
+				}
+
+				promise = &Promise{
+					ObjectMeta: v1.ObjectMeta{Name: "promise"},
+					Spec: PromiseSpec{SchedulingPolicy: SchedulingPolicy{
+						DependencyRollout: &DependencyRolloutPolicy{WaveLabel: "wave", BatchSize: 1},
+					}},
+				}
+				Expect(fakeK8sClient.Create(context.Background(), promise)).To(Succeed())
+
+				work = newWork("static-deps", false, WorkloadGroupScheduling{MatchLabels: map[string]string{"tier": "edge"}, Source: "promise"})
+				work.Labels[WorkTypeLabel] = WorkTypeStaticDependency
+				Expect(fakeK8sClient.Update(context.Background(), &work)).To(Succeed())
+
+				reconcile()
+				reconcile()
+				Expect(promise.Status.DependencyRollout.Phase).To(Equal(DependencyRolloutCompleted))
+				Expect(promise.Status.DependencyRollout.TotalDestinations).To(Equal(4))
+			})
+
+			It("writes the initial dependencies to every Destination at once", func() {
+				for _, destination := range []string{"edge-a", "edge-b", "edge-c", "edge-d"} {
+					Expect(workPlacementFor(destination).Spec.Workloads).To(Equal(v1Workloads))
+				}
+			})
+
+			It("rolls out changes in batches, wave by wave, once the previous batch is written", func() {
+				changeDependencies()
+				Expect(updatedDestinations()).To(Equal([]string{"edge-a"}))
+				rollout := promise.Status.DependencyRollout
+				Expect(rollout.Phase).To(Equal(DependencyRolloutProgressing))
+				Expect(rollout.CurrentWave).To(Equal("1"))
+				Expect(rollout.CurrentBatch).To(Equal([]string{"edge-a"}))
+				Expect(rollout.UpdatedDestinations).To(Equal(1))
+				Expect(rollout.TotalDestinations).To(Equal(4))
+
+				By("waiting for the batch to be written")
+				reconcile()
+				Expect(updatedDestinations()).To(Equal([]string{"edge-a"}))
+				Expect(promise.Status.DependencyRollout.Message).To(ContainSubstring("waiting for the dependencies to be written to Destinations [edge-a]"))
+
+				for _, batch := range [][]string{{"edge-a", "edge-b"}, {"edge-a", "edge-b", "edge-c"}, {"edge-a", "edge-b", "edge-c", "edge-d"}} {
+					setWriteSucceeded(batch[len(batch)-2], v1.ConditionTrue)
+					reconcile()
+					Expect(updatedDestinations()).To(Equal(batch))
+				}
+				Expect(promise.Status.DependencyRollout.CurrentWave).To(BeEmpty())
+				Expect(promise.Status.DependencyRollout.CurrentBatch).To(Equal([]string{"edge-d"}))
+
+				setWriteSucceeded("edge-d", v1.ConditionTrue)
+				reconcile()
+				Expect(promise.Status.DependencyRollout.Phase).To(Equal(DependencyRolloutCompleted))
+				Expect(promise.Status.DependencyRollout.UpdatedDestinations).To(Equal(4))
+			})
+
+			It("pauses between batches", func() {
+				promise.Spec.SchedulingPolicy.DependencyRollout.Pause = &v1.Duration{Duration: time.Hour}
+				Expect(fakeK8sClient.Update(context.Background(), promise)).To(Succeed())
+
+				changeDependencies()
+				setWriteSucceeded("edge-a", v1.ConditionTrue)
+				reconcile()
+				Expect(updatedDestinations()).To(Equal([]string{"edge-a"}))
+				rollout := promise.Status.DependencyRollout
+				Expect(rollout.Phase).To(Equal(DependencyRolloutProgressing))
+				Expect(rollout.NextBatchTime.Time).To(BeTemporally("~", rollout.LastBatchTime.Add(time.Hour)))
+
+				promise.Status.DependencyRollout.LastBatchTime = &v1.Time{Time: time.Now().Add(-2 * time.Hour)}
+				Expect(fakeK8sClient.Status().Update(context.Background(), promise)).To(Succeed())
+				reconcile()
+				Expect(updatedDestinations()).To(Equal([]string{"edge-a", "edge-b"}))
+				Expect(promise.Status.DependencyRollout.NextBatchTime).To(BeNil())
+			})
+
+			It("halts on failure until resumed when haltOnFailure is set", func() {
+				promise.Spec.SchedulingPolicy.DependencyRollout.HaltOnFailure = true
+				Expect(fakeK8sClient.Update(context.Background(), promise)).To(Succeed())
+
+				changeDependencies()
+				setWriteSucceeded("edge-a", v1.ConditionFalse)
+				reconcile()
+				reconcile()
+				Expect(updatedDestinations()).To(Equal([]string{"edge-a"}))
+				Expect(promise.Status.DependencyRollout.Phase).To(Equal(DependencyRolloutPaused))
+				Expect(promise.Status.DependencyRollout.Message).To(ContainSubstring("writing the dependencies to Destinations [edge-a] failed"))
+
+				promise.SetAnnotations(map[string]string{ResumeDependencyRolloutAnnotation: "true"})
+				Expect(fakeK8sClient.Update(context.Background(), promise)).To(Succeed())
+				reconcile()
+				Expect(updatedDestinations()).To(Equal([]string{"edge-a", "edge-b"}))
+				Expect(promise.Status.DependencyRollout.Phase).To(Equal(DependencyRolloutProgressing))
+				Expect(promise.GetAnnotations()).NotTo(HaveKey(ResumeDependencyRolloutAnnotation))
+			})
+
+			It("keeps rolling out after failures when haltOnFailure is not set", func() {
+				changeDependencies()
+				setWriteSucceeded("edge-a", v1.ConditionFalse)
+				reconcile()
+				Expect(updatedDestinations()).To(Equal([]string{"edge-a", "edge-b"}))
+			})
+		})
+
+		Describe("Scheduling explanation", func() {
+			reconcile := func(work *Work) *SchedulingExplanation {
+				_, err := scheduler.ReconcileWork(work)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(work), work)).To(Succeed())
+				return work.Status.WorkloadGroups[0].Explanation
+			}
+
+			BeforeEach(func() {
+				Expect(fakeK8sClient.Create(context.Background(), &Promise{ObjectMeta: v1.ObjectMeta{Name: "promise"}})).To(Succeed())
+				createDestination("staging", map[string]string{"environment": "staging", "zone": "a"})
+				createDestination("qa-cordoned", map[string]string{"environment": "qa", "zone": "a"}, func(d *Destination) {
+					d.Spec.Cordoned = true
+				})
+				createDestination("qa-tainted", map[string]string{"environment": "qa", "zone": "a"}, func(d *Destination) {
+					d.Spec.Taints = []DestinationTaint{{Key: "gpu", Effect: TaintEffectNoSchedule}}
+				})
+				createDestination("qa-full", map[string]string{"environment": "qa", "zone": "a"}, func(d *Destination) {
+					d.Spec.Capacity = &DestinationCapacity{MaxWorkPlacements: ptr.To(0)}
+				})
+				createDestination("qa-no-state-store", map[string]string{"environment": "qa", "zone": "a"}, func(d *Destination) {
+					d.Spec.StateStoreRef = nil
+				})
+			})
+
+			It("explains why each Destination was rejected", func() {
+				work := newWork("rr-work", true,
+					WorkloadGroupScheduling{MatchLabels: map[string]string{"environment": "qa"}, Source: "promise"},
+					WorkloadGroupScheduling{MatchLabels: map[string]string{"environment": "staging", "zone": "a"}, Source: "resource-workflow"},
+				)
+
+				explanation := reconcile(&work)
+				Expect(explanation).NotTo(BeNil())
+				Expect(explanation.Selector.MatchLabels).To(Equal(map[string]string{"environment": "qa", "zone": "a"}))
+				Expect(explanation.LabelSources).To(Equal(map[string]string{"environment": "promise", "zone": "resource-workflow"}))
+				Expect(explanation.RejectedDestinations).To(ContainElements(
+					RejectedDestination{Name: "staging", Reason: RejectionReasonLabelMismatch, Message: "labels do not match: environment=qa"},
+					RejectedDestination{Name: "dev-1", Reason: RejectionReasonLabelMismatch, Message: "labels do not match: environment=qa, zone=a"},
+					RejectedDestination{Name: "qa-cordoned", Reason: RejectionReasonCordoned, Message: "destination is cordoned"},
+					RejectedDestination{Name: "qa-tainted", Reason: RejectionReasonUntoleratedTaint, Message: "untolerated taints: gpu:NoSchedule"},
+					RejectedDestination{Name: "qa-full", Reason: RejectionReasonAtCapacity, Message: "no capacity left for the resource"},
+					RejectedDestination{Name: "qa-no-state-store", Reason: RejectionReasonNoStateStore, Message: "destination has no stateStoreRef"},
+				))
+			})
+
+			It("explains Destinations rejected because of strictMatchLabels", func() {
+				destinations := DestinationList{}
+				Expect(fakeK8sClient.List(context.Background(), &destinations)).To(Succeed())
+				for _, destination := range destinations.Items {
+					destination.Spec.StrictMatchLabels = true
+					Expect(fakeK8sClient.Update(context.Background(), &destination)).To(Succeed())
+				}
+
+				work := newWork("rr-work", true)
+				work.Spec.WorkloadGroups[0].DestinationSelectors = nil
+				Expect(fakeK8sClient.Update(context.Background(), &work)).To(Succeed())
+
+				explanation := reconcile(&work)
+				Expect(explanation.Selector).To(BeNil())
+				Expect(explanation.RejectedDestinations).To(ContainElement(RejectedDestination{
+					Name:    "strict",
+					Reason:  RejectionReasonStrictMatchLabels,
+					Message: "destination only accepts works with destinationSelectors that match its labels",
+				}))
+			})
+
+			It("is not set when the WorkloadGroup is scheduled", func() {
+				work := newWork("rr-work", true, WorkloadGroupScheduling{MatchLabels: map[string]string{"environment": "staging"}, Source: "promise"})
+
+				Expect(reconcile(&work)).To(BeNil())
+				Expect(work.Status.WorkloadGroups[0].Destinations).To(ConsistOf("staging"))
+			})
+		})
+
 		Describe("Scheduling Dependencies", func() {
 			var dependencyWork, dependencyWorkForDev, dependencyWorkForProd Work
 
@@ -562,6 +1906,31 @@ var _ = Describe("Controllers/Scheduler", func() {
 						))
 						Expect(workPlacements.Items[0].GetAnnotations()).To(Equal(dependencyWorkForProd.GetAnnotations()))
 					})
+
+					It("keeps the WorkPlacement annotations and does not copy approvals from the Work", func() {
+						Expect(fakeK8sClient.List(context.Background(), &workPlacements)).To(Succeed())
+						workPlacement := workPlacements.Items[0]
+						workPlacement.SetAnnotations(map[string]string{
+							ApprovedRevisionAnnotation: "abc",
+							ApprovedByAnnotation:       "approver",
+						})
+						Expect(fakeK8sClient.Update(context.Background(), &workPlacement)).To(Succeed())
+
+						Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(&dependencyWorkForProd), &dependencyWorkForProd)).To(Succeed())
+						dependencyWorkForProd.SetAnnotations(map[string]string{
+							ApproveAnnotation:    "true",
+							ApprovedByAnnotation: "forged",
+						})
+						_, err := scheduler.ReconcileWork(&dependencyWorkForProd)
+						Expect(err).ToNot(HaveOccurred())
+
+						Expect(fakeK8sClient.Get(context.Background(), client.ObjectKeyFromObject(&workPlacement), &workPlacement)).To(Succeed())
+						Expect(workPlacement.GetAnnotations()).To(SatisfyAll(
+							HaveKeyWithValue(ApprovedRevisionAnnotation, "abc"),
+							HaveKeyWithValue(ApprovedByAnnotation, "approver"),
+							Not(HaveKey(ApproveAnnotation)),
+						))
+					})
 				})
 
 				When("the WorkPlacement is deleted", func() {
@@ -835,6 +2204,117 @@ var _ = Describe("Controllers/Scheduler", func() {
 					}
 				})
 			})
+
+			Describe("matchExpressions", func() {
+				scheduledDestinations := func(scheduling ...WorkloadGroupScheduling) []string {
+					work := newWork("work-with-expressions", false, scheduling...)
+					_, err := scheduler.ReconcileWork(&work)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(fakeK8sClient.List(context.Background(), &workPlacements)).To(Succeed())
+					var names []string
+					for _, wp := range workPlacements.Items {
+						names = append(names, wp.Spec.TargetDestinationName)
+					}
+					return names
+				}
+
+				expression := func(key string, operator v1.LabelSelectorOperator, values ...string) v1.LabelSelectorRequirement {
+					return v1.LabelSelectorRequirement{Key: key, Operator: operator, Values: values}
+				}
+
+				DescribeTable("schedules to the Destinations matching the expressions",
+					func(expressions []v1.LabelSelectorRequirement, expected []string) {
+						Expect(scheduledDestinations(WorkloadGroupScheduling{
+							MatchExpressions: expressions,
+							Source:           "promise",
+						})).To(ConsistOf(expected))
+					},
+					Entry("In", []v1.LabelSelectorRequirement{expression("environment", v1.LabelSelectorOpIn, "dev", "prod")}, []string{"dev-1", "dev-2", "prod"}),
+					Entry("NotIn", []v1.LabelSelectorRequirement{expression("environment", v1.LabelSelectorOpNotIn, "dev")}, []string{"pci", "prod", "strict"}),
+					Entry("Exists", []v1.LabelSelectorRequirement{expression("pci", v1.LabelSelectorOpExists)}, []string{"pci"}),
+					Entry("DoesNotExist", []v1.LabelSelectorRequirement{expression("environment", v1.LabelSelectorOpDoesNotExist)}, []string{"pci", "strict"}),
+					Entry("multiple expressions", []v1.LabelSelectorRequirement{
+						expression("environment", v1.LabelSelectorOpExists),
+						expression("environment", v1.LabelSelectorOpNotIn, "prod"),
+					}, []string{"dev-1", "dev-2"}),
+				)
+
+				It("combines matchLabels and matchExpressions", func() {
+					Expect(scheduledDestinations(WorkloadGroupScheduling{
+						MatchLabels:      map[string]string{"environment": "dev"},
+						MatchExpressions: []v1.LabelSelectorRequirement{expression("pci", v1.LabelSelectorOpDoesNotExist)},
+						Source:           "promise",
+					})).To(ConsistOf("dev-1", "dev-2"))
+				})
+
+				It("requires the selectors of all sources to match when they constrain different labels", func() {
+					Expect(scheduledDestinations(
+						WorkloadGroupScheduling{
+							MatchExpressions: []v1.LabelSelectorRequirement{expression("environment", v1.LabelSelectorOpNotIn, "dev")},
+							Source:           "promise",
+						},
+						WorkloadGroupScheduling{
+							MatchExpressions: []v1.LabelSelectorRequirement{expression("strict", v1.LabelSelectorOpDoesNotExist)},
+							Source:           "promise-workflow",
+						},
+						WorkloadGroupScheduling{
+							MatchLabels: map[string]string{"pci": "true"},
+							Source:      "resource-workflow",
+						},
+					)).To(ConsistOf("pci"))
+				})
+
+				It("uses the highest priority source when several sources constrain the same label", func() {
+					Expect(scheduledDestinations(
+						WorkloadGroupScheduling{
+							MatchLabels: map[string]string{"environment": "prod"},
+							Source:      "resource-workflow",
+						},
+						WorkloadGroupScheduling{
+							MatchExpressions: []v1.LabelSelectorRequirement{expression("environment", v1.LabelSelectorOpNotIn, "dev")},
+							Source:           "promise-workflow",
+						},
+						WorkloadGroupScheduling{
+							MatchExpressions: []v1.LabelSelectorRequirement{expression("environment", v1.LabelSelectorOpIn, "dev")},
+							Source:           "promise",
+						},
+					)).To(ConsistOf("dev-1", "dev-2"))
+				})
+
+				It("prioritises resource request selectors over the resource workflow selectors", func() {
+					Expect(scheduledDestinations(
+						WorkloadGroupScheduling{
+							MatchLabels: map[string]string{"environment": "prod"},
+							Source:      "resource-workflow",
+						},
+						WorkloadGroupScheduling{
+							MatchLabels: map[string]string{"environment": "dev"},
+							Source:      "resource-request",
+						},
+					)).To(ConsistOf("dev-1", "dev-2"))
+				})
+
+				It("prioritises the Promise workflow selectors over resource request selectors", func() {
+					Expect(scheduledDestinations(
+						WorkloadGroupScheduling{
+							MatchLabels: map[string]string{"environment": "dev"},
+							Source:      "resource-request",
+						},
+						WorkloadGroupScheduling{
+							MatchLabels: map[string]string{"environment": "prod"},
+							Source:      "promise-workflow",
+						},
+					)).To(ConsistOf("prod"))
+				})
+
+				It("does not schedule when the expressions are invalid", func() {
+					Expect(scheduledDestinations(WorkloadGroupScheduling{
+						MatchExpressions: []v1.LabelSelectorRequirement{expression("environment", v1.LabelSelectorOpIn)},
+						Source:           "promise",
+					})).To(BeEmpty())
+				})
+			})
 		})
 	})
 })
@@ -845,6 +2325,9 @@ func newDestination(name string, labels map[string]string) Destination {
 			Name:   name,
 			Labels: labels,
 		},
+		Spec: DestinationSpec{
+			StateStoreRef: &StateStoreReference{Kind: "BucketStateStore", Name: "default"},
+		},
 	}
 }
 
@@ -955,3 +2438,26 @@ func schedulingFor(destination Destination) WorkloadGroupScheduling {
 		Source:      "promise",
 	}
 }
+
+type fixedStrategy struct {
+	destination string
+}
+
+func (f *fixedStrategy) ChooseDestination(_ *Work, _ WorkloadGroup, _ []Destination) (string, error) {
+	return f.destination, nil
+}
+
+// preferredStrategy chooses the given destination when available, or the
+// first available destination otherwise
+type preferredStrategy struct {
+	destination string
+}
+
+func (p *preferredStrategy) ChooseDestination(_ *Work, _ WorkloadGroup, destinations []Destination) (string, error) {
+	for _, destination := range destinations {
+		if destination.Name == p.destination {
+			return destination.Name, nil
+		}
+	}
+	return destinations[0].Name, nil
+}
@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Explains why a WorkloadGroup could not be fully scheduled, by checking every
+// Destination the WorkloadGroup is not already scheduled to against each
+// scheduling requirement in turn. Each rejected Destination is reported with
+// the first requirement it fails.
+func (s *Scheduler) explainScheduling(work *v1alpha1.Work, workloadGroup v1alpha1.WorkloadGroup, destinations *destinationCache) (*v1alpha1.SchedulingExplanation, error) {
+	destinationSelectors := resolveDestinationSelectorsForWorkloadGroup(workloadGroup, work)
+	explanation := &v1alpha1.SchedulingExplanation{
+		LabelSources: resolveLabelSourcesForWorkloadGroup(workloadGroup),
+	}
+	if !isEmptyLabelSelector(destinationSelectors) {
+		explanation.Selector = &destinationSelectors
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&destinationSelectors)
+	if err != nil {
+		selector = nil
+	}
+
+	allDestinations, err := destinations.list()
+	if err != nil {
+		return nil, err
+	}
+
+	existingWorkplacements, err := s.getExistingWorkPlacementsForWorkloadGroup(work.Namespace, work.Name, workloadGroup)
+	if err != nil {
+		return nil, err
+	}
+	scheduledTo := map[string]bool{}
+	for _, wp := range existingWorkplacements {
+		scheduledTo[wp.Spec.TargetDestinationName] = true
+	}
+
+	var promise *v1alpha1.Promise
+	var affinity *resourceAffinity
+	var namespaceLabels map[string]string
+	if work.IsResourceRequest() {
+		if promise, err = s.getPromise(work); err != nil {
+			return nil, err
+		}
+		if namespaceLabels, err = s.getNamespaceLabels(work.Namespace); err != nil {
+			return nil, err
+		}
+		if affinity, err = s.newResourceAffinity(work, workloadGroup, destinations.resourceRequests); err != nil {
+			return nil, err
+		}
+	}
+	capacityRequests := promiseCapacityRequests{}
+
+	tolerations := resolveTolerationsForWorkloadGroup(workloadGroup)
+	for _, destination := range allDestinations {
+		if scheduledTo[destination.Name] {
+			continue
+		}
+
+		reason, message := explainDestinationMismatch(destination, destinationSelectors, selector, tolerations)
+		if reason == "" && work.IsResourceRequest() && !destination.AllowsNamespace(work.Namespace, namespaceLabels) {
+			reason, message = v1alpha1.RejectionReasonNamespaceNotAllowed, fmt.Sprintf("destination does not allow resource requests from namespace %q", work.Namespace)
+		}
+		if reason == "" && work.IsResourceRequest() && !affinity.allows(destination) {
+			reason, message = v1alpha1.RejectionReasonResourceAffinity, "resource affinity or anti-affinity is not satisfied"
+		}
+		if reason == "" && work.IsResourceRequest() {
+			hasCapacity, err := s.destinationHasCapacity(destination, promise, capacityRequests)
+			if err != nil {
+				return nil, err
+			}
+			if !hasCapacity {
+				reason, message = v1alpha1.RejectionReasonAtCapacity, "no capacity left for the resource"
+			}
+		}
+
+		if reason != "" {
+			explanation.RejectedDestinations = append(explanation.RejectedDestinations, v1alpha1.RejectedDestination{
+				Name:    destination.Name,
+				Reason:  reason,
+				Message: message,
+			})
+		}
+	}
+	return explanation, nil
+}
+
+// Returns why works with the given selectors and tolerations can't be
+// scheduled to the Destination, or an empty reason if they can. A nil selector
+// means the destinationSelectors are invalid.
+func explainDestinationMismatch(destination v1alpha1.Destination, destinationSelectors metav1.LabelSelector, selector labels.Selector, tolerations []v1alpha1.Toleration) (string, string) {
+	if !destination.DeletionTimestamp.IsZero() {
+		return v1alpha1.RejectionReasonDeleting, "destination is being deleted"
+	}
+
+	if isEmptyLabelSelector(destinationSelectors) {
+		if destination.Spec.StrictMatchLabels && len(destination.GetLabels()) > 0 {
+			return v1alpha1.RejectionReasonStrictMatchLabels, "destination only accepts works with destinationSelectors that match its labels"
+		}
+	} else if selector == nil {
+		return v1alpha1.RejectionReasonLabelMismatch, "destinationSelectors are invalid"
+	} else {
+		var unmatched []string
+		requirements, _ := selector.Requirements()
+		for _, requirement := range requirements {
+			if !requirement.Matches(labels.Set(destination.GetLabels())) {
+				unmatched = append(unmatched, requirement.String())
+			}
+		}
+		if len(unmatched) > 0 {
+			return v1alpha1.RejectionReasonLabelMismatch, "labels do not match: " + strings.Join(unmatched, ", ")
+		}
+	}
+
+	if destination.IsCordoned() {
+		return v1alpha1.RejectionReasonCordoned, "destination is cordoned"
+	}
+
+	if destination.Spec.StateStoreRef == nil {
+		return v1alpha1.RejectionReasonNoStateStore, "destination has no stateStoreRef"
+	}
+
+	if taints := destination.UntoleratedTaints(tolerations, v1alpha1.TaintEffectNoSchedule, v1alpha1.TaintEffectNoExecute); len(taints) > 0 {
+		var formatted []string
+		for _, taint := range taints {
+			if taint.Value == "" {
+				formatted = append(formatted, fmt.Sprintf("%s:%s", taint.Key, taint.Effect))
+			} else {
+				formatted = append(formatted, fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect))
+			}
+		}
+		return v1alpha1.RejectionReasonUntoleratedTaint, "untolerated taints: " + strings.Join(formatted, ", ")
+	}
+
+	return "", ""
+}
+
+// Returns the source whose destinationSelectors set each label key of the
+// resolved selector, following the same priority as
+// resolveDestinationSelectorsForWorkloadGroup
+func resolveLabelSourcesForWorkloadGroup(workloadGroup v1alpha1.WorkloadGroup) map[string]string {
+	sources := map[string]string{}
+	for _, scheduling := range sortWorkloadGroupDestinationsByLowestPriority(workloadGroup.DestinationSelectors) {
+		for key := range scheduling.MatchLabels {
+			sources[key] = scheduling.Source
+		}
+		for _, expression := range scheduling.MatchExpressions {
+			sources[expression.Key] = scheduling.Source
+		}
+	}
+
+	if len(sources) == 0 {
+		return nil
+	}
+	return sources
+}
@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SchedulingStrategy chooses which of the available Destinations a resource
+// request's WorkloadGroup is scheduled to. Destinations are never empty.
+type SchedulingStrategy interface {
+	ChooseDestination(work *v1alpha1.Work, workloadGroup v1alpha1.WorkloadGroup, destinations []v1alpha1.Destination) (string, error)
+}
+
+func newBuiltInSchedulingStrategies(k8sClient client.Client) map[string]SchedulingStrategy {
+	return map[string]SchedulingStrategy{
+		v1alpha1.SchedulingStrategyRandom:         &randomStrategy{},
+		v1alpha1.SchedulingStrategyRoundRobin:     &roundRobinStrategy{},
+		v1alpha1.SchedulingStrategyLeastLoaded:    &leastLoadedStrategy{client: k8sClient},
+		v1alpha1.SchedulingStrategyConsistentHash: &consistentHashStrategy{},
+	}
+}
+
+type randomStrategy struct{}
+
+func (r *randomStrategy) ChooseDestination(_ *v1alpha1.Work, _ v1alpha1.WorkloadGroup, destinations []v1alpha1.Destination) (string, error) {
+	return destinations[rand.Intn(len(destinations))].Name, nil
+}
+
+// roundRobinStrategy cycles through the Destinations, ordered by name. The
+// position is kept in memory, so it restarts from the beginning when Kratix
+// restarts.
+type roundRobinStrategy struct {
+	mutex sync.Mutex
+	next  int
+}
+
+func (r *roundRobinStrategy) ChooseDestination(_ *v1alpha1.Work, _ v1alpha1.WorkloadGroup, destinations []v1alpha1.Destination) (string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	names := sortedDestinationNames(destinations)
+	chosen := names[r.next%len(names)]
+	r.next++
+	return chosen, nil
+}
+
+// leastLoadedStrategy chooses the Destination with the fewest resource
+// WorkPlacements, breaking ties by name. Promise dependencies and WorkPlacements
+// being deleted don't count towards a Destination's load.
+type leastLoadedStrategy struct {
+	client client.Client
+}
+
+func (l *leastLoadedStrategy) ChooseDestination(_ *v1alpha1.Work, _ v1alpha1.WorkloadGroup, destinations []v1alpha1.Destination) (string, error) {
+	var chosen string
+	var lowestLoad int
+	for _, name := range sortedDestinationNames(destinations) {
+		load, err := l.load(name)
+		if err != nil {
+			return "", err
+		}
+		if chosen == "" || load < lowestLoad {
+			chosen = name
+			lowestLoad = load
+		}
+	}
+	return chosen, nil
+}
+
+func (l *leastLoadedStrategy) load(destinationName string) (int, error) {
+	workPlacements := &v1alpha1.WorkPlacementList{}
+	if err := l.client.List(context.Background(), workPlacements, client.MatchingLabels{targetDestinationNameLabel: destinationName}); err != nil {
+		return 0, err
+	}
+
+	var load int
+	for _, wp := range workPlacements.Items {
+		if isPlacedResource(wp) {
+			load++
+		}
+	}
+	return load, nil
+}
+
+// consistentHashStrategy uses rendezvous hashing on the resource request, so a
+// resource is always scheduled to the same Destination for a given set of
+// Destinations, and only resources on a removed Destination move elsewhere
+type consistentHashStrategy struct{}
+
+func (c *consistentHashStrategy) ChooseDestination(work *v1alpha1.Work, _ v1alpha1.WorkloadGroup, destinations []v1alpha1.Destination) (string, error) {
+	key := work.GetNamespace() + "/" + work.Spec.PromiseName + "/" + work.Spec.ResourceName
+
+	var chosen string
+	var highestScore uint64
+	for _, name := range sortedDestinationNames(destinations) {
+		h := fnv.New64a()
+		h.Write([]byte(key + "/" + name))
+		if score := h.Sum64(); chosen == "" || score > highestScore {
+			chosen = name
+			highestScore = score
+		}
+	}
+	return chosen, nil
+}
+
+// Returns whether the WorkPlacement holds a resource request's workloads and
+// is not being removed from its Destination
+func isPlacedResource(wp v1alpha1.WorkPlacement) bool {
+	return wp.Spec.ResourceName != "" && wp.DeletionTimestamp.IsZero()
+}
+
+func sortedDestinationNames(destinations []v1alpha1.Destination) []string {
+	names := make([]string, len(destinations))
+	for i, destination := range destinations {
+		names[i] = destination.Name
+	}
+	sort.Strings(names)
+	return names
+}
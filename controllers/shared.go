@@ -116,7 +116,15 @@ func fetchObjectAndSecret(o opts, stateStoreRef client.ObjectKey, stateStore Sta
 	return secret, nil
 }
 
+// Returned by newWriter for Destinations that don't reference a StateStore,
+// such as members of a DestinationGroup without a stateStoreRef
+var errNoStateStoreRef = fmt.Errorf("destination has no stateStoreRef")
+
 func newWriter(o opts, destination v1alpha1.Destination) (writers.StateStoreWriter, error) {
+	if destination.Spec.StateStoreRef == nil {
+		return nil, errNoStateStoreRef
+	}
+
 	stateStoreRef := client.ObjectKey{
 		Name: destination.Spec.StateStoreRef.Name,
 	}
@@ -93,12 +93,18 @@ var _ = BeforeEach(func() {
 		return client.Status().Update(ctx, obj, opts...)
 	}
 
-	fakeK8sClient = fake.NewClientBuilder().WithInterceptorFuncs(interceptorsFuncs).WithScheme(scheme.Scheme).WithStatusSubresource(
+	clientBuilder := fake.NewClientBuilder()
+	for field, indexer := range controllers.WorkFieldIndexers() {
+		clientBuilder = clientBuilder.WithIndex(&v1alpha1.Work{}, field, indexer)
+	}
+
+	fakeK8sClient = clientBuilder.WithInterceptorFuncs(interceptorsFuncs).WithScheme(scheme.Scheme).WithStatusSubresource(
 		&v1alpha1.PromiseRelease{},
 		&v1alpha1.Promise{},
 		&v1alpha1.Work{},
 		&v1alpha1.WorkPlacement{},
 		&v1alpha1.Destination{},
+		&v1alpha1.DestinationGroup{},
 		&v1alpha1.GitStateStore{},
 		&v1alpha1.BucketStateStore{},
 		//Add redis.marketplace.kratix.io/v1alpha1 so we can update its status
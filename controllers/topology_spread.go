@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"github.com/syntasso/kratix/api/v1alpha1"
+)
+
+// Chooses up to count Destinations for new replicas of a resource's
+// WorkloadGroup, each different from the Destinations the WorkloadGroup is
+// already scheduled to. Replicas are chosen one at a time with the scheduling
+// strategy, from the Destinations that keep the replicas within the Promise's
+// topology spread constraints and score highest against the preferred
+// selectors.
+func chooseReplicaDestinations(strategy SchedulingStrategy, work *v1alpha1.Work, workloadGroup v1alpha1.WorkloadGroup, destinations []v1alpha1.Destination, scheduledTo []v1alpha1.Destination, tolerations []v1alpha1.Toleration, preferred []v1alpha1.PreferredDestinationSelector, spread *topologySpread, count int) ([]string, error) {
+	used := map[string]bool{}
+	for _, destination := range scheduledTo {
+		used[destination.Name] = true
+	}
+
+	var chosen []string
+	for len(chosen) < count {
+		var available []v1alpha1.Destination
+		for _, destination := range destinations {
+			if !used[destination.Name] {
+				available = append(available, destination)
+			}
+		}
+
+		available = spread.filter(available)
+		if len(available) == 0 {
+			break
+		}
+		available = preferDestinationsWithoutTaints(available, tolerations)
+		available = preferHighestScoredDestinations(available, preferred)
+
+		name, err := strategy.ChooseDestination(work, workloadGroup, available)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, destination := range available {
+			if destination.Name == name {
+				spread.add(destination)
+			}
+		}
+		used[name] = true
+		chosen = append(chosen, name)
+	}
+	return chosen, nil
+}
+
+type topologySpread struct {
+	constraints []v1alpha1.TopologySpreadConstraint
+	// replicas per topology domain, for each constraint
+	counts []map[string]int
+}
+
+// The topology domains are the values of each constraint's topologyKey on the
+// given Destinations
+func newTopologySpread(constraints []v1alpha1.TopologySpreadConstraint, destinations, scheduledTo []v1alpha1.Destination) *topologySpread {
+	spread := &topologySpread{constraints: constraints}
+	for _, constraint := range constraints {
+		domains := map[string]int{}
+		for _, destination := range destinations {
+			if domain, found := destination.GetLabels()[constraint.TopologyKey]; found {
+				domains[domain] = 0
+			}
+		}
+		spread.counts = append(spread.counts, domains)
+	}
+
+	for _, destination := range scheduledTo {
+		spread.add(destination)
+	}
+	return spread
+}
+
+func (t *topologySpread) add(destination v1alpha1.Destination) {
+	for i, constraint := range t.constraints {
+		if domain, found := destination.GetLabels()[constraint.TopologyKey]; found {
+			t.counts[i][domain]++
+		}
+	}
+}
+
+// Returns the Destinations a replica can be scheduled to without exceeding the
+// maxSkew of any DoNotSchedule constraint. For ScheduleAnyway constraints, the
+// Destinations that don't exceed maxSkew are preferred, and otherwise the ones
+// that would give the smallest skew. Destinations without the topologyKey label
+// are only kept for ScheduleAnyway constraints, as they don't add to the skew.
+func (t *topologySpread) filter(destinations []v1alpha1.Destination) []v1alpha1.Destination {
+	for i, constraint := range t.constraints {
+		maxSkew := constraint.MaxSkew
+		if maxSkew < 1 {
+			maxSkew = 1
+		}
+
+		var withinSkew, minimumSkew []v1alpha1.Destination
+		lowestSkew := -1
+		for _, destination := range destinations {
+			skew, found := t.skew(i, destination)
+			if !found {
+				if constraint.WhenUnsatisfiable == v1alpha1.WhenUnsatisfiableScheduleAnyway {
+					withinSkew = append(withinSkew, destination)
+				}
+				continue
+			}
+			if skew <= maxSkew {
+				withinSkew = append(withinSkew, destination)
+			}
+			switch {
+			case lowestSkew == -1 || skew < lowestSkew:
+				lowestSkew = skew
+				minimumSkew = []v1alpha1.Destination{destination}
+			case skew == lowestSkew:
+				minimumSkew = append(minimumSkew, destination)
+			}
+		}
+
+		if constraint.WhenUnsatisfiable == v1alpha1.WhenUnsatisfiableScheduleAnyway && len(withinSkew) == 0 {
+			if len(minimumSkew) > 0 {
+				destinations = minimumSkew
+			}
+			continue
+		}
+		destinations = withinSkew
+	}
+	return destinations
+}
+
+// Returns the skew scheduling a replica to the Destination would give for the
+// constraint, or false if the Destination has no topology domain
+func (t *topologySpread) skew(constraintIndex int, destination v1alpha1.Destination) (int, bool) {
+	constraint := t.constraints[constraintIndex]
+	counts := t.counts[constraintIndex]
+
+	domain, found := destination.GetLabels()[constraint.TopologyKey]
+	if !found {
+		return 0, false
+	}
+
+	minCount := -1
+	for _, count := range counts {
+		if minCount == -1 || count < minCount {
+			minCount = count
+		}
+	}
+	return counts[domain] + 1 - minCount, true
+}
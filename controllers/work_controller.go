@@ -18,18 +18,38 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
 	"github.com/go-logr/logr"
 	"github.com/syntasso/kratix/api/v1alpha1"
+	"github.com/syntasso/kratix/lib/payload"
+	"github.com/syntasso/kratix/lib/resourceutil"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-const workCleanUpFinalizer = v1alpha1.KratixPrefix + "work-cleanup"
+const (
+	workCleanUpFinalizer = v1alpha1.KratixPrefix + "work-cleanup"
+	// set to False when the ConfigMaps holding the workload content of the Work
+	// are missing, while the workflow that stored them is run again
+	payloadAvailableConditionType = "PayloadAvailable"
+)
 
 // WorkReconciler reconciles a Work object
 type WorkReconciler struct {
@@ -47,6 +67,8 @@ type WorkScheduler interface {
 //+kubebuilder:rbac:groups=platform.kratix.io,resources=works,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=platform.kratix.io,resources=works/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=platform.kratix.io,resources=works/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;update;delete
 
 func (r *WorkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	if r.Disabled {
@@ -78,6 +100,19 @@ func (r *WorkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 			ctx:    ctx}, work, []string{workFinalizer})
 	}
 
+	if err := r.ownPayload(ctx, work); err != nil {
+		if errors.IsConflict(err) {
+			logger.Info("failed to take ownership of the Work payload due to update conflict, requeue...")
+			return fastRequeue, nil
+		}
+		if errors.IsNotFound(err) {
+			logger.Info("workload content of the Work is missing, rerunning the workflow that stored it", "error", err.Error())
+			return slowRequeue, r.recoverPayload(ctx, work, err)
+		}
+		logger.Error(err, "Error taking ownership of the Work payload")
+		return defaultRequeue, err
+	}
+
 	logger.Info("Requesting scheduling for Work")
 	unscheduledWorkloadGroupIDs, err := r.Scheduler.ReconcileWork(work)
 	if errors.IsConflict(err) {
@@ -90,16 +125,213 @@ func (r *WorkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		return defaultRequeue, err
 	}
 
+	if err := r.updatePlacementStatus(ctx, work); err != nil {
+		if errors.IsConflict(err) {
+			logger.Info("failed to update Work placement status due to update conflict, requeue...")
+			return fastRequeue, nil
+		}
+		logger.Error(err, "Error updating Work placement status")
+		return defaultRequeue, err
+	}
+
 	if work.IsResourceRequest() && len(unscheduledWorkloadGroupIDs) > 0 {
-		logger.Info("no available Destinations for some of the workload groups, trying again shortly", "workloadGroupIDs", unscheduledWorkloadGroupIDs)
+		logger.Info("some of the workload groups are not fully scheduled or are being rescheduled, trying again shortly", "workloadGroupIDs", unscheduledWorkloadGroupIDs)
 		return slowRequeue, nil
 	}
 
+	if work.IsStaticDependency() {
+		result, rollingOut, err := r.dependencyRolloutRequeue(ctx, work)
+		if err != nil {
+			return defaultRequeue, err
+		}
+		if rollingOut {
+			logger.Info("promise dependencies are being rolled out, trying again shortly")
+			return result, nil
+		}
+	}
+
 	return ctrl.Result{}, nil
 
 }
 
+// Makes the Work the owner of the ConfigMaps the work-creator stored its
+// workload content in, releasing the ones it no longer references, and deletes
+// the ConfigMaps of the namespace left without a Work. Pipelines are only
+// allowed to create ConfigMaps, so they are owned and cleaned up here.
+func (r *WorkReconciler) ownPayload(ctx context.Context, work *v1alpha1.Work) error {
+	if err := payload.SetOwner(ctx, r.Client, work); err != nil {
+		return err
+	}
+	if meta.RemoveStatusCondition(&work.Status.Conditions, payloadAvailableConditionType) {
+		if err := r.Client.Status().Update(ctx, work); err != nil {
+			return err
+		}
+	}
+	return payload.CollectOrphans(ctx, r.Client, work.GetNamespace())
+}
+
+// Marks the Work as failed when a ConfigMap holding its workload content is
+// missing, and runs the workflow that stored it again, so the content is
+// stored anew. The workflow is only requested to run when the content is first
+// found missing.
+func (r *WorkReconciler) recoverPayload(ctx context.Context, work *v1alpha1.Work, missing error) error {
+	changed := meta.SetStatusCondition(&work.Status.Conditions, metav1.Condition{
+		Type:    payloadAvailableConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "PayloadMissing",
+		Message: fmt.Sprintf("Workload content is missing, rerunning the workflow: %s", missing),
+	})
+	if !changed {
+		return nil
+	}
+	if err := r.Client.Status().Update(ctx, work); err != nil {
+		return err
+	}
+
+	promise := &v1alpha1.Promise{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: work.Spec.PromiseName}, promise); err != nil {
+		return err
+	}
+	var parent client.Object = promise
+	if work.IsResourceRequest() {
+		gvk, _, err := promise.GetAPI()
+		if err != nil {
+			return err
+		}
+		resourceRequest := &unstructured.Unstructured{}
+		resourceRequest.SetGroupVersionKind(*gvk)
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: work.GetNamespace(), Name: work.Spec.ResourceName}, resourceRequest); err != nil {
+			return err
+		}
+		parent = resourceRequest
+	}
+
+	labels := parent.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[resourceutil.ManualReconciliationLabel] = "true"
+	parent.SetLabels(labels)
+	return r.Client.Update(ctx, parent)
+}
+
+// Aggregates the write status of the Work's WorkPlacements into the Work
+// status, and sets the Work's WriteSucceeded condition from it
+func (r *WorkReconciler) updatePlacementStatus(ctx context.Context, work *v1alpha1.Work) error {
+	workPlacements := &v1alpha1.WorkPlacementList{}
+	err := r.Client.List(ctx, workPlacements,
+		client.InNamespace(work.GetNamespace()),
+		client.MatchingLabels{workLabelKey: work.GetName()},
+	)
+	if err != nil {
+		return err
+	}
+
+	var placements []v1alpha1.PlacementStatus
+	var failed, pending []string
+	for _, wp := range workPlacements.Items {
+		if !wp.DeletionTimestamp.IsZero() {
+			continue
+		}
+		placement := v1alpha1.PlacementStatus{
+			WorkPlacement:   wp.GetName(),
+			WorkloadGroupID: wp.Spec.ID,
+			Destination:     wp.Spec.TargetDestinationName,
+			WriteStatus:     v1alpha1.WriteStatusPending,
+			VersionID:       wp.Status.VersionID,
+		}
+		if synced := meta.FindStatusCondition(wp.Status.Conditions, syncedConditionType); synced != nil {
+			placement.Sync = synced.Reason
+		}
+		if healthy := meta.FindStatusCondition(wp.Status.Conditions, healthyConditionType); healthy != nil {
+			placement.Health = healthy.Reason
+		}
+		condition := meta.FindStatusCondition(wp.Status.Conditions, writeSucceededConditionType)
+		switch {
+		case writeSucceeded(wp):
+			placement.WriteStatus = v1alpha1.WriteStatusWritten
+		case condition != nil && condition.Status == metav1.ConditionFalse:
+			placement.WriteStatus = v1alpha1.WriteStatusFailed
+			placement.Message = condition.Message
+			failed = append(failed, placement.Destination)
+		default:
+			if suspended := meta.FindStatusCondition(wp.Status.Conditions, writesSuspendedConditionType); suspended != nil {
+				placement.Message = suspended.Message
+			} else if approval := meta.FindStatusCondition(wp.Status.Conditions, approvedConditionType); approval != nil && approval.Status == metav1.ConditionFalse {
+				placement.Message = approval.Message
+			}
+			pending = append(pending, placement.Destination)
+		}
+		placements = append(placements, placement)
+	}
+	sort.Slice(placements, func(i, j int) bool {
+		return placements[i].WorkPlacement < placements[j].WorkPlacement
+	})
+
+	latest := &v1alpha1.Work{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(work), latest); err != nil {
+		return err
+	}
+
+	changed := !reflect.DeepEqual(latest.Status.Placements, placements)
+	latest.Status.Placements = placements
+
+	if len(placements) == 0 {
+		changed = meta.RemoveStatusCondition(&latest.Status.Conditions, writeSucceededConditionType) || changed
+	} else {
+		condition := metav1.Condition{
+			Type:    writeSucceededConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "AllWorkloadsWritten",
+			Message: "All workloads written to their Destinations",
+		}
+		if len(failed) > 0 {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "WriteFailed"
+			condition.Message = fmt.Sprintf("Writing workloads to Destinations failed: %v", failed)
+		} else if len(pending) > 0 {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "WritePending"
+			condition.Message = fmt.Sprintf("Workloads not yet written to Destinations: %v", pending)
+		}
+		changed = meta.SetStatusCondition(&latest.Status.Conditions, condition) || changed
+	}
+
+	if !changed {
+		return nil
+	}
+	return r.Client.Status().Update(ctx, latest)
+}
+
+// Returns when to reconcile the Work again while the Promise dependencies it
+// holds are being rolled out, and whether they are
+func (r *WorkReconciler) dependencyRolloutRequeue(ctx context.Context, work *v1alpha1.Work) (ctrl.Result, bool, error) {
+	promise := &v1alpha1.Promise{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: work.Spec.PromiseName}, promise); err != nil {
+		return ctrl.Result{}, false, client.IgnoreNotFound(err)
+	}
+
+	rollout := promise.Status.DependencyRollout
+	switch {
+	case rollout == nil || rollout.Phase == v1alpha1.DependencyRolloutCompleted:
+		return ctrl.Result{}, false, nil
+	case rollout.Phase == v1alpha1.DependencyRolloutPaused:
+		// the rollout is resumed by an annotation on the Promise
+		return slowRequeue, true, nil
+	case rollout.NextBatchTime != nil:
+		return ctrl.Result{RequeueAfter: max(time.Until(rollout.NextBatchTime.Time), time.Second)}, true, nil
+	default:
+		return defaultRequeue, true, nil
+	}
+}
+
 func (r *WorkReconciler) deleteWork(ctx context.Context, work *v1alpha1.Work) (ctrl.Result, error) {
+	if v1alpha1.Orphaned(work) {
+		if err := r.orphanWorkPlacements(ctx, work); err != nil {
+			return defaultRequeue, err
+		}
+	}
+
 	workplacementGVK := schema.GroupVersionKind{
 		Group:   v1alpha1.GroupVersion.Group,
 		Version: v1alpha1.GroupVersion.Version,
@@ -113,6 +345,11 @@ func (r *WorkReconciler) deleteWork(ctx context.Context, work *v1alpha1.Work) (c
 	}
 
 	if !resourcesRemaining {
+		// released before the Work is gone, so content claimed for another Work
+		// is not garbage collected with it
+		if err := payload.Release(ctx, r.Client, work); err != nil {
+			return defaultRequeue, err
+		}
 		controllerutil.RemoveFinalizer(work, workCleanUpFinalizer)
 		err = r.Client.Update(ctx, work)
 		if err != nil {
@@ -122,18 +359,110 @@ func (r *WorkReconciler) deleteWork(ctx context.Context, work *v1alpha1.Work) (c
 	return defaultRequeue, nil
 }
 
+// Marks the WorkPlacements of a Work being orphaned to be orphaned too, so their
+// workloads are left in the State Store when they are deleted
+func (r *WorkReconciler) orphanWorkPlacements(ctx context.Context, work *v1alpha1.Work) error {
+	workPlacements := &v1alpha1.WorkPlacementList{}
+	if err := r.Client.List(ctx, workPlacements, client.InNamespace(work.GetNamespace()), client.MatchingLabels{workLabelKey: work.GetName()}); err != nil {
+		return err
+	}
+
+	for _, workPlacement := range workPlacements.Items {
+		if v1alpha1.Orphaned(&workPlacement) {
+			continue
+		}
+		annotations := workPlacement.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[v1alpha1.DeletionPolicyAnnotation] = string(v1alpha1.DeletionPolicyOrphan)
+		workPlacement.SetAnnotations(annotations)
+		if err := r.Client.Update(ctx, &workPlacement); err != nil {
+			return err
+		}
+		r.Log.Info("orphaning workplacement", "workPlacement", workPlacement.GetName(), "destination", workPlacement.Spec.TargetDestinationName)
+	}
+	return nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *WorkReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	for field, indexer := range workFieldIndexers {
+		if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Work{}, field, indexer); err != nil {
+			return err
+		}
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.Work{}).
 		Owns(&v1alpha1.WorkPlacement{}).
 		Watches(
 			&v1alpha1.Destination{},
 			handler.EnqueueRequestsFromMapFunc(r.requestReconcilationOfWorksOnDestination),
+			builder.WithPredicates(destinationSchedulingChangedPredicate),
+		).
+		Watches(
+			&v1alpha1.WorkPlacement{},
+			handler.EnqueueRequestsFromMapFunc(r.requestReconcilationOfUnscheduledWorks),
+			builder.WithPredicates(predicate.Funcs{
+				CreateFunc:  func(event.CreateEvent) bool { return false },
+				UpdateFunc:  func(event.UpdateEvent) bool { return false },
+				DeleteFunc:  func(event.DeleteEvent) bool { return true },
+				GenericFunc: func(event.GenericEvent) bool { return false },
+			}),
+		).
+		Watches(
+			&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.requestReconcilationOfWorksInNamespace),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}),
 		).
 		Complete(r)
 }
 
+// Destinations can restrict access to namespaces by their labels, so when a
+// namespace's labels change the resource requests in it are scheduled again
+func (r *WorkReconciler) requestReconcilationOfWorksInNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	works := &v1alpha1.WorkList{}
+	if err := r.Client.List(ctx, works, client.InNamespace(obj.GetName())); err != nil {
+		r.Log.Error(err, "Error listing Works in namespace", "namespace", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, work := range works.Items {
+		if work.IsResourceRequest() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&work)})
+		}
+	}
+	return requests
+}
+
+// When a WorkPlacement is deleted, capacity may have been freed on its
+// Destination, so any Work that could not be scheduled is tried again
+func (r *WorkReconciler) requestReconcilationOfUnscheduledWorks(ctx context.Context, _ client.Object) []reconcile.Request {
+	unscheduledWorks := &v1alpha1.WorkList{}
+	err := r.Client.List(ctx, unscheduledWorks, client.MatchingFields{workScheduledConditionField: string(metav1.ConditionFalse)})
+	if err != nil {
+		r.Log.Error(err, "Error listing unscheduled Works")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, work := range unscheduledWorks.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&work)})
+	}
+	return requests
+}
+
+// Destination status updates don't affect scheduling, so Works are only
+// reconciled when a Destination's labels or spec change
+var destinationSchedulingChangedPredicate = predicate.Or(predicate.LabelChangedPredicate{}, predicate.GenerationChangedPredicate{})
+
+// Requests reconciliation of the Works that a change to the Destination could
+// affect: the Works scheduled to it, and the Works whose selectors match it
+// that may need to be scheduled to it. Resource requests that are already
+// fully scheduled elsewhere are not affected. On updates, this is called with
+// both the old and new Destination, so Works that no longer match are included.
 func (r *WorkReconciler) requestReconcilationOfWorksOnDestination(ctx context.Context, obj client.Object) []reconcile.Request {
 	dest := obj.(*v1alpha1.Destination)
 
@@ -141,16 +470,45 @@ func (r *WorkReconciler) requestReconcilationOfWorksOnDestination(ctx context.Co
 		return nil
 	}
 
-	allWorks := &v1alpha1.WorkList{}
-	err := r.Client.List(ctx, allWorks)
+	works := map[types.NamespacedName]bool{}
+	scheduledWorks := &v1alpha1.WorkList{}
+	err := r.Client.List(ctx, scheduledWorks, client.MatchingFields{workScheduledDestinationsField: dest.Name})
 	if err != nil {
-		r.Log.Error(err, "Error listing all Works")
+		r.Log.Error(err, "Error listing Works scheduled to Destination", "destination", dest.Name)
 		return nil
 	}
+	for _, work := range scheduledWorks.Items {
+		works[client.ObjectKeyFromObject(&work)] = true
+	}
+
+	labelKeys := []string{anyDestinationLabelKey}
+	for key := range dest.GetLabels() {
+		labelKeys = append(labelKeys, key)
+	}
+	for _, key := range labelKeys {
+		candidateWorks := &v1alpha1.WorkList{}
+		err := r.Client.List(ctx, candidateWorks, client.MatchingFields{workDestinationSelectorKeysField: key})
+		if err != nil {
+			r.Log.Error(err, "Error listing Works selecting Destination label", "destination", dest.Name, "label", key)
+			return nil
+		}
+
+		for _, work := range candidateWorks.Items {
+			if work.IsResourceRequest() && meta.IsStatusConditionTrue(work.Status.Conditions, "Scheduled") {
+				continue
+			}
+			if workSelectsDestination(&work, dest) {
+				works[client.ObjectKeyFromObject(&work)] = true
+			}
+		}
+	}
 
 	var requests []reconcile.Request
-	for _, work := range allWorks.Items {
-		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&work)})
+	for name := range works {
+		requests = append(requests, reconcile.Request{NamespacedName: name})
 	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].String() < requests[j].String()
+	})
 	return requests
 }
@@ -19,15 +19,23 @@ package controllers_test
 import (
 	"context"
 	"errors"
+	"strings"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/syntasso/kratix/api/v1alpha1"
 	"github.com/syntasso/kratix/controllers"
 	"github.com/syntasso/kratix/controllers/controllersfakes"
 	"github.com/syntasso/kratix/lib/hash"
+	"github.com/syntasso/kratix/lib/payload"
+	"github.com/syntasso/kratix/lib/resourceutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -162,6 +170,197 @@ var _ = Describe("WorkReconciler", func() {
 		})
 	})
 
+	When("the work holds Promise dependencies that are being rolled out", func() {
+		BeforeEach(func() {
+			fakeScheduler.ReconcileWorkReturns(nil, nil)
+			work.Spec.ResourceName = ""
+			work.Spec.PromiseName = "rolling-out"
+			work.Labels = map[string]string{v1alpha1.WorkTypeLabel: v1alpha1.WorkTypeStaticDependency}
+			Expect(fakeK8sClient.Create(ctx, work)).To(Succeed())
+			Expect(fakeK8sClient.Get(ctx, workName, work)).To(Succeed())
+
+			promise := &v1alpha1.Promise{ObjectMeta: v1.ObjectMeta{Name: "rolling-out"}}
+			Expect(fakeK8sClient.Create(ctx, promise)).To(Succeed())
+			promise.Status.DependencyRollout = &v1alpha1.DependencyRolloutStatus{Phase: v1alpha1.DependencyRolloutProgressing}
+			Expect(fakeK8sClient.Status().Update(ctx, promise)).To(Succeed())
+		})
+
+		It("re-reconciles until the rollout completes", func() {
+			_, err := t.reconcileUntilCompletion(reconciler, work)
+			Expect(err).To(MatchError("reconcile loop detected"))
+
+			promise := &v1alpha1.Promise{}
+			Expect(fakeK8sClient.Get(ctx, types.NamespacedName{Name: "rolling-out"}, promise)).To(Succeed())
+			promise.Status.DependencyRollout.Phase = v1alpha1.DependencyRolloutCompleted
+			Expect(fakeK8sClient.Status().Update(ctx, promise)).To(Succeed())
+
+			result, err := t.reconcileUntilCompletion(reconciler, work)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+		})
+	})
+
+	When("the work has been placed on Destinations", func() {
+		createWorkPlacement := func(destination string, condition *v1.Condition) {
+			wp := &v1alpha1.WorkPlacement{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      work.Name + "." + destination,
+					Namespace: "default",
+					Labels:    map[string]string{v1alpha1.KratixPrefix + "work": work.Name},
+				},
+				Spec: v1alpha1.WorkPlacementSpec{TargetDestinationName: destination, ID: "wg"},
+			}
+			Expect(fakeK8sClient.Create(ctx, wp)).To(Succeed())
+			if condition != nil {
+				wp.Status.Conditions = []v1.Condition{*condition}
+				Expect(fakeK8sClient.Status().Update(ctx, wp)).To(Succeed())
+			}
+		}
+
+		BeforeEach(func() {
+			fakeScheduler.ReconcileWorkReturns(nil, nil)
+			Expect(fakeK8sClient.Create(ctx, work)).To(Succeed())
+			Expect(fakeK8sClient.Get(ctx, workName, work)).To(Succeed())
+
+			createWorkPlacement("written", &v1.Condition{Type: "WriteSucceeded", Status: v1.ConditionTrue, Reason: "WorkloadsWritten", LastTransitionTime: v1.Now()})
+			createWorkPlacement("pending", nil)
+		})
+
+		It("aggregates the write status of the placements", func() {
+			_, err := t.reconcileUntilCompletion(reconciler, work)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeK8sClient.Get(ctx, workName, work)).To(Succeed())
+			Expect(work.Status.Placements).To(Equal([]v1alpha1.PlacementStatus{
+				{WorkPlacement: work.Name + ".pending", WorkloadGroupID: "wg", Destination: "pending", WriteStatus: v1alpha1.WriteStatusPending},
+				{WorkPlacement: work.Name + ".written", WorkloadGroupID: "wg", Destination: "written", WriteStatus: v1alpha1.WriteStatusWritten},
+			}))
+			condition := meta.FindStatusCondition(work.Status.Conditions, "WriteSucceeded")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("WritePending"))
+		})
+
+		It("reports failed writes", func() {
+			createWorkPlacement("failed", &v1.Condition{Type: "WriteSucceeded", Status: v1.ConditionFalse, Reason: "WriteFailed", Message: "push rejected", LastTransitionTime: v1.Now()})
+
+			_, err := t.reconcileUntilCompletion(reconciler, work)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeK8sClient.Get(ctx, workName, work)).To(Succeed())
+			Expect(work.Status.Placements).To(ContainElement(v1alpha1.PlacementStatus{
+				WorkPlacement: work.Name + ".failed", WorkloadGroupID: "wg", Destination: "failed", WriteStatus: v1alpha1.WriteStatusFailed, Message: "push rejected",
+			}))
+			condition := meta.FindStatusCondition(work.Status.Conditions, "WriteSucceeded")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("WriteFailed"))
+			Expect(condition.Message).To(ContainSubstring("[failed]"))
+		})
+	})
+
+	When("the work-creator stored workload content in ConfigMaps", func() {
+		BeforeEach(func() {
+			fakeScheduler.ReconcileWorkReturns(nil, nil)
+			work.Spec.WorkloadGroups[0].Workloads[0].Content = strings.Repeat("a", payload.MinDeduplicatedContentSize)
+			Expect(payload.Deduplicate(ctx, fakeK8sClient, "default", work.Spec.WorkloadGroups)).To(Succeed())
+			Expect(fakeK8sClient.Create(ctx, work)).To(Succeed())
+			Expect(fakeK8sClient.Get(ctx, workName, work)).To(Succeed())
+
+			orphan := &corev1.ConfigMap{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "kratix-payload-orphan-0",
+					Namespace: "default",
+					Labels:    map[string]string{payload.DigestLabel: "orphan"},
+				},
+			}
+			Expect(fakeK8sClient.Create(ctx, orphan)).To(Succeed())
+		})
+
+		It("owns the ConfigMaps it references and deletes the ones left without a Work", func() {
+			_, err := t.reconcileUntilCompletion(reconciler, work)
+			Expect(err).NotTo(HaveOccurred())
+
+			configMaps := &corev1.ConfigMapList{}
+			Expect(fakeK8sClient.List(ctx, configMaps, client.InNamespace("default"), client.HasLabels{payload.DigestLabel})).To(Succeed())
+			Expect(configMaps.Items).To(HaveLen(1))
+			Expect(configMaps.Items[0].GetLabels()).To(HaveKeyWithValue(payload.DigestLabel, work.Spec.WorkloadGroups[0].Workloads[0].ContentRef.Digest[:32]))
+			Expect(configMaps.Items[0].GetOwnerReferences()).To(ConsistOf(HaveField("Name", work.GetName())))
+		})
+
+		It("releases the ConfigMaps before the Work is deleted", func() {
+			_, err := t.reconcileUntilCompletion(reconciler, work)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeK8sClient.Delete(ctx, work)).To(Succeed())
+			_, err = t.reconcileUntilCompletion(reconciler, work)
+			Expect(err).NotTo(HaveOccurred())
+
+			configMaps := &corev1.ConfigMapList{}
+			Expect(fakeK8sClient.List(ctx, configMaps, client.InNamespace("default"), client.HasLabels{payload.DigestLabel})).To(Succeed())
+			Expect(configMaps.Items).To(BeEmpty())
+		})
+	})
+
+	When("a ConfigMap holding the workload content of the Work is missing", func() {
+		var promise *v1alpha1.Promise
+
+		reconcile := func() (ctrl.Result, error) {
+			return reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: workName})
+		}
+
+		BeforeEach(func() {
+			fakeScheduler.ReconcileWorkReturns(nil, nil)
+			promise = &v1alpha1.Promise{ObjectMeta: v1.ObjectMeta{Name: "test-promise"}}
+			Expect(fakeK8sClient.Create(ctx, promise)).To(Succeed())
+
+			work.Spec.PromiseName = promise.GetName()
+			work.Spec.ResourceName = ""
+			work.Spec.WorkloadGroups[0].Workloads[0].Content = strings.Repeat("a", payload.MinDeduplicatedContentSize)
+			Expect(payload.Deduplicate(ctx, fakeK8sClient, "default", work.Spec.WorkloadGroups)).To(Succeed())
+			Expect(fakeK8sClient.Create(ctx, work)).To(Succeed())
+			Expect(fakeK8sClient.DeleteAllOf(ctx, &corev1.ConfigMap{}, client.InNamespace("default"), client.HasLabels{payload.DigestLabel})).To(Succeed())
+
+			// the first reconcile adds the finalizer
+			_, err := reconcile()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("marks the Work as failed and reruns the workflow that stored the content", func() {
+			result, err := reconcile()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(fakeScheduler.ReconcileWorkCallCount()).To(Equal(0))
+
+			Expect(fakeK8sClient.Get(ctx, workName, work)).To(Succeed())
+			condition := meta.FindStatusCondition(work.Status.Conditions, "PayloadAvailable")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("PayloadMissing"))
+
+			Expect(fakeK8sClient.Get(ctx, client.ObjectKeyFromObject(promise), promise)).To(Succeed())
+			Expect(promise.GetLabels()).To(HaveKeyWithValue(resourceutil.ManualReconciliationLabel, "true"))
+
+			By("not rerunning the workflow again while it runs")
+			promise.SetLabels(nil)
+			Expect(fakeK8sClient.Update(ctx, promise)).To(Succeed())
+			_, err = reconcile()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeK8sClient.Get(ctx, client.ObjectKeyFromObject(promise), promise)).To(Succeed())
+			Expect(promise.GetLabels()).NotTo(HaveKey(resourceutil.ManualReconciliationLabel))
+
+			By("scheduling the Work once the workflow stores the content again")
+			Expect(payload.Deduplicate(ctx, fakeK8sClient, "default", []v1alpha1.WorkloadGroup{{Workloads: []v1alpha1.Workload{
+				{Content: strings.Repeat("a", payload.MinDeduplicatedContentSize)},
+			}}})).To(Succeed())
+			_, err = reconcile()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeScheduler.ReconcileWorkCallCount()).To(Equal(1))
+			Expect(fakeK8sClient.Get(ctx, workName, work)).To(Succeed())
+			Expect(meta.FindStatusCondition(work.Status.Conditions, "PayloadAvailable")).To(BeNil())
+		})
+	})
+
 	When("work is deleted", func() {
 		BeforeEach(func() {
 			fakeScheduler.ReconcileWorkReturns([]string{}, nil)
@@ -202,4 +401,160 @@ var _ = Describe("WorkReconciler", func() {
 				&v1alpha1.WorkPlacement{})).To(MatchError(ContainSubstring("not found")))
 		})
 	})
+
+	When("a Work marked to be orphaned is deleted", func() {
+		BeforeEach(func() {
+			fakeScheduler.ReconcileWorkReturns([]string{}, nil)
+			work.SetAnnotations(map[string]string{v1alpha1.DeletionPolicyAnnotation: "Orphan"})
+			Expect(fakeK8sClient.Create(ctx, work)).To(Succeed())
+			_, err := t.reconcileUntilCompletion(reconciler, work)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("marks every WorkPlacement of the Work to be orphaned before deleting them", func() {
+			workPlacement := &v1alpha1.WorkPlacement{
+				ObjectMeta: v1.ObjectMeta{
+					Name:       work.Name + ".created-during-deletion",
+					Namespace:  "default",
+					Labels:     map[string]string{v1alpha1.KratixPrefix + "work": work.Name},
+					Finalizers: []string{"finalizers.workplacement.kratix.io/repo-cleanup"},
+				},
+				Spec: v1alpha1.WorkPlacementSpec{TargetDestinationName: "test-destination"},
+			}
+			Expect(fakeK8sClient.Create(ctx, workPlacement)).To(Succeed())
+
+			Expect(fakeK8sClient.Delete(ctx, work)).To(Succeed())
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: workName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeK8sClient.Get(ctx, client.ObjectKeyFromObject(workPlacement), workPlacement)).To(Succeed())
+			Expect(workPlacement.GetDeletionTimestamp()).NotTo(BeNil())
+			Expect(workPlacement.GetAnnotations()).To(HaveKeyWithValue(v1alpha1.DeletionPolicyAnnotation, "Orphan"))
+		})
+	})
+
+	Describe("Destination changes", func() {
+		var destination *v1alpha1.Destination
+
+		createWork := func(name, resourceName string, scheduling []v1alpha1.WorkloadGroupScheduling, scheduled bool, scheduledTo ...string) {
+			w := &v1alpha1.Work{
+				ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec: v1alpha1.WorkSpec{
+					ResourceName: resourceName,
+					WorkloadGroups: []v1alpha1.WorkloadGroup{
+						{ID: "wg", DestinationSelectors: scheduling},
+					},
+				},
+			}
+			Expect(fakeK8sClient.Create(ctx, w)).To(Succeed())
+
+			status := v1.ConditionFalse
+			if scheduled {
+				status = v1.ConditionTrue
+			}
+			w.Status.Conditions = []v1.Condition{
+				{Type: "Scheduled", Status: status, Reason: "Test", LastTransitionTime: v1.Now()},
+			}
+			w.Status.WorkloadGroups = []v1alpha1.WorkloadGroupStatus{{ID: "wg", Destinations: scheduledTo}}
+			Expect(fakeK8sClient.Status().Update(ctx, w)).To(Succeed())
+		}
+
+		requestedWorks := func() []string {
+			var names []string
+			for _, request := range reconciler.RequestReconcilationOfWorksOnDestination(ctx, destination) {
+				names = append(names, request.Name)
+			}
+			return names
+		}
+
+		BeforeEach(func() {
+			destination = &v1alpha1.Destination{
+				ObjectMeta: v1.ObjectMeta{
+					Name:   "dev-1",
+					Labels: map[string]string{"environment": "dev"},
+				},
+			}
+
+			createWork("dependency-dev", "", []v1alpha1.WorkloadGroupScheduling{
+				{MatchLabels: map[string]string{"environment": "dev"}, Source: "promise"},
+			}, true, "dev-2")
+			createWork("dependency-prod", "", []v1alpha1.WorkloadGroupScheduling{
+				{MatchLabels: map[string]string{"environment": "prod"}, Source: "promise"},
+			}, true, "prod")
+			createWork("dependency-anywhere", "", nil, true, "dev-2", "prod")
+			createWork("dependency-not-pci", "", []v1alpha1.WorkloadGroupScheduling{
+				{MatchExpressions: []v1.LabelSelectorRequirement{{Key: "pci", Operator: v1.LabelSelectorOpDoesNotExist}}, Source: "promise"},
+			}, true)
+			createWork("resource-scheduled-elsewhere", "resource", []v1alpha1.WorkloadGroupScheduling{
+				{MatchLabels: map[string]string{"environment": "dev"}, Source: "promise"},
+			}, true, "dev-2")
+			createWork("resource-scheduled-here", "resource", []v1alpha1.WorkloadGroupScheduling{
+				{MatchLabels: map[string]string{"environment": "prod"}, Source: "promise"},
+			}, true, "dev-1")
+			createWork("resource-unscheduled", "resource", []v1alpha1.WorkloadGroupScheduling{
+				{MatchLabels: map[string]string{"environment": "dev"}, Source: "promise"},
+			}, false)
+		})
+
+		It("only requests reconciliation of the Works the Destination change could affect", func() {
+			Expect(requestedWorks()).To(ConsistOf(
+				"dependency-dev",
+				"dependency-anywhere",
+				"dependency-not-pci",
+				"resource-scheduled-here",
+				"resource-unscheduled",
+			))
+		})
+
+		It("does not request reconciliation of Works selecting no labels for a strict Destination", func() {
+			destination.Spec.StrictMatchLabels = true
+			Expect(requestedWorks()).NotTo(ContainElement("dependency-anywhere"))
+		})
+
+		It("does not request reconciliation when the Destination is being deleted", func() {
+			now := v1.Now()
+			destination.DeletionTimestamp = &now
+			Expect(requestedWorks()).To(BeEmpty())
+		})
+
+		It("only requests reconciliation of unscheduled Works when a WorkPlacement is deleted", func() {
+			var names []string
+			for _, request := range reconciler.RequestReconcilationOfUnscheduledWorks(ctx, &v1alpha1.WorkPlacement{}) {
+				names = append(names, request.Name)
+			}
+			Expect(names).To(ConsistOf("resource-unscheduled"))
+		})
+
+		It("requests reconciliation of the resource request Works in a namespace", func() {
+			var names []string
+			namespace := &corev1.Namespace{ObjectMeta: v1.ObjectMeta{Name: "default"}}
+			for _, request := range reconciler.RequestReconcilationOfWorksInNamespace(ctx, namespace) {
+				names = append(names, request.Name)
+			}
+			Expect(names).To(ConsistOf("resource-scheduled-elsewhere", "resource-scheduled-here", "resource-unscheduled"))
+
+			namespace.SetName("other")
+			Expect(reconciler.RequestReconcilationOfWorksInNamespace(ctx, namespace)).To(BeEmpty())
+		})
+
+		It("ignores Destination status updates", func() {
+			updated := destination.DeepCopy()
+			updated.Status.Drain = &v1alpha1.DrainStatus{Phase: v1alpha1.DrainPhaseDrained}
+			Expect(controllers.DestinationSchedulingChangedPredicate().Update(event.UpdateEvent{
+				ObjectOld: destination, ObjectNew: updated,
+			})).To(BeFalse())
+
+			updated = destination.DeepCopy()
+			updated.Labels["environment"] = "prod"
+			Expect(controllers.DestinationSchedulingChangedPredicate().Update(event.UpdateEvent{
+				ObjectOld: destination, ObjectNew: updated,
+			})).To(BeTrue())
+
+			updated = destination.DeepCopy()
+			updated.Generation = destination.Generation + 1
+			Expect(controllers.DestinationSchedulingChangedPredicate().Update(event.UpdateEvent{
+				ObjectOld: destination, ObjectNew: updated,
+			})).To(BeTrue())
+		})
+	})
 })
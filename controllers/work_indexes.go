@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Indexes Works by the Destination label keys their WorkloadGroups select
+	// on. Works that can match a Destination without any of the keys being one
+	// of its labels, because their selectors are empty or only exclude values,
+	// are also indexed under anyDestinationLabelKey.
+	workDestinationSelectorKeysField = "spec.workloadGroups.destinationSelectors.keys"
+	// Indexes Works by the Destinations their WorkloadGroups are scheduled to
+	workScheduledDestinationsField = "status.workloadGroups.destinations"
+	// Indexes Works by the status of their Scheduled condition, so the Works
+	// that could not be scheduled can be found without listing every Work
+	workScheduledConditionField = "status.conditions.scheduled"
+
+	// not a valid label key, so it can't clash with a real one
+	anyDestinationLabelKey = "*"
+)
+
+var workFieldIndexers = map[string]client.IndexerFunc{
+	workDestinationSelectorKeysField: indexWorkDestinationSelectorKeys,
+	workScheduledDestinationsField:   indexWorkScheduledDestinations,
+	workScheduledConditionField:      indexWorkScheduledCondition,
+}
+
+func indexWorkDestinationSelectorKeys(obj client.Object) []string {
+	work := obj.(*v1alpha1.Work)
+	keys := map[string]bool{}
+	for _, wg := range work.Spec.WorkloadGroups {
+		destinationSelectors := resolveWorkloadGroupSelectors(wg, work)
+		if isEmptyLabelSelector(destinationSelectors) {
+			keys[anyDestinationLabelKey] = true
+		}
+		for key := range destinationSelectors.MatchLabels {
+			keys[key] = true
+		}
+		for _, expression := range destinationSelectors.MatchExpressions {
+			keys[expression.Key] = true
+			if expression.Operator == metav1.LabelSelectorOpNotIn || expression.Operator == metav1.LabelSelectorOpDoesNotExist {
+				keys[anyDestinationLabelKey] = true
+			}
+		}
+	}
+	return sortedKeys(keys)
+}
+
+func indexWorkScheduledDestinations(obj client.Object) []string {
+	work := obj.(*v1alpha1.Work)
+	destinations := map[string]bool{}
+	for _, wg := range work.Status.WorkloadGroups {
+		for _, destination := range wg.Destinations {
+			destinations[destination] = true
+		}
+	}
+	return sortedKeys(destinations)
+}
+
+func indexWorkScheduledCondition(obj client.Object) []string {
+	work := obj.(*v1alpha1.Work)
+	condition := meta.FindStatusCondition(work.Status.Conditions, "Scheduled")
+	if condition == nil {
+		return nil
+	}
+	return []string{string(condition.Status)}
+}
+
+// Returns whether any WorkloadGroup of the Work can be scheduled to the
+// Destination based on its labels
+func workSelectsDestination(work *v1alpha1.Work, destination *v1alpha1.Destination) bool {
+	for _, wg := range work.Spec.WorkloadGroups {
+		destinationSelectors := resolveWorkloadGroupSelectors(wg, work)
+		if isEmptyLabelSelector(destinationSelectors) {
+			if !destination.Spec.StrictMatchLabels || len(destination.GetLabels()) == 0 {
+				return true
+			}
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(&destinationSelectors)
+		if err == nil && selector.Matches(labels.Set(destination.GetLabels())) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolves the WorkloadGroup's selectors without reordering the
+// destinationSelectors of the given Work, which may be shared with the cache
+func resolveWorkloadGroupSelectors(workloadGroup v1alpha1.WorkloadGroup, work *v1alpha1.Work) metav1.LabelSelector {
+	workloadGroup.DestinationSelectors = slices.Clone(workloadGroup.DestinationSelectors)
+	return resolveDestinationSelectorsForWorkloadGroup(workloadGroup, work)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"github.com/syntasso/kratix/lib/writers"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const approvedConditionType = "Approved"
+
+// Checks whether the workloads of the WorkPlacement can be written to a
+// Destination that requires approval, and records the approval, or the
+// changes waiting for it, on the WorkPlacement status. Returns whether the
+// workloads are approved, and whether the status changed.
+func (r *WorkPlacementReconciler) reconcileApproval(ctx context.Context, writer writers.StateStoreWriter, workPlacement *v1alpha1.WorkPlacement, destination v1alpha1.Destination) (bool, bool, error) {
+	if !destination.Spec.ApprovalRequired {
+		changed := workPlacement.Status.Approval != nil
+		workPlacement.Status.Approval = nil
+		return true, meta.RemoveStatusCondition(&workPlacement.Status.Conditions, approvedConditionType) || changed, nil
+	}
+
+	revision, err := v1alpha1.WorkloadsRevision(workPlacement.Spec.Workloads)
+	if err != nil {
+		return false, false, err
+	}
+
+	current := workPlacement.Status.Approval
+	if current != nil && current.Revision == revision && current.ApprovedBy != "" {
+		return true, false, nil
+	}
+	if current == nil && writeSucceeded(*workPlacement) {
+		// written before the Destination required approval
+		return true, false, nil
+	}
+
+	approval := &v1alpha1.WorkPlacementApproval{Revision: revision}
+	if current != nil && current.Revision == revision {
+		approval.Changes = current.Changes
+	} else {
+		if approval.Changes, err = r.summariseChanges(ctx, writer, *workPlacement, destination); err != nil {
+			return false, false, err
+		}
+	}
+
+	approval.ApprovedBy, err = r.approvedBy(ctx, *workPlacement, revision)
+	if err != nil {
+		return false, false, err
+	}
+
+	condition := metav1.Condition{
+		Type:               approvedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Approved",
+		Message:            fmt.Sprintf("Revision %s approved by %s", revision, approval.ApprovedBy),
+		ObservedGeneration: workPlacement.GetGeneration(),
+	}
+	if approval.ApprovedBy == "" {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "PendingApproval"
+		condition.Message = fmt.Sprintf("Revision %s is waiting for approval before being written to Destination %s: %d changes",
+			revision, destination.GetName(), len(approval.Changes))
+	}
+
+	changed := !reflect.DeepEqual(current, approval)
+	workPlacement.Status.Approval = approval
+	changed = meta.SetStatusCondition(&workPlacement.Status.Conditions, condition) || changed
+	return approval.ApprovedBy != "", changed, nil
+}
+
+// Returns who approved the revision of the WorkPlacement's workloads, with
+// the approve annotation or an Approval, or an empty string if no one has. The
+// approvers are recorded by the admission webhooks, so an approval without
+// one was not made through them and is ignored.
+func (r *WorkPlacementReconciler) approvedBy(ctx context.Context, workPlacement v1alpha1.WorkPlacement, revision string) (string, error) {
+	annotations := workPlacement.GetAnnotations()
+	if annotations[v1alpha1.ApprovedRevisionAnnotation] == revision && annotations[v1alpha1.ApprovedByAnnotation] != "" {
+		return annotations[v1alpha1.ApprovedByAnnotation], nil
+	}
+
+	approvals := &v1alpha1.ApprovalList{}
+	if err := r.Client.List(ctx, approvals, client.InNamespace(workPlacement.GetNamespace())); err != nil {
+		return "", err
+	}
+	for _, approval := range approvals.Items {
+		if approval.Spec.WorkPlacement == workPlacement.GetName() && approval.Spec.Revision == revision && approval.Spec.ApprovedBy != "" {
+			return approval.Spec.ApprovedBy, nil
+		}
+	}
+	return "", nil
+}
+
+// Returns the files the workloads create, update and delete in the State
+// Store
+func (r *WorkPlacementReconciler) summariseChanges(ctx context.Context, writer writers.StateStoreWriter, workPlacement v1alpha1.WorkPlacement, destination v1alpha1.Destination) ([]string, error) {
+	dir, workloads, err := r.renderWorkloads(ctx, workPlacement, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	stateFilePath := fmt.Sprintf(".kratix/%s-%s.yaml", workPlacement.Namespace, workPlacement.Name)
+	var paths []string
+	for _, workload := range workloads {
+		paths = append(paths, filepath.Join(dir, workload.Filepath))
+	}
+	files, err := writer.ReadFiles(append(paths, stateFilePath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read written files: %w", err)
+	}
+
+	var changes []string
+	for i, workload := range workloads {
+		content, found := files[paths[i]]
+		switch {
+		case !found:
+			changes = append(changes, "create "+paths[i])
+		case string(content) != workload.Content:
+			changes = append(changes, "update "+paths[i])
+		}
+	}
+
+	if tracksFilesInStateFile(destination.GetFilepathMode()) {
+		stateFile := StateFile{}
+		if err := yaml.Unmarshal(files[stateFilePath], &stateFile); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal .kratix state file: %s", err)
+		}
+		for _, path := range cleanupWorkloads(stateFile.Files, workloads) {
+			changes = append(changes, "delete "+path)
+		}
+	}
+	return changes, nil
+}
+
+// Returns the WorkPlacement an Approval is for
+func (r *WorkPlacementReconciler) requestReconciliationOfApprovedWorkPlacement(ctx context.Context, obj client.Object) []reconcile.Request {
+	approval, ok := obj.(*v1alpha1.Approval)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{
+		Namespace: approval.GetNamespace(),
+		Name:      approval.Spec.WorkPlacement,
+	}}}
+}
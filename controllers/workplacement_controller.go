@@ -21,24 +21,34 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"gopkg.in/yaml.v2"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	"github.com/syntasso/kratix/api/v1alpha1"
 	"github.com/syntasso/kratix/lib/compression"
+	"github.com/syntasso/kratix/lib/hash"
+	"github.com/syntasso/kratix/lib/payload"
 	"github.com/syntasso/kratix/lib/writers"
 )
 
 const (
 	resourcesDir    = "resources"
 	dependenciesDir = "dependencies"
+
+	writeSucceededConditionType = "WriteSucceeded"
+	driftedConditionType        = "Drifted"
 )
 
 type StateFile struct {
@@ -49,8 +59,6 @@ type StateFile struct {
 type WorkPlacementReconciler struct {
 	Client client.Client
 	Log    logr.Logger
-
-	VersionCache map[string]string
 }
 
 const (
@@ -61,6 +69,8 @@ const (
 //+kubebuilder:rbac:groups=platform.kratix.io,resources=workplacements,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=platform.kratix.io,resources=workplacements/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=platform.kratix.io,resources=workplacements/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get
+//+kubebuilder:rbac:groups=platform.kratix.io,resources=approvals,verbs=get;list;watch
 
 func (r *WorkPlacementReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := r.Log.WithValues("work-placement-controller", req.NamespacedName)
@@ -87,6 +97,21 @@ func (r *WorkPlacementReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
+	suspendedBy, err := r.writesSuspendedBy(ctx, workPlacement, destination)
+	if err != nil {
+		logger.Error(err, "Error checking whether writes are suspended")
+		return defaultRequeue, nil
+	}
+	if setWritesSuspendedCondition(workPlacement, suspendedBy) {
+		if err := r.Client.Status().Update(ctx, workPlacement); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if suspendedBy != "" {
+		logger.Info("writes are suspended; not writing to statestore", "suspendedBy", suspendedBy)
+		return defaultRequeue, nil
+	}
+
 	opts := opts{
 		client: r.Client,
 		ctx:    ctx,
@@ -95,6 +120,10 @@ func (r *WorkPlacementReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	//Mock this out
 	writer, err := newWriter(opts, *destination)
+	if err == errNoStateStoreRef {
+		logger.Info("Destination has no stateStoreRef; not writing to statestore", "destination", destination.Name)
+		return defaultRequeue, nil
+	}
 	if err != nil {
 		if k8sErrors.IsNotFound(err) {
 			return defaultRequeue, nil
@@ -103,32 +132,101 @@ func (r *WorkPlacementReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	filepathMode := destination.GetFilepathMode()
+	// Deletions are exempt from approval: removing workloads from a Destination
+	// that requires approval happens as soon as the WorkPlacement is deleted.
 	if !workPlacement.DeletionTimestamp.IsZero() {
 		return r.deleteWorkPlacement(ctx, writer, workPlacement, filepathMode, logger)
 	}
 
+	approved, approvalChanged, err := r.reconcileApproval(ctx, writer, workPlacement, *destination)
+	if err != nil {
+		logger.Error(err, "Error checking the approval of the workloads")
+		return defaultRequeue, err
+	}
+	if !approved {
+		logger.Info("workloads are waiting for approval; not writing to statestore", "revision", workPlacement.Status.Approval.Revision)
+		if approvalChanged {
+			if err := r.Client.Status().Update(ctx, workPlacement); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	var remediated []string
+	var pathsHash string
+	driftDetection := destination.GetDriftDetection()
+	if driftDetection != nil {
+		// a failure to render the workloads is reported by writing them below
+		dir, workloads, renderErr := r.renderWorkloads(ctx, *workPlacement, *destination)
+		if renderErr == nil {
+			paths := workloadPaths(dir, workloads)
+			pathsHash = writtenPathsHash(*destination, paths)
+			// changes to the Destination or to a templated path do not change the
+			// WorkPlacement's generation, so its workloads are only checked for
+			// drift while they are still written to the same paths
+			if writeSucceeded(*workPlacement) && workPlacement.Status.WrittenPathsHash == pathsHash {
+				drifted, err := detectDrift(writer, paths, workloads)
+				if err != nil {
+					logger.Error(err, "Error checking the statestore for drift")
+					return defaultRequeue, err
+				}
+
+				if len(drifted) == 0 || !driftDetection.Remediate {
+					if len(drifted) > 0 {
+						logger.Info("files in statestore drifted from the workloads", "files", drifted)
+					}
+					statusChanged := setVersionIDStatus(workPlacement, logger) || approvalChanged
+					statusChanged = setDriftedCondition(workPlacement, drifted, false) || statusChanged
+					if statusChanged {
+						if err := r.Client.Status().Update(ctx, workPlacement); err != nil {
+							return ctrl.Result{}, err
+						}
+					}
+					return periodicRequeue(*destination), nil
+				}
+
+				logger.Info("writing drifted files to statestore again", "files", drifted)
+				remediated = drifted
+			}
+		}
+	}
+
 	logger.Info("Updating files in statestore if required")
-	versionID, err := r.writeWorkloadsToStateStore(writer, *workPlacement, *destination, logger)
+	versionID, err := r.writeWorkloadsToStateStore(ctx, writer, *workPlacement, *destination, logger)
 	if err != nil {
 		logger.Error(err, "Error writing to repository, will try again in 5 seconds")
+		if setWriteSucceededCondition(workPlacement, err) {
+			if statusErr := r.Client.Status().Update(ctx, workPlacement); statusErr != nil {
+				logger.Error(statusErr, "Error updating WorkPlacement status")
+			}
+		}
 		return defaultRequeue, err
 	}
 
-	if versionID == "" && r.VersionCache[workPlacement.GetUniqueID()] != "" {
-		versionID = r.VersionCache[workPlacement.GetUniqueID()]
-		delete(r.VersionCache, workPlacement.GetUniqueID())
+	if versionID != "" && workPlacement.GetAnnotations()[v1alpha1.VersionIDAnnotation] != versionID {
+		if err := r.recordVersionID(ctx, workPlacement, versionID); err != nil {
+			logger.Error(err, "Error recording VersionID on WorkPlacement")
+			return ctrl.Result{}, err
+		}
+	}
+
+	statusChanged := setWriteSucceededCondition(workPlacement, nil) || approvalChanged
+	if driftDetection != nil {
+		statusChanged = setDriftedCondition(workPlacement, remediated, true) || statusChanged
+		if pathsHash != "" && workPlacement.Status.WrittenPathsHash != pathsHash {
+			workPlacement.Status.WrittenPathsHash = pathsHash
+			statusChanged = true
+		}
 	}
+	statusChanged = setVersionIDStatus(workPlacement, logger) || statusChanged
 
-	if versionID != "" && workPlacement.Status.VersionID != versionID {
-		workPlacement.Status.VersionID = versionID
-		logger.Info("Updating version status", "versionID", versionID)
+	if statusChanged {
 		err = r.Client.Status().Update(ctx, workPlacement)
 		if kerrors.IsConflict(err) {
-			r.VersionCache[workPlacement.GetUniqueID()] = versionID
 			r.Log.Info("failed to update WorkPlacement status due to update conflict, requeue...")
 			return fastRequeue, nil
 		} else if err != nil {
-			r.VersionCache[workPlacement.GetUniqueID()] = versionID
 			logger.Error(err, "Error updating WorkPlacement status")
 			return ctrl.Result{}, err
 		}
@@ -139,7 +237,142 @@ func (r *WorkPlacementReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	logger.Info("WorkPlacement successfully reconciled", "workPlacement", workPlacement.Name, "versionID", versionID)
-	return ctrl.Result{}, nil
+	return periodicRequeue(*destination), nil
+}
+
+// Records the VersionID on the WorkPlacement's annotations as soon as the
+// workloads are written, so it is not lost if updating the status fails or
+// Kratix restarts before it does, as writers return no VersionID when nothing
+// changed
+func (r *WorkPlacementReconciler) recordVersionID(ctx context.Context, workPlacement *v1alpha1.WorkPlacement, versionID string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &v1alpha1.WorkPlacement{}
+		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(workPlacement), latest); err != nil {
+			return err
+		}
+		annotations := latest.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[v1alpha1.VersionIDAnnotation] = versionID
+		latest.SetAnnotations(annotations)
+		if err := r.Client.Update(ctx, latest); err != nil {
+			return err
+		}
+		workPlacement.SetAnnotations(latest.GetAnnotations())
+		workPlacement.SetResourceVersion(latest.GetResourceVersion())
+		return nil
+	})
+}
+
+// Sets Status.VersionID to the VersionID recorded on the WorkPlacement,
+// returning whether it changed
+func setVersionIDStatus(workPlacement *v1alpha1.WorkPlacement, logger logr.Logger) bool {
+	versionID := workPlacement.GetAnnotations()[v1alpha1.VersionIDAnnotation]
+	if versionID == "" || workPlacement.Status.VersionID == versionID {
+		return false
+	}
+	logger.Info("Updating version status", "versionID", versionID)
+	workPlacement.Status.VersionID = versionID
+	return true
+}
+
+// Returns when to reconcile the WorkPlacement again to check for drift. The
+// health feedback is read by the Destination reconciler for all of its
+// WorkPlacements at once.
+func periodicRequeue(destination v1alpha1.Destination) ctrl.Result {
+	if driftDetection := destination.GetDriftDetection(); driftDetection != nil {
+		return ctrl.Result{RequeueAfter: driftDetection.Interval.Duration}
+	}
+	return ctrl.Result{}
+}
+
+// Sets the WriteSucceeded condition from the result of writing the
+// workloads, returning whether the condition changed
+func setWriteSucceededCondition(workPlacement *v1alpha1.WorkPlacement, writeErr error) bool {
+	condition := metav1.Condition{
+		Type:               writeSucceededConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "WorkloadsWritten",
+		Message:            "Workloads written to the Destination",
+		ObservedGeneration: workPlacement.GetGeneration(),
+	}
+	if writeErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "WriteFailed"
+		condition.Message = writeErr.Error()
+	}
+	return meta.SetStatusCondition(&workPlacement.Status.Conditions, condition)
+}
+
+// Returns true if the WorkPlacement's current workloads have been written to
+// its Destination
+func writeSucceeded(workPlacement v1alpha1.WorkPlacement) bool {
+	condition := meta.FindStatusCondition(workPlacement.Status.Conditions, writeSucceededConditionType)
+	return condition != nil &&
+		condition.Status == metav1.ConditionTrue &&
+		condition.ObservedGeneration == workPlacement.GetGeneration()
+}
+
+// Returns the paths of the workloads in the State Store
+func workloadPaths(dir string, workloads []v1alpha1.Workload) []string {
+	var paths []string
+	for _, workload := range workloads {
+		paths = append(paths, filepath.Join(dir, workload.Filepath))
+	}
+	return paths
+}
+
+// Returns a hash of where the workloads are written: the State Store and path
+// of the Destination, and the paths of the workloads within it
+func writtenPathsHash(destination v1alpha1.Destination, paths []string) string {
+	location := []string{destination.Spec.StateStoreRef.Kind, destination.Spec.StateStoreRef.Name, destination.Spec.Path}
+	return hash.ComputeHash(strings.Join(append(location, paths...), "\n"))
+}
+
+// Reads back the files written for the workloads and returns the ones that no
+// longer match them
+func detectDrift(writer writers.StateStoreWriter, paths []string, workloads []v1alpha1.Workload) ([]string, error) {
+	files, err := writer.ReadFiles(paths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read written files: %w", err)
+	}
+
+	var drifted []string
+	for i, workload := range workloads {
+		content, found := files[paths[i]]
+		if !found {
+			drifted = append(drifted, paths[i]+" (deleted)")
+			continue
+		}
+		if string(content) != workload.Content {
+			drifted = append(drifted, paths[i])
+		}
+	}
+	return drifted, nil
+}
+
+// Sets the Drifted condition from the files that no longer match the
+// workloads, and whether they have been written again, returning whether the
+// condition changed
+func setDriftedCondition(workPlacement *v1alpha1.WorkPlacement, drifted []string, remediated bool) bool {
+	condition := metav1.Condition{
+		Type:               driftedConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NoDrift",
+		Message:            "Files in the statestore match the workloads",
+		ObservedGeneration: workPlacement.GetGeneration(),
+	}
+	switch {
+	case len(drifted) > 0 && remediated:
+		condition.Reason = "DriftRemediated"
+		condition.Message = fmt.Sprintf("Files that drifted were written again: %v", drifted)
+	case len(drifted) > 0:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "FilesDrifted"
+		condition.Message = fmt.Sprintf("Files in the statestore differ from the workloads: %v", drifted)
+	}
+	return meta.SetStatusCondition(&workPlacement.Status.Conditions, condition)
 }
 
 func (r *WorkPlacementReconciler) deleteWorkPlacement(ctx context.Context, writer writers.StateStoreWriter, workPlacement *v1alpha1.WorkPlacement, filePathMode string, logger logr.Logger) (ctrl.Result, error) {
@@ -158,7 +391,7 @@ func (r *WorkPlacementReconciler) deleteWorkPlacement(ctx context.Context, write
 	if pendingRepoCleanup {
 		logger.Info("cleaning up work on repository", "workplacement", workPlacement.Name)
 		var workloadsToDelete []string
-		if filePathMode == v1alpha1.FilepathModeNone {
+		if tracksFilesInStateFile(filePathMode) {
 			var kratixFile []byte
 			if kratixFile, err = writer.ReadFile(kratixFilePath); err != nil {
 				logger.Error(err, "failed to read .kratix state file", "file path", kratixFilePath)
@@ -172,6 +405,9 @@ func (r *WorkPlacementReconciler) deleteWorkPlacement(ctx context.Context, write
 			workloadsToDelete = stateFile.Files
 		}
 
+		if v1alpha1.Orphaned(workPlacement) {
+			return r.orphan(ctx, writer, dir, workPlacement, workloadsToDelete, logger)
+		}
 		return r.delete(ctx, writer, dir, workPlacement, workloadsToDelete, repoCleanupWorkPlacementFinalizer, logger)
 	}
 
@@ -195,35 +431,60 @@ func (r *WorkPlacementReconciler) delete(ctx context.Context, writer writers.Sta
 	return fastRequeue, nil
 }
 
-func (r *WorkPlacementReconciler) writeWorkloadsToStateStore(writer writers.StateStoreWriter, workPlacement v1alpha1.WorkPlacement, destination v1alpha1.Destination, logger logr.Logger) (string, error) {
+// Returns the directory the WorkPlacement's workloads are written to, and the
+// workloads as they are written to the State Store
+func (r *WorkPlacementReconciler) renderWorkloads(ctx context.Context, workPlacement v1alpha1.WorkPlacement, destination v1alpha1.Destination) (string, []v1alpha1.Workload, error) {
 	var err error
-	var workloadsToDelete []string
-	var dir = getDir(workPlacement)
-	var workloadsToCreate []v1alpha1.Workload
+	var workloads []v1alpha1.Workload
 
 	//loop through workloads and decompress them so the works written to the State Store are decompressed
 	for _, workload := range workPlacement.Spec.Workloads {
-		decompressedContent, err := compression.DecompressContent([]byte(workload.Content))
+		content, err := payload.Resolve(ctx, r.Client, workPlacement.GetNamespace(), workload)
 		if err != nil {
-			return "", fmt.Errorf("unable to decompress file content: %s", err)
+			return "", nil, err
+		}
+
+		decompressedContent, err := compression.DecompressContent([]byte(content))
+		if err != nil {
+			return "", nil, fmt.Errorf("unable to decompress file content: %s", err)
 		}
 
 		workload.Content = string(decompressedContent)
-		workloadsToCreate = append(workloadsToCreate, workload)
+		workload.ContentRef = nil
+		workloads = append(workloads, workload)
+	}
+
+	if destination.GetFilepathMode() == v1alpha1.FilepathModeTemplate {
+		if workloads, err = r.renderTemplatedWorkloads(ctx, workPlacement, destination, workloads); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if tracksFilesInStateFile(destination.GetFilepathMode()) {
+		return "", workloads, nil
+	}
+	return getDir(workPlacement), workloads, nil
+}
+
+func (r *WorkPlacementReconciler) writeWorkloadsToStateStore(ctx context.Context, writer writers.StateStoreWriter, workPlacement v1alpha1.WorkPlacement, destination v1alpha1.Destination, logger logr.Logger) (string, error) {
+	var workloadsToDelete []string
+
+	dir, workloadsToCreate, err := r.renderWorkloads(ctx, workPlacement, destination)
+	if err != nil {
+		return "", err
 	}
 
-	if destination.GetFilepathMode() == v1alpha1.FilepathModeNone {
-		var kratixFile []byte
-		if kratixFile, err = writer.ReadFile(fmt.Sprintf(".kratix/%s-%s.yaml", workPlacement.Namespace, workPlacement.Name)); ignoreNotFound(err) != nil {
-			return "", fmt.Errorf("failed to read .kratix state file: %s", err)
+	if tracksFilesInStateFile(destination.GetFilepathMode()) {
+		oldStateFile, otherStateFiles, err := r.readStateFiles(ctx, writer, workPlacement, destination)
+		if err != nil {
+			return "", err
 		}
-		oldStateFile := StateFile{}
-		if err = yaml.Unmarshal(kratixFile, &oldStateFile); err != nil {
-			return "", fmt.Errorf("failed to unmarshal .kratix state file: %s", err)
+		if err := checkFilepathCollisions(workloadsToCreate, otherStateFiles); err != nil {
+			return "", err
 		}
 
 		newStateFile := StateFile{
-			Files: workloadsFilenames(workPlacement.Spec.Workloads),
+			Files: workloadsFilenames(workloadsToCreate),
 		}
 		stateFileContent, marshalErr := yaml.Marshal(newStateFile)
 		if marshalErr != nil {
@@ -235,9 +496,8 @@ func (r *WorkPlacementReconciler) writeWorkloadsToStateStore(writer writers.Stat
 			Content:  string(stateFileContent),
 		}
 
-		dir = ""
+		workloadsToDelete = cleanupWorkloads(oldStateFile.Files, workloadsToCreate)
 		workloadsToCreate = append(workloadsToCreate, stateFileWorkload)
-		workloadsToDelete = cleanupWorkloads(oldStateFile.Files, workPlacement.Spec.Workloads)
 	}
 
 	versionID, err := writer.UpdateFiles(
@@ -253,6 +513,82 @@ func (r *WorkPlacementReconciler) writeWorkloadsToStateStore(writer writers.Stat
 	return versionID, nil
 }
 
+// Reads the WorkPlacement's .kratix state file. In template mode, where
+// WorkPlacements can render the same paths, the state files of the other
+// WorkPlacements on the Destination are read with it, keyed by WorkPlacement.
+func (r *WorkPlacementReconciler) readStateFiles(ctx context.Context, writer writers.StateStoreWriter, workPlacement v1alpha1.WorkPlacement, destination v1alpha1.Destination) (StateFile, map[string]StateFile, error) {
+	stateFilePath := fmt.Sprintf(".kratix/%s-%s.yaml", workPlacement.Namespace, workPlacement.Name)
+	stateFile := StateFile{}
+
+	if destination.GetFilepathMode() != v1alpha1.FilepathModeTemplate {
+		kratixFile, err := writer.ReadFile(stateFilePath)
+		if ignoreNotFound(err) != nil {
+			return stateFile, nil, fmt.Errorf("failed to read .kratix state file: %s", err)
+		}
+		if err = yaml.Unmarshal(kratixFile, &stateFile); err != nil {
+			return stateFile, nil, fmt.Errorf("failed to unmarshal .kratix state file: %s", err)
+		}
+		return stateFile, nil, nil
+	}
+
+	workPlacementList := &v1alpha1.WorkPlacementList{}
+	if err := r.Client.List(ctx, workPlacementList, client.MatchingLabels{targetDestinationNameLabel: destination.Name}); err != nil {
+		return stateFile, nil, err
+	}
+	paths := []string{stateFilePath}
+	others := map[string]string{}
+	for _, other := range workPlacementList.Items {
+		if other.Namespace == workPlacement.Namespace && other.Name == workPlacement.Name {
+			continue
+		}
+		path := fmt.Sprintf(".kratix/%s-%s.yaml", other.Namespace, other.Name)
+		paths = append(paths, path)
+		others[path] = other.Namespace + "/" + other.Name
+	}
+
+	files, err := writer.ReadFiles(paths)
+	if err != nil {
+		return stateFile, nil, fmt.Errorf("failed to read .kratix state files: %s", err)
+	}
+	if err = yaml.Unmarshal(files[stateFilePath], &stateFile); err != nil {
+		return stateFile, nil, fmt.Errorf("failed to unmarshal .kratix state file: %s", err)
+	}
+	otherStateFiles := map[string]StateFile{}
+	for path, name := range others {
+		content, found := files[path]
+		if !found {
+			continue
+		}
+		otherStateFile := StateFile{}
+		if err = yaml.Unmarshal(content, &otherStateFile); err != nil {
+			return stateFile, nil, fmt.Errorf("failed to unmarshal .kratix state file %s: %s", path, err)
+		}
+		otherStateFiles[name] = otherStateFile
+	}
+	return stateFile, otherStateFiles, nil
+}
+
+func (r *WorkPlacementReconciler) renderTemplatedWorkloads(ctx context.Context, workPlacement v1alpha1.WorkPlacement, destination v1alpha1.Destination, workloads []v1alpha1.Workload) ([]v1alpha1.Workload, error) {
+	tmpl, err := parseFilepathTemplate(destination)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceLabels, err := getResourceRequestLabels(ctx, r.Client, workPlacement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource request labels for filepath template: %w", err)
+	}
+
+	return renderTemplatedFilepaths(tmpl, workPlacement, resourceLabels, workloads)
+}
+
+// The none and template filepath modes don't derive the location of the files
+// from the WorkPlacement, so the written files are recorded in a .kratix state
+// file to be able to clean them up later
+func tracksFilesInStateFile(filepathMode string) bool {
+	return filepathMode == v1alpha1.FilepathModeNone || filepathMode == v1alpha1.FilepathModeTemplate
+}
+
 func ignoreNotFound(err error) error {
 	if errors.Is(err, writers.FileNotFound) {
 		return nil
@@ -315,6 +651,10 @@ func (r *WorkPlacementReconciler) addFinalizer(ctx context.Context, workPlacemen
 func (r *WorkPlacementReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.WorkPlacement{}).
+		Watches(
+			&v1alpha1.Approval{},
+			handler.EnqueueRequestsFromMapFunc(r.requestReconciliationOfApprovedWorkPlacement),
+		).
 		Complete(r)
 }
 
@@ -323,7 +663,7 @@ func checkWorkPlacementFinalizers(workPlacement *v1alpha1.WorkPlacement, filepat
 	if !controllerutil.ContainsFinalizer(workPlacement, repoCleanupWorkPlacementFinalizer) {
 		missingFinalizers = append(missingFinalizers, repoCleanupWorkPlacementFinalizer)
 	}
-	if filepathMode == v1alpha1.FilepathModeNone && !controllerutil.ContainsFinalizer(workPlacement, kratixFileCleanupWorkPlacementFinalizer) {
+	if tracksFilesInStateFile(filepathMode) && !controllerutil.ContainsFinalizer(workPlacement, kratixFileCleanupWorkPlacementFinalizer) {
 		missingFinalizers = append(missingFinalizers, kratixFileCleanupWorkPlacementFinalizer)
 	}
 	return missingFinalizers
@@ -19,6 +19,8 @@ package controllers_test
 import (
 	"context"
 	"fmt"
+	"math/rand"
+	"time"
 
 	"k8s.io/apimachinery/pkg/types"
 
@@ -29,11 +31,16 @@ import (
 	"github.com/syntasso/kratix/controllers"
 	"github.com/syntasso/kratix/lib/compression"
 	"github.com/syntasso/kratix/lib/hash"
+	"github.com/syntasso/kratix/lib/payload"
 	"github.com/syntasso/kratix/lib/writers"
 	"github.com/syntasso/kratix/lib/writers/writersfakes"
+	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -60,9 +67,8 @@ var _ = Describe("WorkplacementReconciler", func() {
 	BeforeEach(func() {
 		ctx = context.Background()
 		reconciler = &controllers.WorkPlacementReconciler{
-			Client:       fakeK8sClient,
-			Log:          ctrl.Log.WithName("controllers").WithName("Work"),
-			VersionCache: make(map[string]string),
+			Client: fakeK8sClient,
+			Log:    ctrl.Log.WithName("controllers").WithName("Work"),
 		}
 
 		compressedContent, err := compression.CompressContent([]byte("{someApi: foo, someValue: bar}"))
@@ -120,6 +126,17 @@ var _ = Describe("WorkplacementReconciler", func() {
 		}
 	})
 
+	When("the destination has no stateStoreRef", func() {
+		It("requeues without writing the workloads", func() {
+			destination.Spec.StateStoreRef = nil
+			Expect(fakeK8sClient.Create(ctx, &destination)).To(Succeed())
+
+			result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&workPlacement)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{RequeueAfter: 15 * time.Second}))
+		})
+	})
+
 	When("the destination statestore is s3", func() {
 		When("the destination has filepath mode of none", func() {
 			BeforeEach(func() {
@@ -243,6 +260,40 @@ files:
 					Expect(workloadsToDelete).To(ConsistOf(kratixStateFile))
 					Expect(dir).To(Equal(""))
 				})
+
+				It("leaves the workloads and records them when orphaned", func() {
+					fakeWriter.ReadFileReturns([]byte(`
+files:
+  - fruit.yaml`), nil)
+					Expect(fakeK8sClient.Get(ctx, client.ObjectKeyFromObject(&workPlacement), &workPlacement)).To(Succeed())
+					workPlacement.SetAnnotations(map[string]string{v1alpha1.DeletionPolicyAnnotation: "Orphan"})
+					Expect(fakeK8sClient.Update(ctx, &workPlacement)).To(Succeed())
+					Expect(fakeK8sClient.Delete(ctx, &workPlacement)).To(Succeed())
+					result, err := t.reconcileUntilCompletion(reconciler, &workPlacement)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(Equal(ctrl.Result{}))
+
+					kratixStateFile := fmt.Sprintf(".kratix/%s-%s.yaml", workPlacement.Namespace, workPlacement.Name)
+					Expect(fakeWriter.UpdateFilesCallCount()).To(Equal(4))
+
+					dir, _, workloadsToCreate, workloadsToDelete := fakeWriter.UpdateFilesArgsForCall(2)
+					Expect(dir).To(Equal(""))
+					Expect(workloadsToDelete).To(BeEmpty())
+					Expect(workloadsToCreate).To(HaveLen(1))
+					Expect(workloadsToCreate[0].Filepath).To(Equal(".kratix/orphaned/default-test-workplacement.yaml"))
+					record := controllers.OrphanRecord{}
+					Expect(yaml.Unmarshal([]byte(workloadsToCreate[0].Content), &record)).To(Succeed())
+					Expect(record.Promise).To(Equal("test-promise"))
+					Expect(record.Resource).To(Equal("test-resource"))
+					Expect(record.Destination).To(Equal("test-destination"))
+					Expect(record.Files).To(ConsistOf("fruit.yaml"))
+					Expect(record.DeleteWorkflowsSkipped).To(BeTrue())
+					Expect(record.OrphanedAt).NotTo(BeEmpty())
+
+					_, _, workloadsToCreate, workloadsToDelete = fakeWriter.UpdateFilesArgsForCall(3)
+					Expect(workloadsToCreate).To(BeNil())
+					Expect(workloadsToDelete).To(ConsistOf(kratixStateFile))
+				})
 			})
 
 			When("statestore and workplacement.spec.workloads has diverged", func() {
@@ -332,6 +383,218 @@ files:
 				})
 			})
 		})
+
+		When("the destination has filepath mode of template", func() {
+			BeforeEach(func() {
+				setupGitDestination(&gitStateStore, &destination)
+				destination.Spec.Filepath = v1alpha1.Filepath{
+					Mode:     v1alpha1.FilepathModeTemplate,
+					Template: "{{ .ResourceRequest.Labels.team }}/{{ .WorkPlacement.ResourceName }}/{{ .Workload.Filepath }}",
+				}
+				Expect(fakeK8sClient.Update(ctx, &destination)).To(Succeed())
+
+				promise := promiseFromFile(promisePath)
+				promise.SetName("test-promise")
+				Expect(fakeK8sClient.Create(ctx, promise)).To(Succeed())
+
+				rr := &unstructured.Unstructured{}
+				rr.SetAPIVersion("marketplace.kratix.io/v1alpha1")
+				rr.SetKind("redis")
+				rr.SetName("test-resource")
+				rr.SetNamespace("default")
+				rr.SetLabels(map[string]string{"team": "platform"})
+				Expect(fakeK8sClient.Create(ctx, rr)).To(Succeed())
+
+				controllers.SetNewGitWriter(func(logger logr.Logger, stateStoreSpec v1alpha1.GitStateStoreSpec, destination v1alpha1.Destination,
+					creds map[string][]byte) (writers.StateStoreWriter, error) {
+					return fakeWriter, nil
+				})
+			})
+
+			It("writes the files to the path rendered from the template and records them in the state file", func() {
+				result, err := t.reconcileUntilCompletion(reconciler, &workPlacement)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+
+				dir, workPlacementName, workloadsToCreate, workloadsToDelete := fakeWriter.UpdateFilesArgsForCall(0)
+				Expect(dir).To(Equal(""))
+				Expect(workPlacementName).To(Equal(workPlacement.Name))
+				Expect(workloadsToCreate).To(ConsistOf(
+					v1alpha1.Workload{
+						Filepath: "platform/test-resource/fruit.yaml",
+						Content:  "{someApi: foo, someValue: bar}",
+					},
+					v1alpha1.Workload{
+						Filepath: fmt.Sprintf(".kratix/%s-%s.yaml", workPlacement.Namespace, workPlacement.Name),
+						Content: `files:
+- platform/test-resource/fruit.yaml
+`,
+					},
+				))
+				Expect(workloadsToDelete).To(BeEmpty())
+
+				workplacement := &v1alpha1.WorkPlacement{}
+				Expect(fakeK8sClient.Get(ctx, types.NamespacedName{Name: workplacementName, Namespace: "default"}, workplacement)).
+					To(Succeed())
+				Expect(workplacement.GetFinalizers()).To(ConsistOf(
+					"finalizers.workplacement.kratix.io/repo-cleanup",
+					"finalizers.workplacement.kratix.io/kratix-dot-files-cleanup",
+				))
+			})
+
+			It("removes files that were previously rendered to a different path", func() {
+				fakeWriter.ReadFilesReturns(map[string][]byte{
+					".kratix/default-test-workplacement.yaml": []byte(`
+files:
+  - old-team/test-resource/fruit.yaml`),
+				}, nil)
+
+				_, err := t.reconcileUntilCompletion(reconciler, &workPlacement)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, _, workloadsToDelete := fakeWriter.UpdateFilesArgsForCall(0)
+				Expect(workloadsToDelete).To(ConsistOf("old-team/test-resource/fruit.yaml"))
+			})
+
+			When("the template renders the same path for different workloads", func() {
+				BeforeEach(func() {
+					destination.Spec.Filepath.Template = "{{ .ResourceRequest.Labels.team }}/static.yaml"
+					Expect(fakeK8sClient.Update(ctx, &destination)).To(Succeed())
+
+					workPlacement.Spec.Workloads = append(workPlacement.Spec.Workloads, v1alpha1.Workload{
+						Filepath: "other-fruit.yaml",
+						Content:  workloads[0].Content,
+					})
+					Expect(fakeK8sClient.Update(ctx, &workPlacement)).To(Succeed())
+				})
+
+				It("does not write the workloads", func() {
+					_, err := t.reconcileUntilCompletion(reconciler, &workPlacement)
+					Expect(err).To(MatchError(ContainSubstring(`filepath template renders "platform/static.yaml" for both fruit.yaml and other-fruit.yaml`)))
+					Expect(fakeWriter.UpdateFilesCallCount()).To(BeZero())
+				})
+			})
+
+			When("the template renders a path already written for another workplacement", func() {
+				BeforeEach(func() {
+					Expect(fakeK8sClient.Create(ctx, &v1alpha1.WorkPlacement{
+						ObjectMeta: v1.ObjectMeta{
+							Name:      "other-workplacement",
+							Namespace: "default",
+							Labels:    map[string]string{v1alpha1.KratixPrefix + "targetDestinationName": destination.Name},
+						},
+						Spec: v1alpha1.WorkPlacementSpec{TargetDestinationName: destination.Name},
+					})).To(Succeed())
+					fakeWriter.ReadFilesReturns(map[string][]byte{
+						".kratix/default-other-workplacement.yaml": []byte(`
+files:
+  - platform/test-resource/fruit.yaml`),
+					}, nil)
+				})
+
+				It("does not write the workloads", func() {
+					_, err := t.reconcileUntilCompletion(reconciler, &workPlacement)
+					Expect(err).To(MatchError(ContainSubstring(`filepath template renders "platform/test-resource/fruit.yaml", which is already written for workplacement default/other-workplacement`)))
+					Expect(fakeWriter.UpdateFilesCallCount()).To(BeZero())
+
+					By("reading the state files of the workplacements in one go")
+					Expect(fakeWriter.ReadFilesArgsForCall(0)).To(ConsistOf(
+						".kratix/default-test-workplacement.yaml",
+						".kratix/default-other-workplacement.yaml",
+					))
+				})
+			})
+
+			When("the workplacement is for Promise dependencies", func() {
+				BeforeEach(func() {
+					workPlacement.Spec.ResourceName = ""
+					Expect(fakeK8sClient.Update(ctx, &workPlacement)).To(Succeed())
+				})
+
+				It("explains that the template must guard .ResourceRequest", func() {
+					_, err := t.reconcileUntilCompletion(reconciler, &workPlacement)
+					Expect(err).To(MatchError(ContainSubstring("Promise dependency, which has no .ResourceRequest; guard it with {{ with .ResourceRequest }}")))
+					Expect(fakeWriter.UpdateFilesCallCount()).To(BeZero())
+				})
+
+				It("writes the workloads when the template guards .ResourceRequest", func() {
+					destination.Spec.Filepath.Template = "{{ with .ResourceRequest }}{{ .Labels.team }}/{{ end }}{{ .WorkPlacement.PromiseName }}/{{ .Workload.Filepath }}"
+					Expect(fakeK8sClient.Update(ctx, &destination)).To(Succeed())
+
+					_, err := t.reconcileUntilCompletion(reconciler, &workPlacement)
+					Expect(err).NotTo(HaveOccurred())
+					_, _, workloadsToCreate, _ := fakeWriter.UpdateFilesArgsForCall(0)
+					Expect(workloadsToCreate).To(ContainElement(v1alpha1.Workload{
+						Filepath: "test-promise/fruit.yaml",
+						Content:  "{someApi: foo, someValue: bar}",
+					}))
+				})
+			})
+
+			When("the template renders a path outside of the destination", func() {
+				BeforeEach(func() {
+					destination.Spec.Filepath.Template = "../{{ .Workload.Filepath }}"
+					Expect(fakeK8sClient.Update(ctx, &destination)).To(Succeed())
+				})
+
+				It("does not write the workloads", func() {
+					_, err := t.reconcileUntilCompletion(reconciler, &workPlacement)
+					Expect(err).To(MatchError(ContainSubstring("must be within the destination")))
+					Expect(fakeWriter.UpdateFilesCallCount()).To(BeZero())
+				})
+			})
+
+			When("the template references a label the resource request does not have", func() {
+				BeforeEach(func() {
+					destination.Spec.Filepath.Template = "{{ .ResourceRequest.Labels.env }}/{{ .Workload.Filepath }}"
+					Expect(fakeK8sClient.Update(ctx, &destination)).To(Succeed())
+				})
+
+				It("does not write the workloads", func() {
+					_, err := t.reconcileUntilCompletion(reconciler, &workPlacement)
+					Expect(err).To(MatchError(ContainSubstring("failed to render filepath template")))
+					Expect(fakeWriter.UpdateFilesCallCount()).To(BeZero())
+				})
+			})
+		})
+	})
+
+	Describe("Workloads too large to be held in the Work", func() {
+		BeforeEach(func() {
+			setupGitDestination(&gitStateStore, &destination)
+			controllers.SetNewGitWriter(func(logger logr.Logger, stateStoreSpec v1alpha1.GitStateStoreSpec, destination v1alpha1.Destination,
+				creds map[string][]byte) (writers.StateStoreWriter, error) {
+				return fakeWriter, nil
+			})
+		})
+
+		It("writes the content read from the referenced ConfigMaps", func() {
+			largeContent := make([]byte, 2*payload.MaxInlineContentSize)
+			random := rand.New(rand.NewSource(1))
+			for i := range largeContent {
+				largeContent[i] = byte('a' + random.Intn(26))
+			}
+			compressedContent, err := compression.CompressContent(largeContent)
+			Expect(err).NotTo(HaveOccurred())
+
+			workloadGroups := []v1alpha1.WorkloadGroup{{Workloads: []v1alpha1.Workload{
+				{Filepath: "large.yaml", Content: string(compressedContent)},
+			}}}
+			Expect(payload.Offload(ctx, fakeK8sClient, "default", workloadGroups)).To(Succeed())
+			Expect(workloadGroups[0].Workloads[0].ContentRef).NotTo(BeNil())
+
+			Expect(fakeK8sClient.Get(ctx, client.ObjectKeyFromObject(&workPlacement), &workPlacement)).To(Succeed())
+			workPlacement.Spec.Workloads = workloadGroups[0].Workloads
+			Expect(fakeK8sClient.Update(ctx, &workPlacement)).To(Succeed())
+
+			_, err = t.reconcileUntilCompletion(reconciler, &workPlacement)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, workloadsToCreate, _ := fakeWriter.UpdateFilesArgsForCall(0)
+			Expect(workloadsToCreate).To(Equal([]v1alpha1.Workload{
+				{Filepath: "large.yaml", Content: string(largeContent)},
+			}))
+		})
 	})
 
 	Describe("WorkPlacement Status", func() {
@@ -380,6 +643,277 @@ files:
 			Expect(updatedWorkplacement.Status.VersionID).To(Equal("an-amazing-version-id"))
 		})
 
+		It("records whether the workloads were written", func() {
+			result, err := t.reconcileUntilCompletion(reconciler, &workPlacement)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+
+			updatedWorkplacement := v1alpha1.WorkPlacement{}
+			Expect(fakeK8sClient.Get(ctx, client.ObjectKeyFromObject(&workPlacement), &updatedWorkplacement)).To(Succeed())
+			condition := meta.FindStatusCondition(updatedWorkplacement.Status.Conditions, "WriteSucceeded")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(v1.ConditionTrue))
+
+			fakeWriter.UpdateFilesReturns("", fmt.Errorf("push failed"))
+			_, err = t.reconcileUntilCompletion(reconciler, &workPlacement)
+			Expect(err).To(HaveOccurred())
+
+			Expect(fakeK8sClient.Get(ctx, client.ObjectKeyFromObject(&workPlacement), &updatedWorkplacement)).To(Succeed())
+			condition = meta.FindStatusCondition(updatedWorkplacement.Status.Conditions, "WriteSucceeded")
+			Expect(condition.Status).To(Equal(v1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring("push failed"))
+		})
+
+		When("the destination has drift detection enabled", func() {
+			var driftedCondition func() *v1.Condition
+
+			BeforeEach(func() {
+				destination.Spec.DriftDetection = &v1alpha1.DriftDetection{}
+				Expect(fakeK8sClient.Update(ctx, &destination)).To(Succeed())
+
+				driftedCondition = func() *v1.Condition {
+					updatedWorkplacement := v1alpha1.WorkPlacement{}
+					Expect(fakeK8sClient.Get(ctx, client.ObjectKeyFromObject(&workPlacement), &updatedWorkplacement)).To(Succeed())
+					return meta.FindStatusCondition(updatedWorkplacement.Status.Conditions, "Drifted")
+				}
+
+				result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&workPlacement)})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(fakeWriter.UpdateFilesCallCount()).To(Equal(1))
+				Expect(driftedCondition().Status).To(Equal(v1.ConditionFalse))
+			})
+
+			It("periodically checks the written files against the workloads", func() {
+				content := "{someApi: foo, someValue: bar}"
+				fakeWriter.ReadFilesStub = func(paths []string) (map[string][]byte, error) {
+					files := map[string][]byte{}
+					for _, path := range paths {
+						files[path] = []byte(content)
+					}
+					return files, nil
+				}
+				result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&workPlacement)})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{RequeueAfter: 10 * time.Minute}))
+				Expect(fakeWriter.ReadFilesArgsForCall(0)).To(ConsistOf(HaveSuffix("/fruit.yaml")))
+				Expect(driftedCondition().Status).To(Equal(v1.ConditionFalse))
+
+				content = "{someApi: foo, someValue: edited}"
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&workPlacement)})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("reading the files in one go per check")
+				Expect(fakeWriter.ReadFilesCallCount()).To(Equal(2))
+				Expect(fakeWriter.ReadFileCallCount()).To(BeZero())
+
+				condition := driftedCondition()
+				Expect(condition.Status).To(Equal(v1.ConditionTrue))
+				Expect(condition.Reason).To(Equal("FilesDrifted"))
+				Expect(condition.Message).To(ContainSubstring("fruit.yaml"))
+
+				By("not writing the expected content again")
+				Expect(fakeWriter.UpdateFilesCallCount()).To(Equal(1))
+			})
+
+			It("writes drifted files again when remediation is enabled", func() {
+				destination.Spec.DriftDetection.Remediate = true
+				Expect(fakeK8sClient.Update(ctx, &destination)).To(Succeed())
+
+				fakeWriter.ReadFilesReturns(map[string][]byte{}, nil)
+				result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&workPlacement)})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{RequeueAfter: 10 * time.Minute}))
+
+				Expect(fakeWriter.UpdateFilesCallCount()).To(Equal(2))
+				condition := driftedCondition()
+				Expect(condition.Status).To(Equal(v1.ConditionFalse))
+				Expect(condition.Reason).To(Equal("DriftRemediated"))
+				Expect(condition.Message).To(ContainSubstring("fruit.yaml (deleted)"))
+			})
+
+			It("writes the workloads again when the Destination changes where they are written", func() {
+				fakeWriter.ReadFilesReturns(map[string][]byte{}, nil)
+				destination.Spec.Path = "new-path"
+				Expect(fakeK8sClient.Update(ctx, &destination)).To(Succeed())
+
+				result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&workPlacement)})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{RequeueAfter: 10 * time.Minute}))
+
+				Expect(fakeWriter.ReadFilesCallCount()).To(BeZero())
+				Expect(fakeWriter.UpdateFilesCallCount()).To(Equal(2))
+				Expect(driftedCondition().Status).To(Equal(v1.ConditionFalse))
+
+				By("checking for drift once the workloads are written to the new path")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&workPlacement)})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeWriter.ReadFilesCallCount()).To(Equal(1))
+				Expect(fakeWriter.UpdateFilesCallCount()).To(Equal(2))
+			})
+		})
+
+		When("the destination requires approval", func() {
+			var getWorkPlacement func() v1alpha1.WorkPlacement
+
+			BeforeEach(func() {
+				destination.Spec.ApprovalRequired = true
+				Expect(fakeK8sClient.Update(ctx, &destination)).To(Succeed())
+				fakeWriter.ReadFilesReturns(map[string][]byte{}, nil)
+
+				getWorkPlacement = func() v1alpha1.WorkPlacement {
+					updatedWorkplacement := v1alpha1.WorkPlacement{}
+					Expect(fakeK8sClient.Get(ctx, client.ObjectKeyFromObject(&workPlacement), &updatedWorkplacement)).To(Succeed())
+					return updatedWorkplacement
+				}
+
+				result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&workPlacement)})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+			})
+
+			It("waits for an Approval before writing, showing the changes", func() {
+				Expect(fakeWriter.UpdateFilesCallCount()).To(BeZero())
+
+				pending := getWorkPlacement()
+				revision, err := v1alpha1.WorkloadsRevision(pending.Spec.Workloads)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pending.Status.Approval.Revision).To(Equal(revision))
+				Expect(pending.Status.Approval.Changes).To(ContainElement(And(HavePrefix("create "), HaveSuffix("fruit.yaml"))))
+				Expect(pending.Status.Approval.ApprovedBy).To(BeEmpty())
+				condition := meta.FindStatusCondition(pending.Status.Conditions, "Approved")
+				Expect(condition.Status).To(Equal(v1.ConditionFalse))
+				Expect(condition.Reason).To(Equal("PendingApproval"))
+
+				Expect(fakeK8sClient.Create(ctx, &v1alpha1.Approval{
+					ObjectMeta: v1.ObjectMeta{Name: "approve-fruit", Namespace: "default"},
+					Spec: v1alpha1.ApprovalSpec{
+						WorkPlacement: workPlacement.GetName(),
+						Revision:      revision,
+						ApprovedBy:    "approver@example.com",
+					},
+				})).To(Succeed())
+
+				result, err := t.reconcileUntilCompletion(reconciler, &workPlacement)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(fakeWriter.UpdateFilesCallCount()).NotTo(BeZero())
+
+				approved := getWorkPlacement()
+				Expect(approved.Status.Approval.ApprovedBy).To(Equal("approver@example.com"))
+				Expect(meta.IsStatusConditionTrue(approved.Status.Conditions, "Approved")).To(BeTrue())
+			})
+
+			It("writes once approved with the approve annotation", func() {
+				pending := getWorkPlacement()
+				pending.SetAnnotations(map[string]string{
+					v1alpha1.ApprovedRevisionAnnotation: pending.Status.Approval.Revision,
+					v1alpha1.ApprovedByAnnotation:       "approver@example.com",
+				})
+				Expect(fakeK8sClient.Update(ctx, &pending)).To(Succeed())
+
+				_, err := t.reconcileUntilCompletion(reconciler, &workPlacement)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeWriter.UpdateFilesCallCount()).NotTo(BeZero())
+				Expect(getWorkPlacement().Status.Approval.ApprovedBy).To(Equal("approver@example.com"))
+			})
+
+			It("does not write when the approval has no approver", func() {
+				pending := getWorkPlacement()
+				pending.SetAnnotations(map[string]string{
+					v1alpha1.ApprovedRevisionAnnotation: pending.Status.Approval.Revision,
+				})
+				Expect(fakeK8sClient.Update(ctx, &pending)).To(Succeed())
+				Expect(fakeK8sClient.Create(ctx, &v1alpha1.Approval{
+					ObjectMeta: v1.ObjectMeta{Name: "approve-fruit", Namespace: "default"},
+					Spec: v1alpha1.ApprovalSpec{
+						WorkPlacement: workPlacement.GetName(),
+						Revision:      pending.Status.Approval.Revision,
+					},
+				})).To(Succeed())
+
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&workPlacement)})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeWriter.UpdateFilesCallCount()).To(BeZero())
+				Expect(getWorkPlacement().Status.Approval.ApprovedBy).To(BeEmpty())
+			})
+		})
+
+		When("writes are suspended", func() {
+			var getWorkPlacement func() v1alpha1.WorkPlacement
+
+			BeforeEach(func() {
+				getWorkPlacement = func() v1alpha1.WorkPlacement {
+					updatedWorkplacement := v1alpha1.WorkPlacement{}
+					Expect(fakeK8sClient.Get(ctx, client.ObjectKeyFromObject(&workPlacement), &updatedWorkplacement)).To(Succeed())
+					return updatedWorkplacement
+				}
+			})
+
+			It("queues the changes until the Destination resumes writes", func() {
+				destination.SetAnnotations(map[string]string{v1alpha1.SuspendWritesAnnotation: "true"})
+				Expect(fakeK8sClient.Update(ctx, &destination)).To(Succeed())
+
+				result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&workPlacement)})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).NotTo(BeZero())
+				Expect(fakeWriter.UpdateFilesCallCount()).To(BeZero())
+
+				suspended := getWorkPlacement()
+				Expect(suspended.Status.PendingChangesSince).NotTo(BeNil())
+				condition := meta.FindStatusCondition(suspended.Status.Conditions, "WritesSuspended")
+				Expect(condition.Status).To(Equal(v1.ConditionTrue))
+				Expect(condition.Reason).To(Equal("PendingChanges"))
+				Expect(condition.Message).To(ContainSubstring("Destination test-destination; pending changes since"))
+
+				destination.SetAnnotations(nil)
+				Expect(fakeK8sClient.Update(ctx, &destination)).To(Succeed())
+
+				result, err = t.reconcileUntilCompletion(reconciler, &workPlacement)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(ctrl.Result{}))
+				Expect(fakeWriter.UpdateFilesCallCount()).NotTo(BeZero())
+
+				resumed := getWorkPlacement()
+				Expect(resumed.Status.PendingChangesSince).To(BeNil())
+				Expect(meta.FindStatusCondition(resumed.Status.Conditions, "WritesSuspended")).To(BeNil())
+				Expect(meta.IsStatusConditionTrue(resumed.Status.Conditions, "WriteSucceeded")).To(BeTrue())
+			})
+
+			It("honours the suspension on the Work and the Promise", func() {
+				work := &v1alpha1.Work{
+					ObjectMeta: v1.ObjectMeta{
+						Name:        "test-work",
+						Namespace:   "default",
+						Annotations: map[string]string{v1alpha1.SuspendWritesAnnotation: "true"},
+					},
+				}
+				Expect(fakeK8sClient.Create(ctx, work)).To(Succeed())
+				workPlacement.SetLabels(map[string]string{"kratix.io/work": work.GetName()})
+				Expect(fakeK8sClient.Update(ctx, &workPlacement)).To(Succeed())
+
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&workPlacement)})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeWriter.UpdateFilesCallCount()).To(BeZero())
+				condition := meta.FindStatusCondition(getWorkPlacement().Status.Conditions, "WritesSuspended")
+				Expect(condition.Message).To(ContainSubstring("Work test-work"))
+
+				promise := &v1alpha1.Promise{
+					ObjectMeta: v1.ObjectMeta{
+						Name:        "test-promise",
+						Annotations: map[string]string{v1alpha1.SuspendWritesAnnotation: "true"},
+					},
+				}
+				Expect(fakeK8sClient.Create(ctx, promise)).To(Succeed())
+
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&workPlacement)})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeWriter.UpdateFilesCallCount()).To(BeZero())
+				condition = meta.FindStatusCondition(getWorkPlacement().Status.Conditions, "WritesSuspended")
+				Expect(condition.Message).To(ContainSubstring("Promise test-promise"))
+			})
+		})
+
 		When("updating the status fails", func() {
 			It("applies the Version ID on the next reconcile", func() {
 				subResourceUpdateError = fmt.Errorf("an-error")
@@ -406,6 +940,29 @@ files:
 
 				Expect(latestWP.Status.VersionID).To(Equal("an-amazing-version-id"))
 			})
+
+			It("applies the Version ID after a restart", func() {
+				subResourceUpdateError = fmt.Errorf("an-error")
+				fakeWriter.UpdateFilesReturnsOnCall(0, "an-amazing-version-id", nil)
+
+				_, err := t.reconcileUntilCompletion(reconciler, &workPlacement)
+				Expect(err).To(HaveOccurred())
+				subResourceUpdateError = nil
+
+				latestWP := v1alpha1.WorkPlacement{}
+				Expect(fakeK8sClient.Get(ctx, client.ObjectKeyFromObject(&workPlacement), &latestWP)).To(Succeed())
+				Expect(latestWP.GetAnnotations()).To(HaveKeyWithValue("kratix.io/version-id", "an-amazing-version-id"))
+
+				restartedReconciler := &controllers.WorkPlacementReconciler{
+					Client: fakeK8sClient,
+					Log:    ctrl.Log.WithName("controllers").WithName("Work"),
+				}
+				_, err = t.reconcileUntilCompletion(restartedReconciler, &workPlacement)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeK8sClient.Get(ctx, client.ObjectKeyFromObject(&workPlacement), &latestWP)).To(Succeed())
+				Expect(latestWP.Status.VersionID).To(Equal("an-amazing-version-id"))
+			})
 		})
 	})
 })
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"github.com/syntasso/kratix/lib/writers"
+	"gopkg.in/yaml.v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// OrphanRecord is written to the State Store, at orphanRecordPath, in place of
+// deleting the workloads of a WorkPlacement deleted with the Orphan deletion
+// policy
+type OrphanRecord struct {
+	WorkPlacement string `yaml:"workPlacement"`
+	Namespace     string `yaml:"namespace"`
+	Promise       string `yaml:"promise"`
+	Resource      string `yaml:"resource,omitempty"`
+	Destination   string `yaml:"destination"`
+	// The directory the workloads were written to, when the Destination
+	// writes each WorkPlacement to its own directory
+	Directory string `yaml:"directory,omitempty"`
+	// The files written, when the Destination tracks them in the .kratix
+	// state file
+	Files     []string `yaml:"files,omitempty"`
+	VersionID string   `yaml:"versionID,omitempty"`
+	// The delete workflows of the resource are not run when it is orphaned
+	DeleteWorkflowsSkipped bool   `yaml:"deleteWorkflowsSkipped,omitempty"`
+	OrphanedAt             string `yaml:"orphanedAt"`
+}
+
+func orphanRecordPath(workPlacement v1alpha1.WorkPlacement) string {
+	return fmt.Sprintf(".kratix/orphaned/%s-%s.yaml", workPlacement.Namespace, workPlacement.Name)
+}
+
+// Leaves the workloads of the WorkPlacement in the State Store, writing a
+// record of what was orphaned, and removes the repo cleanup finalizer
+func (r *WorkPlacementReconciler) orphan(ctx context.Context, writer writers.StateStoreWriter, dir string, workPlacement *v1alpha1.WorkPlacement, files []string, logger logr.Logger) (ctrl.Result, error) {
+	if dir != "" {
+		dir = filepath.Clean(dir)
+	}
+	record := OrphanRecord{
+		WorkPlacement: workPlacement.GetName(),
+		Namespace:     workPlacement.GetNamespace(),
+		Promise:       workPlacement.Spec.PromiseName,
+		Resource:      workPlacement.Spec.ResourceName,
+		Destination:   workPlacement.Spec.TargetDestinationName,
+		Directory:     dir,
+		Files:         files,
+		VersionID:     workPlacement.Status.VersionID,
+		// only resource requests are orphaned, and always without their
+		// delete workflows
+		DeleteWorkflowsSkipped: workPlacement.Spec.ResourceName != "",
+		OrphanedAt:             time.Now().UTC().Format(time.RFC3339),
+	}
+	content, err := yaml.Marshal(record)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to marshal orphan record: %w", err)
+	}
+
+	path := orphanRecordPath(*workPlacement)
+	if _, err := writer.UpdateFiles("", workPlacement.Name, []v1alpha1.Workload{{Filepath: path, Content: string(content)}}, nil); err != nil {
+		logger.Error(err, "error writing orphan record to repository, will try again in 5 seconds")
+		return ctrl.Result{}, err
+	}
+	logger.Info("orphaned work on repository", "workplacement", workPlacement.Name, "record", path)
+
+	controllerutil.RemoveFinalizer(workPlacement, repoCleanupWorkPlacementFinalizer)
+	if err := r.Client.Update(ctx, workPlacement); err != nil {
+		return ctrl.Result{}, err
+	}
+	return fastRequeue, nil
+}
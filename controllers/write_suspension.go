@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const writesSuspendedConditionType = "WritesSuspended"
+
+// Returns what suspends writes for the WorkPlacement: its Destination, its
+// Promise, its resource request or its Work, in that order. Returns an empty
+// string if writes are not suspended.
+func (r *WorkPlacementReconciler) writesSuspendedBy(ctx context.Context, workPlacement *v1alpha1.WorkPlacement, destination *v1alpha1.Destination) (string, error) {
+	if v1alpha1.WritesSuspended(destination) {
+		return fmt.Sprintf("Destination %s", destination.GetName()), nil
+	}
+
+	promise := &v1alpha1.Promise{}
+	err := r.Client.Get(ctx, client.ObjectKey{Name: workPlacement.Spec.PromiseName}, promise)
+	if client.IgnoreNotFound(err) != nil {
+		return "", err
+	}
+	if err == nil && v1alpha1.WritesSuspended(promise) {
+		return fmt.Sprintf("Promise %s", promise.GetName()), nil
+	}
+
+	if err == nil && workPlacement.Spec.ResourceName != "" && promise.ContainsAPI() {
+		gvk, _, err := promise.GetAPI()
+		if err != nil {
+			return "", err
+		}
+		resourceRequest := &unstructured.Unstructured{}
+		resourceRequest.SetGroupVersionKind(*gvk)
+		err = r.Client.Get(ctx, client.ObjectKey{Namespace: workPlacement.GetNamespace(), Name: workPlacement.Spec.ResourceName}, resourceRequest)
+		if client.IgnoreNotFound(err) != nil && !meta.IsNoMatchError(err) {
+			return "", err
+		}
+		if err == nil && v1alpha1.WritesSuspended(resourceRequest) {
+			return fmt.Sprintf("resource request %s/%s", resourceRequest.GetNamespace(), resourceRequest.GetName()), nil
+		}
+	}
+
+	if workName := workPlacement.GetLabels()[workLabelKey]; workName != "" {
+		work := &v1alpha1.Work{}
+		err := r.Client.Get(ctx, client.ObjectKey{Namespace: workPlacement.GetNamespace(), Name: workName}, work)
+		if client.IgnoreNotFound(err) != nil {
+			return "", err
+		}
+		if err == nil && v1alpha1.WritesSuspended(work) {
+			return fmt.Sprintf("Work %s", work.GetName()), nil
+		}
+	}
+	return "", nil
+}
+
+// Sets the WritesSuspended condition, and when the WorkPlacement has changes
+// that are not written yet, the time they have been pending since. Removes
+// both when writes are not suspended. Returns whether the status changed.
+func setWritesSuspendedCondition(workPlacement *v1alpha1.WorkPlacement, suspendedBy string) bool {
+	if suspendedBy == "" {
+		changed := workPlacement.Status.PendingChangesSince != nil
+		workPlacement.Status.PendingChangesSince = nil
+		return meta.RemoveStatusCondition(&workPlacement.Status.Conditions, writesSuspendedConditionType) || changed
+	}
+
+	changed := false
+	condition := metav1.Condition{
+		Type:               writesSuspendedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Suspended",
+		Message:            fmt.Sprintf("Writes suspended by %s; no pending changes", suspendedBy),
+		ObservedGeneration: workPlacement.GetGeneration(),
+	}
+	if !writeSucceeded(*workPlacement) || !workPlacement.DeletionTimestamp.IsZero() {
+		if workPlacement.Status.PendingChangesSince == nil {
+			workPlacement.Status.PendingChangesSince = &metav1.Time{Time: time.Now()}
+			changed = true
+		}
+		condition.Reason = "PendingChanges"
+		condition.Message = fmt.Sprintf("Writes suspended by %s; pending changes since %s",
+			suspendedBy, workPlacement.Status.PendingChangesSince.UTC().Format(time.RFC3339))
+	}
+	return meta.SetStatusCondition(&workPlacement.Status.Conditions, condition) || changed
+}
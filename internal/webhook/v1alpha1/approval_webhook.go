@@ -0,0 +1,77 @@
+/*
+Copyright 2021 Syntasso.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+var approvallog = logf.Log.WithName("approval-webhook")
+
+func SetupApprovalWebhookWithManager(mgr ctrl.Manager, c client.Client) error {
+	k8sClient = c
+	return ctrl.NewWebhookManagedBy(mgr).For(&v1alpha1.Approval{}).
+		WithDefaulter(&ApprovalCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-platform-kratix-io-v1alpha1-approval,mutating=true,failurePolicy=fail,sideEffects=None,groups=platform.kratix.io,resources=approvals,verbs=create;update,versions=v1alpha1,name=mapproval.kb.io,admissionReviewVersions=v1
+
+type ApprovalCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &ApprovalCustomDefaulter{}
+
+// Records who approved the workloads, and defaults the revision to the
+// current revision of the WorkPlacement's workloads
+func (d ApprovalCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	approval, ok := obj.(*v1alpha1.Approval)
+	if !ok {
+		return fmt.Errorf("expected an Approval object but got %T", obj)
+	}
+
+	username, err := requestUsername(ctx)
+	if err != nil {
+		return err
+	}
+	approval.Spec.ApprovedBy = username
+
+	if approval.Spec.Revision == "" {
+		workPlacement := &v1alpha1.WorkPlacement{}
+		key := client.ObjectKey{Namespace: approval.GetNamespace(), Name: approval.Spec.WorkPlacement}
+		if err := k8sClient.Get(ctx, key, workPlacement); err != nil {
+			if errors.IsNotFound(err) {
+				return fmt.Errorf("workplacement %s not found", approval.Spec.WorkPlacement)
+			}
+			return err
+		}
+		if approval.Spec.Revision, err = v1alpha1.WorkloadsRevision(workPlacement.Spec.Workloads); err != nil {
+			return err
+		}
+	}
+
+	approvallog.Info("approving workloads", "name", approval.Name, "workPlacement", approval.Spec.WorkPlacement, "revision", approval.Spec.Revision, "approvedBy", username)
+	return nil
+}
@@ -0,0 +1,81 @@
+package v1alpha1_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	kratixWebhook "github.com/syntasso/kratix/internal/webhook/v1alpha1"
+)
+
+var _ = Describe("ApprovalWebhook", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		approval   *v1alpha1.Approval
+		defaulter  *kratixWebhook.ApprovalCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		Expect(v1alpha1.AddToScheme(scheme.Scheme)).To(Succeed())
+		fakeClient = clientfake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		kratixWebhook.SetClient(fakeClient)
+
+		ctx = admission.NewContextWithRequest(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{Username: "approver@example.com"},
+			},
+		})
+		defaulter = &kratixWebhook.ApprovalCustomDefaulter{}
+		approval = &v1alpha1.Approval{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "approve-redis",
+				Namespace: "default",
+			},
+			Spec: v1alpha1.ApprovalSpec{
+				WorkPlacement: "redis-production",
+				ApprovedBy:    "someone-else",
+			},
+		}
+	})
+
+	It("records the approver and defaults the revision to the current one", func() {
+		workPlacement := &v1alpha1.WorkPlacement{
+			ObjectMeta: metav1.ObjectMeta{Name: "redis-production", Namespace: "default"},
+			Spec: v1alpha1.WorkPlacementSpec{
+				Workloads: []v1alpha1.Workload{{Filepath: "redis.yaml", Content: "content"}},
+			},
+		}
+		Expect(fakeClient.Create(ctx, workPlacement)).To(Succeed())
+
+		Expect(defaulter.Default(ctx, approval)).To(Succeed())
+		revision, err := v1alpha1.WorkloadsRevision(workPlacement.Spec.Workloads)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(approval.Spec.Revision).To(Equal(revision))
+		Expect(approval.Spec.ApprovedBy).To(Equal("approver@example.com"))
+	})
+
+	It("keeps a given revision", func() {
+		approval.Spec.Revision = "a-revision"
+		Expect(defaulter.Default(ctx, approval)).To(Succeed())
+		Expect(approval.Spec.Revision).To(Equal("a-revision"))
+	})
+
+	It("errors when the WorkPlacement does not exist", func() {
+		Expect(defaulter.Default(ctx, approval)).To(MatchError("workplacement redis-production not found"))
+	})
+
+	It("errors when the approver cannot be identified", func() {
+		approval.Spec.Revision = "a-revision"
+		Expect(defaulter.Default(context.TODO(), approval)).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,82 @@
+/*
+Copyright 2021 Syntasso.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var destinationlog = logf.Log.WithName("destination-resource")
+
+func SetupDestinationWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&v1alpha1.Destination{}).
+		WithValidator(&DestinationCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-platform-kratix-io-v1alpha1-destination,mutating=false,failurePolicy=fail,sideEffects=None,groups=platform.kratix.io,resources=destinations,verbs=create;update,versions=v1alpha1,name=vdestination.kb.io,admissionReviewVersions=v1
+
+type DestinationCustomValidator struct{}
+
+var _ webhook.CustomValidator = &DestinationCustomValidator{}
+
+func (v DestinationCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	destination, ok := obj.(*v1alpha1.Destination)
+	if !ok {
+		return nil, fmt.Errorf("expected a Destination object but got %T", obj)
+	}
+
+	destinationlog.Info("validate create", "name", destination.Name)
+	return nil, validateDestination(destination)
+}
+
+func (v DestinationCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	destination, ok := newObj.(*v1alpha1.Destination)
+	if !ok {
+		return nil, fmt.Errorf("expected a Destination object but got %T", newObj)
+	}
+
+	destinationlog.Info("validate update", "name", destination.Name)
+	return nil, validateDestination(destination)
+}
+
+func (v DestinationCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// Rejects a namespaceAccess.namespaceSelector that is not a valid label
+// selector, as it would otherwise deny access to every namespace
+func validateDestination(destination *v1alpha1.Destination) error {
+	access := destination.Spec.NamespaceAccess
+	if access == nil || access.NamespaceSelector == nil {
+		return nil
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(access.NamespaceSelector); err != nil {
+		return fmt.Errorf("spec.namespaceAccess.namespaceSelector is invalid: %w", err)
+	}
+	return nil
+}
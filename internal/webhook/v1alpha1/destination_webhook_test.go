@@ -0,0 +1,67 @@
+package v1alpha1_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/syntasso/kratix/api/v1alpha1"
+	kratixWebhook "github.com/syntasso/kratix/internal/webhook/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("DestinationWebhook", func() {
+	var (
+		destination *v1alpha1.Destination
+		validator   *kratixWebhook.DestinationCustomValidator
+	)
+
+	ctx := context.TODO()
+
+	BeforeEach(func() {
+		validator = &kratixWebhook.DestinationCustomValidator{}
+		destination = &v1alpha1.Destination{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+			Spec: v1alpha1.DestinationSpec{
+				NamespaceAccess: &v1alpha1.DestinationNamespaceAccess{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"tenant": "team-a"},
+					},
+				},
+			},
+		}
+	})
+
+	It("allows a valid namespace selector", func() {
+		_, err := validator.ValidateCreate(ctx, destination)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = validator.ValidateUpdate(ctx, destination, destination)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("allows a Destination without namespace access", func() {
+		destination.Spec.NamespaceAccess = nil
+		_, err := validator.ValidateCreate(ctx, destination)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	When("the namespace selector is invalid", func() {
+		BeforeEach(func() {
+			destination.Spec.NamespaceAccess.NamespaceSelector = &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      "tenant",
+					Operator: metav1.LabelSelectorOpIn,
+				}},
+			}
+		})
+
+		It("errors on create and update", func() {
+			_, err := validator.ValidateCreate(ctx, destination)
+			Expect(err).To(MatchError(ContainSubstring("spec.namespaceAccess.namespaceSelector is invalid")))
+
+			_, err = validator.ValidateUpdate(ctx, destination, destination)
+			Expect(err).To(MatchError(ContainSubstring("spec.namespaceAccess.namespaceSelector is invalid")))
+		})
+	})
+})
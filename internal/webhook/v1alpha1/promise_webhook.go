@@ -114,9 +114,31 @@ func validatePromise(p *v1alpha1.Promise) ([]string, error) {
 	if err := validatePipelines(p); err != nil {
 		return nil, err
 	}
+
+	if err := validateDestinationSelectors(p); err != nil {
+		return nil, err
+	}
 	return validateRequiredPromisesAreAvailable(p), nil
 }
 
+func validateDestinationSelectors(p *v1alpha1.Promise) error {
+	for i, selector := range p.Spec.DestinationSelectors {
+		if err := v1alpha1.ValidateMatchExpressions(selector.MatchExpressions); err != nil {
+			return fmt.Errorf("invalid spec.destinationSelectors[%d].matchExpressions: %w", i, err)
+		}
+		if err := v1alpha1.ValidatePreferredDestinationSelectors(selector.Preferred); err != nil {
+			return fmt.Errorf("invalid spec.destinationSelectors[%d]: %w", i, err)
+		}
+		if err := v1alpha1.ValidateResourceAffinityTerms(selector.ResourceAffinity); err != nil {
+			return fmt.Errorf("invalid spec.destinationSelectors[%d].resourceAffinity%w", i, err)
+		}
+		if err := v1alpha1.ValidateResourceAffinityTerms(selector.ResourceAntiAffinity); err != nil {
+			return fmt.Errorf("invalid spec.destinationSelectors[%d].resourceAntiAffinity%w", i, err)
+		}
+	}
+	return nil
+}
+
 func validatePipelines(p *v1alpha1.Promise) error {
 	promisePipelines, err := v1alpha1.NewPipelinesMap(p, promiselog)
 	if err != nil {
@@ -127,6 +127,60 @@ var _ = Describe("PromiseWebhook", func() {
 		})
 	})
 
+	Describe("Destination selectors", func() {
+		It("accepts valid matchExpressions", func() {
+			promise := newPromise()
+			promise.Spec.DestinationSelectors = []v1alpha1.PromiseScheduling{{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "region", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"eu-west"}},
+					{Key: "gpu", Operator: metav1.LabelSelectorOpExists},
+				},
+			}}
+			_, err := validator.ValidateCreate(ctx, promise)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects invalid matchExpressions", func() {
+			promise := newPromise()
+			promise.Spec.DestinationSelectors = []v1alpha1.PromiseScheduling{{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "tier", Operator: metav1.LabelSelectorOpIn},
+				},
+			}}
+			_, err := validator.ValidateCreate(ctx, promise)
+			Expect(err).To(MatchError(ContainSubstring("invalid spec.destinationSelectors[0].matchExpressions")))
+		})
+
+		It("rejects invalid preferred selectors", func() {
+			promise := newPromise()
+			promise.Spec.DestinationSelectors = []v1alpha1.PromiseScheduling{{
+				Preferred: []v1alpha1.PreferredDestinationSelector{{
+					Weight: 10,
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "tier", Operator: metav1.LabelSelectorOpIn},
+					},
+				}},
+			}}
+			_, err := validator.ValidateCreate(ctx, promise)
+			Expect(err).To(MatchError(ContainSubstring("invalid spec.destinationSelectors[0]: preferred[0]")))
+		})
+
+		It("rejects invalid resource affinity selectors", func() {
+			promise := newPromise()
+			promise.Spec.DestinationSelectors = []v1alpha1.PromiseScheduling{{
+				ResourceAntiAffinity: []v1alpha1.ResourceAffinityTerm{{
+					LabelSelector: metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{Key: "app", Operator: metav1.LabelSelectorOpIn},
+						},
+					},
+				}},
+			}}
+			_, err := validator.ValidateCreate(ctx, promise)
+			Expect(err).To(MatchError(ContainSubstring("invalid spec.destinationSelectors[0].resourceAntiAffinity[0].labelSelector")))
+		})
+	})
+
 	Describe("Workflows", func() {
 		var promise *v1alpha1.Promise
 
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 Syntasso.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var workplacementlog = logf.Log.WithName("workplacement-webhook")
+
+func SetupWorkPlacementWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&v1alpha1.WorkPlacement{}).
+		WithDefaulter(&WorkPlacementCustomDefaulter{}).
+		Complete()
+}
+
+// Failures reject the request, as otherwise the approval annotations could be
+// set directly while the webhook is unavailable.
+// +kubebuilder:webhook:path=/mutate-platform-kratix-io-v1alpha1-workplacement,mutating=true,failurePolicy=fail,sideEffects=None,groups=platform.kratix.io,resources=workplacements,verbs=create;update,versions=v1alpha1,name=mworkplacement.kb.io,admissionReviewVersions=v1
+
+type WorkPlacementCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &WorkPlacementCustomDefaulter{}
+
+// Replaces the approve annotation with the revision of the workloads it
+// approves, and who approved them. Any other change to the approved revision
+// and approver annotations is reverted, so approvals can't be forged.
+func (d WorkPlacementCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	workPlacement, ok := obj.(*v1alpha1.WorkPlacement)
+	if !ok {
+		return fmt.Errorf("expected a WorkPlacement object but got %T", obj)
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to read the admission request: %w", err)
+	}
+
+	annotations := workPlacement.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	if _, approve := annotations[v1alpha1.ApproveAnnotation]; approve {
+		revision, err := v1alpha1.WorkloadsRevision(workPlacement.Spec.Workloads)
+		if err != nil {
+			return err
+		}
+
+		workplacementlog.Info("approving workloads", "name", workPlacement.Name, "revision", revision, "approvedBy", req.UserInfo.Username)
+		delete(annotations, v1alpha1.ApproveAnnotation)
+		annotations[v1alpha1.ApprovedRevisionAnnotation] = revision
+		annotations[v1alpha1.ApprovedByAnnotation] = req.UserInfo.Username
+		workPlacement.SetAnnotations(annotations)
+		return nil
+	}
+
+	oldAnnotations := map[string]string{}
+	if len(req.OldObject.Raw) > 0 {
+		old := &v1alpha1.WorkPlacement{}
+		if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+			return fmt.Errorf("unable to decode the existing WorkPlacement: %w", err)
+		}
+		oldAnnotations = old.GetAnnotations()
+	}
+
+	reverted := false
+	for _, key := range []string{v1alpha1.ApprovedRevisionAnnotation, v1alpha1.ApprovedByAnnotation} {
+		value, found := annotations[key]
+		oldValue, oldFound := oldAnnotations[key]
+		if found == oldFound && value == oldValue {
+			continue
+		}
+		workplacementlog.Info("reverting change to approval annotation", "name", workPlacement.Name, "annotation", key, "user", req.UserInfo.Username)
+		if oldFound {
+			annotations[key] = oldValue
+		} else {
+			delete(annotations, key)
+		}
+		reverted = true
+	}
+	if reverted {
+		workPlacement.SetAnnotations(annotations)
+	}
+	return nil
+}
+
+// Returns the user making the admission request
+func requestUsername(ctx context.Context) (string, error) {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to identify the approver: %w", err)
+	}
+	return req.UserInfo.Username, nil
+}
@@ -0,0 +1,96 @@
+package v1alpha1_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	kratixWebhook "github.com/syntasso/kratix/internal/webhook/v1alpha1"
+)
+
+var _ = Describe("WorkPlacementWebhook", func() {
+	var (
+		ctx           context.Context
+		workPlacement *v1alpha1.WorkPlacement
+		defaulter     *kratixWebhook.WorkPlacementCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		ctx = admission.NewContextWithRequest(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{Username: "approver@example.com"},
+			},
+		})
+		defaulter = &kratixWebhook.WorkPlacementCustomDefaulter{}
+		workPlacement = &v1alpha1.WorkPlacement{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "redis-production",
+				Namespace: "default",
+			},
+			Spec: v1alpha1.WorkPlacementSpec{
+				Workloads: []v1alpha1.Workload{{Filepath: "redis.yaml", Content: "content"}},
+			},
+		}
+	})
+
+	It("replaces the approve annotation with the approved revision and approver", func() {
+		workPlacement.SetAnnotations(map[string]string{v1alpha1.ApproveAnnotation: ""})
+		Expect(defaulter.Default(ctx, workPlacement)).To(Succeed())
+
+		revision, err := v1alpha1.WorkloadsRevision(workPlacement.Spec.Workloads)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(workPlacement.GetAnnotations()).To(Equal(map[string]string{
+			v1alpha1.ApprovedRevisionAnnotation: revision,
+			v1alpha1.ApprovedByAnnotation:       "approver@example.com",
+		}))
+	})
+
+	It("leaves WorkPlacements without the approve annotation unchanged", func() {
+		Expect(defaulter.Default(ctx, workPlacement)).To(Succeed())
+		Expect(workPlacement.GetAnnotations()).To(BeEmpty())
+	})
+
+	It("removes approval annotations set on create", func() {
+		workPlacement.SetAnnotations(map[string]string{
+			v1alpha1.ApprovedRevisionAnnotation: "forged",
+			v1alpha1.ApprovedByAnnotation:       "someone-else",
+			"other":                             "kept",
+		})
+		Expect(defaulter.Default(ctx, workPlacement)).To(Succeed())
+		Expect(workPlacement.GetAnnotations()).To(Equal(map[string]string{"other": "kept"}))
+	})
+
+	It("reverts changes to the approval annotations on update", func() {
+		old := workPlacement.DeepCopy()
+		old.SetAnnotations(map[string]string{
+			v1alpha1.ApprovedRevisionAnnotation: "approved",
+			v1alpha1.ApprovedByAnnotation:       "approver@example.com",
+		})
+		raw, err := json.Marshal(old)
+		Expect(err).NotTo(HaveOccurred())
+		ctx = admission.NewContextWithRequest(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo:  authenticationv1.UserInfo{Username: "forger@example.com"},
+				OldObject: runtime.RawExtension{Raw: raw},
+			},
+		})
+
+		workPlacement.SetAnnotations(map[string]string{
+			v1alpha1.ApprovedRevisionAnnotation: "forged",
+		})
+		Expect(defaulter.Default(ctx, workPlacement)).To(Succeed())
+		Expect(workPlacement.GetAnnotations()).To(Equal(old.GetAnnotations()))
+	})
+
+	It("errors when the admission request is missing", func() {
+		Expect(defaulter.Default(context.TODO(), workPlacement)).To(MatchError(ContainSubstring("unable to read the admission request")))
+	})
+})
@@ -6,31 +6,107 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec is the algorithm used to compress workload content
+type Codec string
+
+const (
+	CodecGzip Codec = "gzip"
+	CodecZstd Codec = "zstd"
+
+	// The version of the encoding header. Content is encoded as
+	// "<version>:<codec>:<base64 of the compressed content>". The base64
+	// alphabet has no ":", so content without a header is told apart from
+	// content with one; it is always gzip. Gzip content is still encoded
+	// without a header, so versions of Kratix from before the header was
+	// introduced can read it during an upgrade or after a rollback.
+	encodingVersion = "v1"
+	headerSeparator = ":"
+)
+
+// DefaultCodec is the codec CompressContent uses
+var DefaultCodec = CodecGzip
+
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
 )
 
+// Returns the codec if it is supported, or an error otherwise. An empty name
+// is the DefaultCodec.
+func ParseCodec(name string) (Codec, error) {
+	switch codec := Codec(name); codec {
+	case "":
+		return DefaultCodec, nil
+	case CodecGzip, CodecZstd:
+		return codec, nil
+	default:
+		return "", fmt.Errorf("unsupported codec %q", name)
+	}
+}
+
 func CompressContent(content []byte) ([]byte, error) {
-	var buf bytes.Buffer
-	zw := gzip.NewWriter(&buf)
-	_, err := zw.Write(content)
-	if err != nil {
-		return nil, err
+	return EncodeContent(content, DefaultCodec)
+}
+
+// Compresses the content with the codec, and encodes it with a header
+// describing how it was compressed. Gzip content has no header.
+func EncodeContent(content []byte, codec Codec) ([]byte, error) {
+	var compressedBytes []byte
+	switch codec {
+	case CodecGzip:
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, err := zw.Write(content)
+		if err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		compressedBytes = buf.Bytes()
+	case CodecZstd:
+		compressedBytes = zstdEncoder.EncodeAll(content, nil)
+	default:
+		return nil, fmt.Errorf("unsupported codec %q", codec)
 	}
-	if err := zw.Close(); err != nil {
-		return nil, err
+
+	var header string
+	if codec != CodecGzip {
+		header = encodingVersion + headerSeparator + string(codec) + headerSeparator
 	}
-	compressedBytes := buf.Bytes()
-	encodedBytes := make([]byte, base64.StdEncoding.EncodedLen(len(compressedBytes)))
-	base64.StdEncoding.Encode(encodedBytes, compressedBytes)
+	encodedBytes := make([]byte, len(header)+base64.StdEncoding.EncodedLen(len(compressedBytes)))
+	copy(encodedBytes, header)
+	base64.StdEncoding.Encode(encodedBytes[len(header):], compressedBytes)
 	return encodedBytes, nil
 }
 
+// Returns the codec the content was compressed with
+func ContentCodec(compressedBytes []byte) (Codec, error) {
+	codec, _, err := parseHeader(compressedBytes)
+	return codec, err
+}
+
 func DecompressContent(compressedBytes []byte) ([]byte, error) {
-	decodedBytes := make([]byte, base64.StdEncoding.DecodedLen(len(compressedBytes)))
-	n, err := base64.StdEncoding.Decode(decodedBytes, compressedBytes)
+	codec, encodedBytes, err := parseHeader(compressedBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	decodedBytes := make([]byte, base64.StdEncoding.DecodedLen(len(encodedBytes)))
+	n, err := base64.StdEncoding.Decode(decodedBytes, encodedBytes)
 	if err != nil {
 		return nil, err
 	}
 
+	if codec == CodecZstd {
+		return zstdDecoder.DecodeAll(decodedBytes[:n], nil)
+	}
+
 	zr, err := gzip.NewReader(bytes.NewReader(decodedBytes[:n]))
 	if err != nil {
 		return nil, err
@@ -45,6 +121,25 @@ func DecompressContent(compressedBytes []byte) ([]byte, error) {
 	return decompressed, nil
 }
 
+func parseHeader(compressedBytes []byte) (Codec, []byte, error) {
+	if !bytes.Contains(compressedBytes, []byte(headerSeparator)) {
+		return CodecGzip, compressedBytes, nil
+	}
+
+	parts := strings.SplitN(string(compressedBytes), headerSeparator, 3)
+	if len(parts) != 3 {
+		return "", nil, fmt.Errorf("malformed encoding header")
+	}
+	if parts[0] != encodingVersion {
+		return "", nil, fmt.Errorf("unsupported encoding version %q", parts[0])
+	}
+	codec := Codec(parts[1])
+	if codec != CodecGzip && codec != CodecZstd {
+		return "", nil, fmt.Errorf("unsupported codec %q", parts[1])
+	}
+	return codec, []byte(parts[2]), nil
+}
+
 func InCompressedContents(compressedContent string, content []byte) (bool, error) {
 	decompressedContent, err := DecompressContent([]byte(compressedContent))
 	if err != nil {
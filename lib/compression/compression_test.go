@@ -1,6 +1,11 @@
 package compression_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -12,18 +17,18 @@ var _ = Describe("Utils", func() {
 		longContent []byte
 	)
 
-	Describe("CompressContent", func() {
-		BeforeEach(func() {
-			longContent = []byte(`This is a string, a long long long long long long long long string
-				This is a string, a long long long long long long long long string
-				This is a string, a long long long long long long long long string
-				This is a string, a long long long long long long long long string
-				This is a string, a long long long long long long long long string
-				This is a string, a long long long long long long long long string
-				This is a string, a long long long long long long long long string
-			`)
-		})
+	BeforeEach(func() {
+		longContent = []byte(`This is a string, a long long long long long long long long string
+			This is a string, a long long long long long long long long string
+			This is a string, a long long long long long long long long string
+			This is a string, a long long long long long long long long string
+			This is a string, a long long long long long long long long string
+			This is a string, a long long long long long long long long string
+			This is a string, a long long long long long long long long string
+		`)
+	})
 
+	Describe("CompressContent", func() {
 		It("CompressContent compresses a long byte array", func() {
 			longContentSize := len(longContent)
 			compressedContent, err := compression.CompressContent(longContent)
@@ -41,4 +46,71 @@ var _ = Describe("Utils", func() {
 			Expect(decompressedContent).To(Equal(longContent))
 		})
 	})
+
+	Describe("EncodeContent", func() {
+		It("compresses the content with the codec, described in a header", func() {
+			encodedContent, err := compression.EncodeContent(longContent, compression.CodecZstd)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(encodedContent)).To(HavePrefix("v1:zstd:"))
+			Expect(compression.ContentCodec(encodedContent)).To(Equal(compression.CodecZstd))
+
+			decompressedContent, err := compression.DecompressContent(encodedContent)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decompressedContent).To(Equal(longContent))
+		})
+
+		It("encodes gzip content without a header, as readers from before the header expect", func() {
+			encodedContent, err := compression.EncodeContent(longContent, compression.CodecGzip)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(encodedContent)).NotTo(ContainSubstring(":"))
+			Expect(compression.ContentCodec(encodedContent)).To(Equal(compression.CodecGzip))
+
+			By("being readable as plain base64 of gzip")
+			decodedContent, err := base64.StdEncoding.DecodeString(string(encodedContent))
+			Expect(err).ToNot(HaveOccurred())
+			zr, err := gzip.NewReader(bytes.NewReader(decodedContent))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(io.ReadAll(zr)).To(Equal(longContent))
+		})
+
+		It("errors for unsupported codecs", func() {
+			_, err := compression.EncodeContent(longContent, "lz4")
+			Expect(err).To(MatchError(`unsupported codec "lz4"`))
+		})
+	})
+
+	Describe("DecompressContent", func() {
+		It("decompresses gzip content encoded without a header", func() {
+			// gzip+base64 of "legacy content", as written before the encoding header
+			legacyContent := []byte("H4sIAAAAAAAA/wAOAPH/bGVnYWN5IGNvbnRlbnQDAI2F0FUOAAAA")
+			Expect(compression.ContentCodec(legacyContent)).To(Equal(compression.CodecGzip))
+			decompressedContent, err := compression.DecompressContent(legacyContent)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(decompressedContent)).To(Equal("legacy content"))
+		})
+
+		It("decompresses gzip content encoded with a header", func() {
+			legacyContent, err := compression.CompressContent(longContent)
+			Expect(err).ToNot(HaveOccurred())
+			decompressedContent, err := compression.DecompressContent(append([]byte("v1:gzip:"), legacyContent...))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decompressedContent).To(Equal(longContent))
+		})
+
+		It("errors for unsupported encoding versions and codecs", func() {
+			_, err := compression.DecompressContent([]byte("v2:gzip:H4sI"))
+			Expect(err).To(MatchError(`unsupported encoding version "v2"`))
+			_, err = compression.DecompressContent([]byte("v1:lz4:H4sI"))
+			Expect(err).To(MatchError(`unsupported codec "lz4"`))
+		})
+	})
+
+	Describe("ParseCodec", func() {
+		It("returns the default codec when empty", func() {
+			Expect(compression.ParseCodec("")).To(Equal(compression.DefaultCodec))
+			Expect(compression.ParseCodec("zstd")).To(Equal(compression.CodecZstd))
+			_, err := compression.ParseCodec("lz4")
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
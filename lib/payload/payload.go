@@ -0,0 +1,355 @@
+package payload
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/syntasso/kratix/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// The combined size of the workload contents a Work can hold inline. Past
+	// it, the largest workloads are moved to ConfigMaps, keeping the Work and
+	// its WorkPlacements well within the object size limit.
+	MaxInlineContentSize = 512 * 1024
+	// The size of the content held by each ConfigMap
+	ChunkSize = 768 * 1024
+	// The size under which Deduplicate keeps the content of a workload
+	// inline, as a ConfigMap would take more space than it saves
+	MinDeduplicatedContentSize = 1024
+	// How long a ConfigMap can be left without a Work owning it before
+	// CollectOrphans deletes it, giving the work-creator time to write the
+	// Work referencing it. Claims on stored content last as long.
+	OrphanGracePeriod = 10 * time.Minute
+
+	DigestLabel = v1alpha1.KratixPrefix + "payload-digest"
+	// Labels the ConfigMaps claiming stored content for a Work not yet written
+	ClaimLabel = v1alpha1.KratixPrefix + "payload-claim"
+	contentKey = "content"
+)
+
+// Moves the content of the largest workloads of the WorkloadGroups to
+// ConfigMaps in the namespace, until the content left inline fits in
+// MaxInlineContentSize. The ConfigMaps are named after the digest of the
+// content, so identical content is only stored once. The moved workloads
+// reference their content with ContentRef.
+func Offload(ctx context.Context, c client.Client, namespace string, workloadGroups []v1alpha1.WorkloadGroup) error {
+	var workloads []*v1alpha1.Workload
+	inlineSize := 0
+	for i := range workloadGroups {
+		for j := range workloadGroups[i].Workloads {
+			workload := &workloadGroups[i].Workloads[j]
+			if workload.ContentRef == nil {
+				workloads = append(workloads, workload)
+				inlineSize += len(workload.Content)
+			}
+		}
+	}
+
+	sort.SliceStable(workloads, func(i, j int) bool {
+		return len(workloads[i].Content) > len(workloads[j].Content)
+	})
+
+	stored := map[string]*v1alpha1.WorkloadContentRef{}
+	for _, workload := range workloads {
+		if inlineSize <= MaxInlineContentSize {
+			break
+		}
+		ref, err := store(ctx, c, namespace, workload.Content, stored)
+		if err != nil {
+			return err
+		}
+		inlineSize -= len(workload.Content)
+		workload.Content = ""
+		workload.ContentRef = ref
+	}
+	return nil
+}
+
+// Moves the content of the workloads of the WorkloadGroups to the ConfigMaps
+// in the namespace, like Offload, regardless of the size left inline. As the
+// ConfigMaps are named after the digest of the content, identical content in
+// the Work, in other Works of the namespace and in the WorkPlacements of the
+// Work is then stored once. Content under MinDeduplicatedContentSize is kept
+// inline.
+func Deduplicate(ctx context.Context, c client.Client, namespace string, workloadGroups []v1alpha1.WorkloadGroup) error {
+	stored := map[string]*v1alpha1.WorkloadContentRef{}
+	for i := range workloadGroups {
+		for j := range workloadGroups[i].Workloads {
+			workload := &workloadGroups[i].Workloads[j]
+			if workload.ContentRef != nil || len(workload.Content) < MinDeduplicatedContentSize {
+				continue
+			}
+			ref, err := store(ctx, c, namespace, workload.Content, stored)
+			if err != nil {
+				return err
+			}
+			workload.Content = ""
+			workload.ContentRef = ref
+		}
+	}
+	return nil
+}
+
+// Stores the content in ConfigMaps named after its digest, reusing the ones
+// already holding it. Reused content is claimed, as the Works owning it may stop
+// referencing it before the Work about to reference it is owned. Content
+// already stored in the same call is looked up in stored.
+func store(ctx context.Context, c client.Client, namespace, content string, stored map[string]*v1alpha1.WorkloadContentRef) (*v1alpha1.WorkloadContentRef, error) {
+	digest := fmt.Sprintf("%x", sha256.Sum256([]byte(content)))
+	if ref, found := stored[digest]; found {
+		copied := *ref
+		return &copied, nil
+	}
+	ref := &v1alpha1.WorkloadContentRef{Digest: digest}
+
+	reused := false
+	for start := 0; start < len(content); start += ChunkSize {
+		end := min(start+ChunkSize, len(content))
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      chunkName(ref.Digest, ref.Chunks),
+				Namespace: namespace,
+				Labels:    map[string]string{DigestLabel: shortDigest(ref.Digest)},
+			},
+			Data: map[string]string{contentKey: content[start:end]},
+		}
+		if err := c.Create(ctx, configMap); err != nil {
+			if !errors.IsAlreadyExists(err) {
+				return nil, fmt.Errorf("failed to store workload content: %w", err)
+			}
+			if err := checkExisting(ctx, c, configMap); err != nil {
+				return nil, err
+			}
+			reused = true
+		}
+		ref.Chunks++
+	}
+
+	if reused {
+		if err := claim(ctx, c, namespace, ref.Digest); err != nil {
+			return nil, err
+		}
+	}
+	stored[digest] = ref
+	return ref, nil
+}
+
+// Creates a ConfigMap claiming the stored content, keeping it for
+// OrphanGracePeriod even when no Work owns it. Pipelines are only allowed to
+// create ConfigMaps, so the claim is a ConfigMap of its own rather than a
+// change to the ones holding the content.
+func claim(ctx context.Context, c client.Client, namespace, digest string) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "kratix-payload-claim-",
+			Namespace:    namespace,
+			Labels:       map[string]string{ClaimLabel: shortDigest(digest)},
+		},
+	}
+	if err := c.Create(ctx, configMap); err != nil {
+		return fmt.Errorf("failed to claim stored workload content: %w", err)
+	}
+	return nil
+}
+
+// Checks the ConfigMap already in the cluster under the name of the chunk holds
+// the chunk, so the work-creator never references a ConfigMap it did not store
+func checkExisting(ctx context.Context, c client.Client, chunk *corev1.ConfigMap) error {
+	existing := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(chunk), existing); err != nil {
+		return fmt.Errorf("failed to read stored workload content: %w", err)
+	}
+	if existing.GetLabels()[DigestLabel] != chunk.GetLabels()[DigestLabel] || existing.Data[contentKey] != chunk.Data[contentKey] {
+		return fmt.Errorf("ConfigMap %s/%s exists and does not hold the workload content", chunk.GetNamespace(), chunk.GetName())
+	}
+	return nil
+}
+
+// Returns the content of the workload, reading it from the ConfigMaps in the
+// namespace if it is referenced with ContentRef
+func Resolve(ctx context.Context, c client.Client, namespace string, workload v1alpha1.Workload) (string, error) {
+	if workload.ContentRef == nil {
+		return workload.Content, nil
+	}
+
+	var content strings.Builder
+	for i := 0; i < workload.ContentRef.Chunks; i++ {
+		configMap := &corev1.ConfigMap{}
+		key := client.ObjectKey{Namespace: namespace, Name: chunkName(workload.ContentRef.Digest, i)}
+		if err := c.Get(ctx, key, configMap); err != nil {
+			return "", fmt.Errorf("failed to read content of workload %s: %w", workload.Filepath, err)
+		}
+		content.WriteString(configMap.Data[contentKey])
+	}
+
+	if digest := fmt.Sprintf("%x", sha256.Sum256([]byte(content.String()))); digest != workload.ContentRef.Digest {
+		return "", fmt.Errorf("content of workload %s does not match its digest", workload.Filepath)
+	}
+	return content.String(), nil
+}
+
+// Adds the Work as an owner of the ConfigMaps holding the content of its
+// workloads, and removes it as an owner of the ConfigMaps in the namespace it
+// no longer references. A ConfigMap left without owners is deleted, unless its
+// content is claimed; CollectOrphans deletes it once the claim lapses.
+func SetOwner(ctx context.Context, c client.Client, work *v1alpha1.Work) error {
+	return setOwner(ctx, c, work, referencedConfigMaps(work.Spec.WorkloadGroups))
+}
+
+// Removes the Work as an owner of the ConfigMaps in the namespace, as SetOwner
+// does for a Work referencing none. Called before the Work is deleted, so
+// claimed content is not garbage collected along with it.
+func Release(ctx context.Context, c client.Client, work *v1alpha1.Work) error {
+	return setOwner(ctx, c, work, nil)
+}
+
+func setOwner(ctx context.Context, c client.Client, work *v1alpha1.Work, referenced []string) error {
+	for _, name := range referenced {
+		configMap := &corev1.ConfigMap{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: work.GetNamespace(), Name: name}, configMap); err != nil {
+			return err
+		}
+		if isOwnedBy(configMap, work) {
+			continue
+		}
+		if err := controllerutil.SetOwnerReference(work, configMap, c.Scheme()); err != nil {
+			return err
+		}
+		if err := c.Update(ctx, configMap); err != nil {
+			return err
+		}
+	}
+
+	configMaps := &corev1.ConfigMapList{}
+	if err := c.List(ctx, configMaps, client.InNamespace(work.GetNamespace()), client.HasLabels{DigestLabel}); err != nil {
+		return err
+	}
+	claimed, err := claimedDigests(ctx, c, work.GetNamespace())
+	if err != nil {
+		return err
+	}
+	for i := range configMaps.Items {
+		configMap := &configMaps.Items[i]
+		if slices.Contains(referenced, configMap.GetName()) || !isOwnedBy(configMap, work) {
+			continue
+		}
+		if err := controllerutil.RemoveOwnerReference(work, configMap, c.Scheme()); err != nil {
+			return err
+		}
+		if len(configMap.GetOwnerReferences()) == 0 && !claimed[configMap.GetLabels()[DigestLabel]] {
+			if err := c.Delete(ctx, configMap); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			continue
+		}
+		if err := c.Update(ctx, configMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Deletes the ConfigMaps in the namespace that no Work has owned for longer
+// than OrphanGracePeriod, left behind when the Work referencing them could not
+// be written, along with the lapsed claims. Claimed content is kept.
+func CollectOrphans(ctx context.Context, c client.Client, namespace string) error {
+	claims := &corev1.ConfigMapList{}
+	if err := c.List(ctx, claims, client.InNamespace(namespace), client.HasLabels{ClaimLabel}); err != nil {
+		return err
+	}
+	for i := range claims.Items {
+		if !pastGracePeriod(&claims.Items[i]) {
+			continue
+		}
+		if err := c.Delete(ctx, &claims.Items[i]); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	claimed, err := claimedDigests(ctx, c, namespace)
+	if err != nil {
+		return err
+	}
+	configMaps := &corev1.ConfigMapList{}
+	if err := c.List(ctx, configMaps, client.InNamespace(namespace), client.HasLabels{DigestLabel}); err != nil {
+		return err
+	}
+	for i := range configMaps.Items {
+		configMap := &configMaps.Items[i]
+		if len(configMap.GetOwnerReferences()) > 0 || !pastGracePeriod(configMap) || claimed[configMap.GetLabels()[DigestLabel]] {
+			continue
+		}
+		if err := c.Delete(ctx, configMap); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the digests of the content claimed in the namespace within the last
+// OrphanGracePeriod
+func claimedDigests(ctx context.Context, c client.Client, namespace string) (map[string]bool, error) {
+	claims := &corev1.ConfigMapList{}
+	if err := c.List(ctx, claims, client.InNamespace(namespace), client.HasLabels{ClaimLabel}); err != nil {
+		return nil, err
+	}
+	claimed := map[string]bool{}
+	for i := range claims.Items {
+		if !pastGracePeriod(&claims.Items[i]) {
+			claimed[claims.Items[i].GetLabels()[ClaimLabel]] = true
+		}
+	}
+	return claimed, nil
+}
+
+func pastGracePeriod(configMap *corev1.ConfigMap) bool {
+	return time.Since(configMap.GetCreationTimestamp().Time) >= OrphanGracePeriod
+}
+
+// Returns the names of the ConfigMaps holding the content of the workloads
+func referencedConfigMaps(workloadGroups []v1alpha1.WorkloadGroup) []string {
+	var names []string
+	for _, workloadGroup := range workloadGroups {
+		for _, workload := range workloadGroup.Workloads {
+			if workload.ContentRef == nil {
+				continue
+			}
+			for i := 0; i < workload.ContentRef.Chunks; i++ {
+				if name := chunkName(workload.ContentRef.Digest, i); !slices.Contains(names, name) {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+func isOwnedBy(obj metav1.Object, owner metav1.Object) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == owner.GetUID() {
+			return true
+		}
+	}
+	return false
+}
+
+func chunkName(digest string, index int) string {
+	return fmt.Sprintf("kratix-payload-%s-%d", shortDigest(digest), index)
+}
+
+// Returns the part of the digest used in ConfigMap names and labels, as a
+// sha256 digest is longer than a label value can be
+func shortDigest(digest string) string {
+	return digest[:32]
+}
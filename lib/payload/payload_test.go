@@ -0,0 +1,319 @@
+package payload_test
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/syntasso/kratix/api/v1alpha1"
+	"github.com/syntasso/kratix/lib/payload"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Payload", func() {
+	var (
+		ctx            context.Context
+		k8sClient      client.Client
+		largeContent   string
+		workloadGroups []v1alpha1.WorkloadGroup
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		Expect(v1alpha1.AddToScheme(scheme.Scheme)).To(Succeed())
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+
+		largeContent = strings.Repeat("a", payload.ChunkSize+10)
+		workloadGroups = []v1alpha1.WorkloadGroup{
+			{Workloads: []v1alpha1.Workload{
+				{Filepath: "small.yaml", Content: "small"},
+				{Filepath: "large.yaml", Content: largeContent},
+			}},
+			{Workloads: []v1alpha1.Workload{
+				{Filepath: "copy.yaml", Content: largeContent},
+			}},
+		}
+	})
+
+	Describe("Offload", func() {
+		It("moves the largest workloads to ConfigMaps until the rest fits inline", func() {
+			Expect(payload.Offload(ctx, k8sClient, "default", workloadGroups)).To(Succeed())
+
+			Expect(workloadGroups[0].Workloads[0]).To(Equal(v1alpha1.Workload{Filepath: "small.yaml", Content: "small"}))
+			large := workloadGroups[0].Workloads[1]
+			Expect(large.Content).To(BeEmpty())
+			Expect(large.ContentRef.Chunks).To(Equal(2))
+
+			By("storing identical content once")
+			Expect(workloadGroups[1].Workloads[0].ContentRef).To(Equal(large.ContentRef))
+			configMaps := &corev1.ConfigMapList{}
+			Expect(k8sClient.List(ctx, configMaps)).To(Succeed())
+			Expect(configMaps.Items).To(HaveLen(2))
+
+			By("labelling the ConfigMaps with a digest short enough for a label value")
+			for _, configMap := range configMaps.Items {
+				Expect(configMap.GetLabels()[payload.DigestLabel]).To(Equal(large.ContentRef.Digest[:32]))
+			}
+
+			By("resolving the content from the ConfigMaps")
+			content, err := payload.Resolve(ctx, k8sClient, "default", large)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(Equal(largeContent))
+		})
+
+		It("leaves Works that fit inline unchanged", func() {
+			workloadGroups = workloadGroups[:1]
+			workloadGroups[0].Workloads[1].Content = "not so large"
+			Expect(payload.Offload(ctx, k8sClient, "default", workloadGroups)).To(Succeed())
+			Expect(workloadGroups[0].Workloads[1].ContentRef).To(BeNil())
+		})
+	})
+
+	Describe("storing content", func() {
+		It("reuses a ConfigMap already holding the content", func() {
+			Expect(payload.Offload(ctx, k8sClient, "default", workloadGroups)).To(Succeed())
+			ref := workloadGroups[0].Workloads[1].ContentRef
+
+			workloadGroups[0].Workloads[1] = v1alpha1.Workload{Filepath: "large.yaml", Content: largeContent}
+			Expect(payload.Offload(ctx, k8sClient, "default", workloadGroups)).To(Succeed())
+			Expect(workloadGroups[0].Workloads[1].ContentRef).To(Equal(ref))
+		})
+
+		It("errors when a ConfigMap under the name of the content holds something else", func() {
+			Expect(payload.Offload(ctx, k8sClient, "default", workloadGroups)).To(Succeed())
+			configMaps := &corev1.ConfigMapList{}
+			Expect(k8sClient.List(ctx, configMaps)).To(Succeed())
+			for i := range configMaps.Items {
+				configMaps.Items[i].Labels = nil
+				Expect(k8sClient.Update(ctx, &configMaps.Items[i])).To(Succeed())
+			}
+
+			workloadGroups[0].Workloads[1] = v1alpha1.Workload{Filepath: "large.yaml", Content: largeContent}
+			err := payload.Offload(ctx, k8sClient, "default", workloadGroups)
+			Expect(err).To(MatchError(ContainSubstring("does not hold the workload content")))
+		})
+	})
+
+	Describe("Deduplicate", func() {
+		It("moves every workload over the minimum size to ConfigMaps, storing identical content once", func() {
+			content := strings.Repeat("b", payload.MinDeduplicatedContentSize)
+			workloadGroups = []v1alpha1.WorkloadGroup{
+				{Workloads: []v1alpha1.Workload{
+					{Filepath: "small.yaml", Content: "small"},
+					{Filepath: "crd.yaml", Content: content},
+				}},
+				{Workloads: []v1alpha1.Workload{
+					{Filepath: "crd.yaml", Content: content},
+				}},
+			}
+			Expect(payload.Deduplicate(ctx, k8sClient, "default", workloadGroups)).To(Succeed())
+
+			Expect(workloadGroups[0].Workloads[0]).To(Equal(v1alpha1.Workload{Filepath: "small.yaml", Content: "small"}))
+			ref := workloadGroups[0].Workloads[1].ContentRef
+			Expect(ref).NotTo(BeNil())
+			Expect(workloadGroups[0].Workloads[1].Content).To(BeEmpty())
+			Expect(workloadGroups[1].Workloads[0].ContentRef).To(Equal(ref))
+
+			configMaps := &corev1.ConfigMapList{}
+			Expect(k8sClient.List(ctx, configMaps)).To(Succeed())
+			Expect(configMaps.Items).To(HaveLen(1))
+
+			resolved, err := payload.Resolve(ctx, k8sClient, "default", workloadGroups[1].Workloads[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resolved).To(Equal(content))
+		})
+	})
+
+	Describe("Resolve", func() {
+		It("errors when the content does not match its digest", func() {
+			Expect(payload.Offload(ctx, k8sClient, "default", workloadGroups)).To(Succeed())
+
+			configMaps := &corev1.ConfigMapList{}
+			Expect(k8sClient.List(ctx, configMaps)).To(Succeed())
+			configMaps.Items[0].Data["content"] = "tampered"
+			Expect(k8sClient.Update(ctx, &configMaps.Items[0])).To(Succeed())
+
+			_, err := payload.Resolve(ctx, k8sClient, "default", workloadGroups[0].Workloads[1])
+			Expect(err).To(MatchError(ContainSubstring("does not match its digest")))
+		})
+	})
+
+	Describe("SetOwner", func() {
+		It("adds the Work as an owner of the ConfigMaps it references", func() {
+			Expect(payload.Offload(ctx, k8sClient, "default", workloadGroups)).To(Succeed())
+			work := &v1alpha1.Work{
+				ObjectMeta: metav1.ObjectMeta{Name: "work", Namespace: "default"},
+				Spec:       v1alpha1.WorkSpec{WorkloadGroups: workloadGroups},
+			}
+			Expect(k8sClient.Create(ctx, work)).To(Succeed())
+
+			Expect(payload.SetOwner(ctx, k8sClient, work)).To(Succeed())
+
+			configMaps := &corev1.ConfigMapList{}
+			Expect(k8sClient.List(ctx, configMaps)).To(Succeed())
+			for _, configMap := range configMaps.Items {
+				Expect(configMap.GetOwnerReferences()).To(ConsistOf(
+					HaveField("Name", "work"),
+				))
+			}
+		})
+
+		It("removes the Work as an owner of the ConfigMaps it no longer references", func() {
+			Expect(payload.Offload(ctx, k8sClient, "default", workloadGroups)).To(Succeed())
+			work := &v1alpha1.Work{
+				ObjectMeta: metav1.ObjectMeta{Name: "work", Namespace: "default", UID: "work-uid"},
+				Spec:       v1alpha1.WorkSpec{WorkloadGroups: workloadGroups},
+			}
+			Expect(k8sClient.Create(ctx, work)).To(Succeed())
+			Expect(payload.SetOwner(ctx, k8sClient, work)).To(Succeed())
+
+			other := &v1alpha1.Work{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", UID: "other-uid"},
+				Spec:       v1alpha1.WorkSpec{WorkloadGroups: workloadGroups[1:]},
+			}
+			Expect(k8sClient.Create(ctx, other)).To(Succeed())
+			Expect(payload.SetOwner(ctx, k8sClient, other)).To(Succeed())
+
+			By("keeping the ConfigMaps still owned by another Work")
+			work.Spec.WorkloadGroups = []v1alpha1.WorkloadGroup{{Workloads: []v1alpha1.Workload{{Filepath: "small.yaml", Content: "small"}}}}
+			Expect(payload.SetOwner(ctx, k8sClient, work)).To(Succeed())
+
+			configMaps := &corev1.ConfigMapList{}
+			Expect(k8sClient.List(ctx, configMaps)).To(Succeed())
+			Expect(configMaps.Items).To(HaveLen(2))
+			for _, configMap := range configMaps.Items {
+				Expect(configMap.GetOwnerReferences()).To(ConsistOf(HaveField("Name", "other")))
+			}
+
+			By("deleting the ConfigMaps left without owners")
+			other.Spec.WorkloadGroups = work.Spec.WorkloadGroups
+			Expect(payload.SetOwner(ctx, k8sClient, other)).To(Succeed())
+			Expect(k8sClient.List(ctx, configMaps)).To(Succeed())
+			Expect(configMaps.Items).To(BeEmpty())
+		})
+	})
+
+	Describe("claiming stored content", func() {
+		var first *v1alpha1.Work
+
+		// the fake client leaves the creation timestamp unset, which reads as a
+		// claim made long ago
+		claimNow := func() {
+			claims := &corev1.ConfigMapList{}
+			Expect(k8sClient.List(ctx, claims, client.HasLabels{payload.ClaimLabel})).To(Succeed())
+			Expect(claims.Items).NotTo(BeEmpty())
+			for i := range claims.Items {
+				claims.Items[i].CreationTimestamp = metav1.Now()
+				Expect(k8sClient.Update(ctx, &claims.Items[i])).To(Succeed())
+			}
+		}
+
+		BeforeEach(func() {
+			Expect(payload.Offload(ctx, k8sClient, "default", workloadGroups)).To(Succeed())
+			first = &v1alpha1.Work{
+				ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "default", UID: "first-uid"},
+				Spec:       v1alpha1.WorkSpec{WorkloadGroups: workloadGroups},
+			}
+			Expect(k8sClient.Create(ctx, first)).To(Succeed())
+			Expect(payload.SetOwner(ctx, k8sClient, first)).To(Succeed())
+		})
+
+		It("keeps content shared by two Works when the first is deleted before the second owns it", func() {
+			secondGroups := []v1alpha1.WorkloadGroup{{Workloads: []v1alpha1.Workload{{Filepath: "large.yaml", Content: largeContent}}}}
+			Expect(payload.Offload(ctx, k8sClient, "default", secondGroups)).To(Succeed())
+			claimNow()
+
+			By("deleting the first Work")
+			Expect(payload.Release(ctx, k8sClient, first)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, first)).To(Succeed())
+			Expect(payload.CollectOrphans(ctx, k8sClient, "default")).To(Succeed())
+
+			By("owning the content with the second Work")
+			second := &v1alpha1.Work{
+				ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "default", UID: "second-uid"},
+				Spec:       v1alpha1.WorkSpec{WorkloadGroups: secondGroups},
+			}
+			Expect(k8sClient.Create(ctx, second)).To(Succeed())
+			Expect(payload.SetOwner(ctx, k8sClient, second)).To(Succeed())
+
+			content, err := payload.Resolve(ctx, k8sClient, "default", second.Spec.WorkloadGroups[0].Workloads[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(Equal(largeContent))
+		})
+
+		It("keeps claimed content the first Work stops referencing", func() {
+			Expect(payload.Offload(ctx, k8sClient, "default", []v1alpha1.WorkloadGroup{{Workloads: []v1alpha1.Workload{{Filepath: "large.yaml", Content: largeContent}}}})).To(Succeed())
+			claimNow()
+
+			first.Spec.WorkloadGroups = []v1alpha1.WorkloadGroup{{Workloads: []v1alpha1.Workload{{Filepath: "small.yaml", Content: "small"}}}}
+			Expect(payload.SetOwner(ctx, k8sClient, first)).To(Succeed())
+
+			configMaps := &corev1.ConfigMapList{}
+			Expect(k8sClient.List(ctx, configMaps, client.HasLabels{payload.DigestLabel})).To(Succeed())
+			Expect(configMaps.Items).To(HaveLen(2))
+		})
+
+		It("deletes the content once the claim lapses without a Work owning it", func() {
+			Expect(payload.Offload(ctx, k8sClient, "default", []v1alpha1.WorkloadGroup{{Workloads: []v1alpha1.Workload{{Filepath: "large.yaml", Content: largeContent}}}})).To(Succeed())
+			Expect(payload.Release(ctx, k8sClient, first)).To(Succeed())
+
+			Expect(payload.CollectOrphans(ctx, k8sClient, "default")).To(Succeed())
+
+			configMaps := &corev1.ConfigMapList{}
+			Expect(k8sClient.List(ctx, configMaps)).To(Succeed())
+			Expect(configMaps.Items).To(BeEmpty())
+		})
+	})
+
+	Describe("CollectOrphans", func() {
+		It("deletes the ConfigMaps no Work has owned for longer than the grace period", func() {
+			Expect(payload.Offload(ctx, k8sClient, "default", workloadGroups)).To(Succeed())
+			configMaps := &corev1.ConfigMapList{}
+			Expect(k8sClient.List(ctx, configMaps)).To(Succeed())
+			Expect(configMaps.Items).To(HaveLen(2))
+
+			recent := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "recent",
+					Namespace:         "default",
+					Labels:            map[string]string{payload.DigestLabel: "recent"},
+					CreationTimestamp: metav1.Now(),
+				},
+			}
+			Expect(k8sClient.Create(ctx, recent)).To(Succeed())
+			unrelated := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "default"},
+			}
+			Expect(k8sClient.Create(ctx, unrelated)).To(Succeed())
+
+			Expect(payload.CollectOrphans(ctx, k8sClient, "default")).To(Succeed())
+
+			Expect(k8sClient.List(ctx, configMaps)).To(Succeed())
+			Expect(configMaps.Items).To(ConsistOf(
+				HaveField("ObjectMeta.Name", "recent"),
+				HaveField("ObjectMeta.Name", "unrelated"),
+			))
+		})
+
+		It("keeps the ConfigMaps owned by a Work", func() {
+			Expect(payload.Offload(ctx, k8sClient, "default", workloadGroups)).To(Succeed())
+			work := &v1alpha1.Work{
+				ObjectMeta: metav1.ObjectMeta{Name: "work", Namespace: "default", UID: "work-uid"},
+				Spec:       v1alpha1.WorkSpec{WorkloadGroups: workloadGroups},
+			}
+			Expect(k8sClient.Create(ctx, work)).To(Succeed())
+			Expect(payload.SetOwner(ctx, k8sClient, work)).To(Succeed())
+
+			Expect(payload.CollectOrphans(ctx, k8sClient, "default")).To(Succeed())
+
+			configMaps := &corev1.ConfigMapList{}
+			Expect(k8sClient.List(ctx, configMaps)).To(Succeed())
+			Expect(configMaps.Items).To(HaveLen(2))
+		})
+	})
+})
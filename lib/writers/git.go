@@ -235,6 +235,63 @@ func (g *GitWriter) ReadFile(filePath string) ([]byte, error) {
 	return content, nil
 }
 
+func (g *GitWriter) ReadFiles(paths []string) (map[string][]byte, error) {
+	logger := g.Log.WithValues(
+		"Path", g.Path,
+		"branch", g.GitServer.Branch,
+	)
+
+	localTmpDir, _, _, err := g.setupLocalDirectoryWithRepo(logger)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(localTmpDir)
+
+	root := filepath.Join(localTmpDir, g.Path)
+	files := map[string][]byte{}
+	for _, path := range paths {
+		if !strings.HasSuffix(path, "/") {
+			content, err := os.ReadFile(filepath.Join(root, path))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				logger.Error(err, "could not read file", "file", path)
+				return nil, err
+			}
+			files[path] = content
+			continue
+		}
+
+		err := filepath.WalkDir(filepath.Join(root, path), func(fullPath string, entry os.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return filepath.SkipDir
+				}
+				return err
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			content, err := os.ReadFile(fullPath)
+			if err != nil {
+				return err
+			}
+			relativePath, err := filepath.Rel(root, fullPath)
+			if err != nil {
+				return err
+			}
+			files[relativePath] = content
+			return nil
+		})
+		if err != nil {
+			logger.Error(err, "could not read directory", "dir", path)
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
 func (g *GitWriter) setupLocalDirectoryWithRepo(logger logr.Logger) (string, *git.Repository, *git.Worktree, error) {
 	localTmpDir, err := createLocalDirectory(logger)
 	if err != nil {
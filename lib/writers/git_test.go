@@ -7,7 +7,13 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"log"
+	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
 	"github.com/go-logr/logr"
@@ -134,3 +140,81 @@ var _ = Describe("NewGitWriter", func() {
 		})
 	})
 })
+
+var _ = Describe("GitWriter", func() {
+	Describe("ReadFiles", func() {
+		var writer writers.StateStoreWriter
+
+		BeforeEach(func() {
+			repoDir := GinkgoT().TempDir()
+			_, err := git.PlainInit(repoDir, true)
+			Expect(err).NotTo(HaveOccurred())
+
+			workDir := GinkgoT().TempDir()
+			repo, err := git.PlainInit(workDir, false)
+			Expect(err).NotTo(HaveOccurred())
+			files := map[string]string{
+				"store/dst/test/resources/a.yaml":        "a",
+				"store/dst/test/resources/nested/b.yaml": "b",
+				"store/dst/other/resources/c.yaml":       "c",
+			}
+			for path, content := range files {
+				Expect(os.MkdirAll(filepath.Dir(filepath.Join(workDir, path)), 0700)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workDir, path), []byte(content), 0600)).To(Succeed())
+			}
+			worktree, err := repo.Worktree()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(worktree.AddGlob(".")).To(Succeed())
+			_, err = worktree.Commit("add files", &git.CommitOptions{
+				Author: &object.Signature{Name: "a-user", Email: "test@example.com", When: time.Now()},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{repoDir}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repo.Push(&git.PushOptions{
+				RemoteName: "origin",
+				RefSpecs:   []config.RefSpec{"refs/heads/master:refs/heads/main"},
+			})).To(Succeed())
+
+			dest := v1alpha1.Destination{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+			dest.Spec.Path = "dst"
+			stateStoreSpec := v1alpha1.GitStateStoreSpec{
+				URL:    repoDir,
+				Branch: "main",
+				StateStoreCoreFields: v1alpha1.StateStoreCoreFields{
+					Path: "store",
+				},
+			}
+			writer, err = writers.NewGitWriter(ctrl.Log.WithName("setup"), stateStoreSpec, dest, nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reads a file relative to the writer path", func() {
+			files, err := writer.ReadFiles([]string{"resources/a.yaml"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(Equal(map[string][]byte{"resources/a.yaml": []byte("a")}))
+		})
+
+		It("skips a missing file", func() {
+			files, err := writer.ReadFiles([]string{"resources/a.yaml", "resources/missing.yaml"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(HaveLen(1))
+			Expect(files).To(HaveKey("resources/a.yaml"))
+		})
+
+		It("reads a directory recursively when the path ends with a slash", func() {
+			files, err := writer.ReadFiles([]string{"resources/"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(Equal(map[string][]byte{
+				"resources/a.yaml":        []byte("a"),
+				"resources/nested/b.yaml": []byte("b"),
+			}))
+		})
+
+		It("skips a missing directory", func() {
+			files, err := writer.ReadFiles([]string{"missing/", "resources/a.yaml"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(Equal(map[string][]byte{"resources/a.yaml": []byte("a")}))
+		})
+	})
+})
@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/md5"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -95,6 +96,42 @@ func (b *S3Writer) ReadFile(filename string) ([]byte, error) {
 
 }
 
+func (b *S3Writer) ReadFiles(paths []string) (map[string][]byte, error) {
+	ctx := context.Background()
+	files := map[string][]byte{}
+	for _, path := range paths {
+		if !strings.HasSuffix(path, "/") {
+			content, err := b.ReadFile(path)
+			if errors.Is(err, FileNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			files[path] = content
+			continue
+		}
+
+		objectCh := b.RepoClient.ListObjects(ctx, b.BucketName, minio.ListObjectsOptions{Prefix: filepath.Join(b.path, path) + "/", Recursive: true})
+		for object := range objectCh {
+			if object.Err != nil {
+				b.Log.Error(object.Err, "Listing objects", "dir", path)
+				return nil, object.Err
+			}
+			relativePath := strings.TrimPrefix(object.Key, b.path+"/")
+			content, err := b.ReadFile(relativePath)
+			if errors.Is(err, FileNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			files[relativePath] = content
+		}
+	}
+	return files, nil
+}
+
 func (b *S3Writer) UpdateFiles(subDir string, _ string, workloadsToCreate []v1alpha1.Workload, workloadsToDelete []string) (string, error) {
 	return b.update(subDir, workloadsToCreate, workloadsToDelete)
 }
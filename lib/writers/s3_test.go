@@ -1,6 +1,14 @@
 package writers_test
 
 import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -116,4 +124,126 @@ var _ = Describe("S3", func() {
 
 	})
 
+	Describe("ReadFiles", func() {
+		var (
+			server *httptest.Server
+			writer writers.StateStoreWriter
+		)
+
+		BeforeEach(func() {
+			server = httptest.NewServer(fakeBucket("kratix", map[string]string{
+				"store/dst/test/resources/a.yaml":        "a",
+				"store/dst/test/resources/nested/b.yaml": "b",
+				"store/dst/other/resources/c.yaml":       "c",
+			}))
+
+			dest := v1alpha1.Destination{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+			dest.Spec.Path = "dst"
+			stateStoreSpec := v1alpha1.BucketStateStoreSpec{
+				Endpoint:   strings.TrimPrefix(server.URL, "http://"),
+				Insecure:   true,
+				BucketName: "kratix",
+				AuthMethod: "accessKey",
+			}
+			stateStoreSpec.Path = "store"
+			creds := map[string][]byte{
+				"accessKeyID":     []byte("accessKeyID"),
+				"secretAccessKey": []byte("secretAccessKey"),
+			}
+			var err error
+			writer, err = writers.NewS3Writer(ctrl.Log.WithName("setup"), stateStoreSpec, dest, creds)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("reads a file relative to the writer path", func() {
+			files, err := writer.ReadFiles([]string{"resources/a.yaml"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(Equal(map[string][]byte{"resources/a.yaml": []byte("a")}))
+		})
+
+		It("skips a missing file", func() {
+			files, err := writer.ReadFiles([]string{"resources/a.yaml", "resources/missing.yaml"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(Equal(map[string][]byte{"resources/a.yaml": []byte("a")}))
+		})
+
+		It("reads a directory recursively when the path ends with a slash", func() {
+			files, err := writer.ReadFiles([]string{"resources/"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(Equal(map[string][]byte{
+				"resources/a.yaml":        []byte("a"),
+				"resources/nested/b.yaml": []byte("b"),
+			}))
+		})
+
+		It("skips a missing directory", func() {
+			files, err := writer.ReadFiles([]string{"missing/", "resources/a.yaml"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(Equal(map[string][]byte{"resources/a.yaml": []byte("a")}))
+		})
+	})
 })
+
+type listBucketResult struct {
+	XMLName     xml.Name       `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+	Name        string         `xml:"Name"`
+	Prefix      string         `xml:"Prefix"`
+	KeyCount    int            `xml:"KeyCount"`
+	IsTruncated bool           `xml:"IsTruncated"`
+	Contents    []bucketObject `xml:"Contents"`
+}
+
+type bucketObject struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int    `xml:"Size"`
+}
+
+// Serves the objects of a single bucket, answering the requests S3Writer
+// makes to read them
+func fakeBucket(bucketName string, objects map[string]string) http.Handler {
+	lastModified := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/"+bucketName), "/")
+		query := r.URL.Query()
+		switch {
+		case query.Has("location"):
+			fmt.Fprint(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`)
+		case key == "":
+			result := listBucketResult{Name: bucketName, Prefix: query.Get("prefix")}
+			for objectKey, content := range objects {
+				if strings.HasPrefix(objectKey, result.Prefix) {
+					result.Contents = append(result.Contents, bucketObject{
+						Key:          objectKey,
+						LastModified: lastModified.Format(time.RFC3339),
+						ETag:         `"etag"`,
+						Size:         len(content),
+					})
+				}
+			}
+			sort.Slice(result.Contents, func(i, j int) bool { return result.Contents[i].Key < result.Contents[j].Key })
+			result.KeyCount = len(result.Contents)
+			Expect(xml.NewEncoder(w).Encode(result)).To(Succeed())
+		default:
+			content, found := objects[key]
+			if !found {
+				w.WriteHeader(http.StatusNotFound)
+				if r.Method != http.MethodHead {
+					fmt.Fprintf(w, `<Error><Code>NoSuchKey</Code><Key>%s</Key></Error>`, key)
+				}
+				return
+			}
+			w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+			w.Header().Set("ETag", `"etag"`)
+			w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+			if r.Method != http.MethodHead {
+				fmt.Fprint(w, content)
+			}
+		}
+	})
+}
@@ -10,6 +10,11 @@ import (
 type StateStoreWriter interface {
 	UpdateFiles(subDir string, workPlacementName string, workloadsToCreate []v1alpha1.Workload, workloadsToDelete []string) (string, error)
 	ReadFile(filename string) ([]byte, error)
+	// ReadFiles reads several files in one go, keyed by the path they were
+	// requested with. A path ending in "/" reads every file beneath that
+	// directory, keyed by its path within the State Store. Files that do not
+	// exist are left out of the result.
+	ReadFiles(paths []string) (map[string][]byte, error)
 }
 
 var FileNotFound = fmt.Errorf("file not found")
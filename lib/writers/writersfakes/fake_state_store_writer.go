@@ -22,6 +22,19 @@ type FakeStateStoreWriter struct {
 		result1 []byte
 		result2 error
 	}
+	ReadFilesStub        func([]string) (map[string][]byte, error)
+	readFilesMutex       sync.RWMutex
+	readFilesArgsForCall []struct {
+		arg1 []string
+	}
+	readFilesReturns struct {
+		result1 map[string][]byte
+		result2 error
+	}
+	readFilesReturnsOnCall map[int]struct {
+		result1 map[string][]byte
+		result2 error
+	}
 	UpdateFilesStub        func(string, string, []v1alpha1.Workload, []string) (string, error)
 	updateFilesMutex       sync.RWMutex
 	updateFilesArgsForCall []struct {
@@ -106,6 +119,75 @@ func (fake *FakeStateStoreWriter) ReadFileReturnsOnCall(i int, result1 []byte, r
 	}{result1, result2}
 }
 
+func (fake *FakeStateStoreWriter) ReadFiles(arg1 []string) (map[string][]byte, error) {
+	var arg1Copy []string
+	if arg1 != nil {
+		arg1Copy = make([]string, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.readFilesMutex.Lock()
+	ret, specificReturn := fake.readFilesReturnsOnCall[len(fake.readFilesArgsForCall)]
+	fake.readFilesArgsForCall = append(fake.readFilesArgsForCall, struct {
+		arg1 []string
+	}{arg1Copy})
+	stub := fake.ReadFilesStub
+	fakeReturns := fake.readFilesReturns
+	fake.recordInvocation("ReadFiles", []interface{}{arg1Copy})
+	fake.readFilesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStateStoreWriter) ReadFilesCallCount() int {
+	fake.readFilesMutex.RLock()
+	defer fake.readFilesMutex.RUnlock()
+	return len(fake.readFilesArgsForCall)
+}
+
+func (fake *FakeStateStoreWriter) ReadFilesCalls(stub func([]string) (map[string][]byte, error)) {
+	fake.readFilesMutex.Lock()
+	defer fake.readFilesMutex.Unlock()
+	fake.ReadFilesStub = stub
+}
+
+func (fake *FakeStateStoreWriter) ReadFilesArgsForCall(i int) []string {
+	fake.readFilesMutex.RLock()
+	defer fake.readFilesMutex.RUnlock()
+	argsForCall := fake.readFilesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStateStoreWriter) ReadFilesReturns(result1 map[string][]byte, result2 error) {
+	fake.readFilesMutex.Lock()
+	defer fake.readFilesMutex.Unlock()
+	fake.ReadFilesStub = nil
+	fake.readFilesReturns = struct {
+		result1 map[string][]byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStateStoreWriter) ReadFilesReturnsOnCall(i int, result1 map[string][]byte, result2 error) {
+	fake.readFilesMutex.Lock()
+	defer fake.readFilesMutex.Unlock()
+	fake.ReadFilesStub = nil
+	if fake.readFilesReturnsOnCall == nil {
+		fake.readFilesReturnsOnCall = make(map[int]struct {
+			result1 map[string][]byte
+			result2 error
+		})
+	}
+	fake.readFilesReturnsOnCall[i] = struct {
+		result1 map[string][]byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeStateStoreWriter) UpdateFiles(arg1 string, arg2 string, arg3 []v1alpha1.Workload, arg4 []string) (string, error) {
 	var arg3Copy []v1alpha1.Workload
 	if arg3 != nil {
@@ -188,6 +270,8 @@ func (fake *FakeStateStoreWriter) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.readFileMutex.RLock()
 	defer fake.readFileMutex.RUnlock()
+	fake.readFilesMutex.RLock()
+	defer fake.readFilesMutex.RUnlock()
 	fake.updateFilesMutex.RLock()
 	defer fake.updateFilesMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
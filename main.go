@@ -48,6 +48,7 @@ import (
 	"github.com/syntasso/kratix/api/v1alpha1"
 	platformv1alpha1 "github.com/syntasso/kratix/api/v1alpha1"
 	"github.com/syntasso/kratix/controllers"
+	"github.com/syntasso/kratix/lib/compression"
 	"github.com/syntasso/kratix/lib/fetchers"
 	//+kubebuilder:scaffold:imports
 )
@@ -60,8 +61,23 @@ func init() {
 }
 
 type KratixConfig struct {
-	Workflows          Workflows `json:"workflows"`
-	NumberOfJobsToKeep int       `json:"numberOfJobsToKeep,omitempty"`
+	Workflows          Workflows  `json:"workflows"`
+	NumberOfJobsToKeep int        `json:"numberOfJobsToKeep,omitempty"`
+	Scheduling         Scheduling `json:"scheduling,omitempty"`
+	Workloads          Workloads  `json:"workloads,omitempty"`
+}
+
+type Workloads struct {
+	// Codec used to compress the content of workloads; gzip or zstd
+	Codec string `json:"codec,omitempty"`
+	// Store identical workload content once per namespace, referenced by its
+	// digest
+	Deduplicate bool `json:"deduplicate,omitempty"`
+}
+
+type Scheduling struct {
+	// Strategy used for Promises that don't set spec.schedulingPolicy.strategy
+	Strategy string `json:"strategy,omitempty"`
 }
 
 type Workflows struct {
@@ -108,6 +124,11 @@ func main() {
 
 	if kratixConfig != nil {
 		v1alpha1.DefaultUserProvidedContainersSecurityContext = &kratixConfig.Workflows.DefaultContainerSecurityContext
+		v1alpha1.WorkloadContentCodec = getWorkloadContentCodec(kratixConfig)
+		// the controllers compress the workloads of static dependencies
+		// themselves, so they use the configured codec too
+		compression.DefaultCodec = compression.Codec(v1alpha1.WorkloadContentCodec)
+		v1alpha1.DeduplicateWorkloadContent = kratixConfig.Workloads.Deduplicate
 	}
 
 	for {
@@ -138,6 +159,7 @@ func main() {
 			Client: mgr.GetClient(),
 			Log:    ctrl.Log.WithName("controllers").WithName("Scheduler"),
 		}
+		scheduler.DefaultSchedulingStrategy = getDefaultSchedulingStrategy(kratixConfig, &scheduler)
 
 		restartManager := false
 		restartManagerInProgress := false
@@ -185,10 +207,16 @@ func main() {
 			setupLog.Error(err, "unable to create controller", "controller", "Destination")
 			os.Exit(1)
 		}
+		if err = (&controllers.DestinationGroupReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("DestinationGroupController"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "DestinationGroup")
+			os.Exit(1)
+		}
 		if err = (&controllers.WorkPlacementReconciler{
-			Client:       mgr.GetClient(),
-			Log:          ctrl.Log.WithName("controllers").WithName("WorkPlacementController"),
-			VersionCache: make(map[string]string),
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("WorkPlacementController"),
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "WorkPlacement")
 			os.Exit(1)
@@ -211,6 +239,18 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "PromiseRelease")
 			os.Exit(1)
 		}
+		if err = kratixWebhook.SetupWorkPlacementWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "WorkPlacement")
+			os.Exit(1)
+		}
+		if err = kratixWebhook.SetupApprovalWebhookWithManager(mgr, mgr.GetClient()); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Approval")
+			os.Exit(1)
+		}
+		if err = kratixWebhook.SetupDestinationWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Destination")
+			os.Exit(1)
+		}
 		//+kubebuilder:scaffold:builder
 
 		if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -283,3 +323,27 @@ func getNumJobsToKeep(kratixConfig *KratixConfig) int {
 	}
 	return kratixConfig.NumberOfJobsToKeep
 }
+
+func getDefaultSchedulingStrategy(kratixConfig *KratixConfig, scheduler *controllers.Scheduler) string {
+	if kratixConfig == nil || kratixConfig.Scheduling.Strategy == "" {
+		return v1alpha1.SchedulingStrategyRandom
+	}
+	if scheduler.HasSchedulingStrategy(kratixConfig.Scheduling.Strategy) {
+		return kratixConfig.Scheduling.Strategy
+	}
+	setupLog.Error(fmt.Errorf("invalid Kratix Config"),
+		"unknown scheduling strategy; set to default value",
+		"strategy", kratixConfig.Scheduling.Strategy)
+	return v1alpha1.SchedulingStrategyRandom
+}
+
+func getWorkloadContentCodec(kratixConfig *KratixConfig) string {
+	codec, err := compression.ParseCodec(kratixConfig.Workloads.Codec)
+	if err != nil {
+		setupLog.Error(fmt.Errorf("invalid Kratix Config"),
+			"unknown workload codec; set to default value",
+			"codec", kratixConfig.Workloads.Codec)
+		return string(compression.DefaultCodec)
+	}
+	return string(codec)
+}
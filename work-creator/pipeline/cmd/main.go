@@ -1,11 +1,13 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 
 	"github.com/syntasso/kratix/api/v1alpha1"
+	"github.com/syntasso/kratix/lib/compression"
 	"github.com/syntasso/kratix/work-creator/pipeline"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -61,6 +63,21 @@ func main() {
 		K8sClient: k8sClient,
 	}
 
+	if schedulingJSON := os.Getenv(v1alpha1.ResourceRequestDestinationSelectorsEnvVar); schedulingJSON != "" {
+		workCreator.ResourceRequestScheduling = &v1alpha1.WorkloadGroupScheduling{}
+		if err := json.Unmarshal([]byte(schedulingJSON), workCreator.ResourceRequestScheduling); err != nil {
+			fmt.Printf("Error parsing %s: %s\n", v1alpha1.ResourceRequestDestinationSelectorsEnvVar, err)
+			os.Exit(1)
+		}
+	}
+
+	workCreator.Codec, err = compression.ParseCodec(os.Getenv(v1alpha1.WorkloadContentCodecEnvVar))
+	if err != nil {
+		fmt.Printf("Error parsing %s: %s\n", v1alpha1.WorkloadContentCodecEnvVar, err)
+		os.Exit(1)
+	}
+	workCreator.Deduplicate = os.Getenv(v1alpha1.DeduplicateWorkloadContentEnvVar) == "true"
+
 	err = workCreator.Execute(inputDirectory, promiseName, namespace, resourceName, workflowType, pipelineName)
 	if err != nil {
 		fmt.Println(err.Error())
@@ -19,6 +19,7 @@ import (
 	"github.com/syntasso/kratix/api/v1alpha1"
 	"github.com/syntasso/kratix/lib/compression"
 	"github.com/syntasso/kratix/lib/hash"
+	"github.com/syntasso/kratix/lib/payload"
 	"github.com/syntasso/kratix/lib/resourceutil"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -26,6 +27,14 @@ import (
 
 type WorkCreator struct {
 	K8sClient client.Client
+	// The scheduling a resource request sets with its
+	// kratix.io/destination-selectors annotation, if its Promise allows it
+	ResourceRequestScheduling *v1alpha1.WorkloadGroupScheduling
+	// The codec used to compress the workloads; DefaultCodec if empty
+	Codec compression.Codec
+	// Store the content of the workloads once per namespace, referenced by
+	// its digest, rather than inline in the Work
+	Deduplicate bool
 }
 
 func (w *WorkCreator) Execute(rootDirectory, promiseName, namespace, resourceName, workflowType, pipelineName string) error {
@@ -52,7 +61,7 @@ func (w *WorkCreator) Execute(rootDirectory, promiseName, namespace, resourceNam
 
 	var workloadGroups []v1alpha1.WorkloadGroup
 	var directoriesToIgnoreForTheBaseScheduling []string
-	var defaultDestinationSelectors map[string]string
+	var defaultDestinationSelectors *v1alpha1.WorkflowDestinationSelectors
 	pipelineOutputDir := filepath.Join(rootDirectory, "input")
 	for _, workflowDestinationSelector := range workflowScheduling {
 		directory := workflowDestinationSelector.Directory
@@ -71,13 +80,18 @@ func (w *WorkCreator) Execute(rootDirectory, promiseName, namespace, resourceNam
 				ID:        fmt.Sprintf("%x", md5.Sum([]byte(directory))),
 				DestinationSelectors: []v1alpha1.WorkloadGroupScheduling{
 					{
-						MatchLabels: workflowDestinationSelector.MatchLabels,
-						Source:      workflowType + "-" + "workflow",
+						MatchLabels:          workflowDestinationSelector.GetMatchLabels(),
+						MatchExpressions:     workflowDestinationSelector.MatchExpressions,
+						Tolerations:          workflowDestinationSelector.Tolerations,
+						Preferred:            workflowDestinationSelector.Preferred,
+						ResourceAffinity:     workflowDestinationSelector.ResourceAffinity,
+						ResourceAntiAffinity: workflowDestinationSelector.ResourceAntiAffinity,
+						Source:               workflowType + "-" + "workflow",
 					},
 				},
 			})
 		} else {
-			defaultDestinationSelectors = workflowDestinationSelector.MatchLabels
+			defaultDestinationSelectors = &workflowDestinationSelector
 		}
 	}
 
@@ -93,11 +107,17 @@ func (w *WorkCreator) Execute(rootDirectory, promiseName, namespace, resourceNam
 			ID:        hash.ComputeHash(v1alpha1.DefaultWorkloadGroupDirectory),
 		}
 
-		if defaultDestinationSelectors != nil {
+		if defaultDestinationSelectors != nil && (defaultDestinationSelectors.MatchLabels != nil || defaultDestinationSelectors.MatchExpressions != nil || defaultDestinationSelectors.Tolerations != nil || defaultDestinationSelectors.Preferred != nil ||
+			defaultDestinationSelectors.ResourceAffinity != nil || defaultDestinationSelectors.ResourceAntiAffinity != nil || defaultDestinationSelectors.DestinationGroup != "") {
 			defaultWorkloadGroup.DestinationSelectors = []v1alpha1.WorkloadGroupScheduling{
 				{
-					MatchLabels: defaultDestinationSelectors,
-					Source:      workflowType + "-" + "workflow",
+					MatchLabels:          defaultDestinationSelectors.GetMatchLabels(),
+					MatchExpressions:     defaultDestinationSelectors.MatchExpressions,
+					Tolerations:          defaultDestinationSelectors.Tolerations,
+					Preferred:            defaultDestinationSelectors.Preferred,
+					ResourceAffinity:     defaultDestinationSelectors.ResourceAffinity,
+					ResourceAntiAffinity: defaultDestinationSelectors.ResourceAntiAffinity,
+					Source:               workflowType + "-" + "workflow",
 				},
 			}
 		}
@@ -113,28 +133,50 @@ func (w *WorkCreator) Execute(rootDirectory, promiseName, namespace, resourceNam
 				switch selector.Source {
 				case "promise":
 					p = append(p, v1alpha1.PromiseScheduling{
-						MatchLabels: selector.MatchLabels,
+						MatchLabels:          selector.MatchLabels,
+						MatchExpressions:     selector.MatchExpressions,
+						Tolerations:          selector.Tolerations,
+						Preferred:            selector.Preferred,
+						ResourceAffinity:     selector.ResourceAffinity,
+						ResourceAntiAffinity: selector.ResourceAntiAffinity,
 					})
 				case "promise-workflow":
 					pw = append(pw, v1alpha1.PromiseScheduling{
-						MatchLabels: selector.MatchLabels,
+						MatchLabels:          selector.MatchLabels,
+						MatchExpressions:     selector.MatchExpressions,
+						Tolerations:          selector.Tolerations,
+						Preferred:            selector.Preferred,
+						ResourceAffinity:     selector.ResourceAffinity,
+						ResourceAntiAffinity: selector.ResourceAntiAffinity,
 					})
 				}
 			}
 
 			if len(pw) > 0 {
+				affinity, antiAffinity := v1alpha1.SquashPromiseSchedulingResourceAffinity(pw)
 				defaultWorkloadGroup.DestinationSelectors = append(defaultWorkloadGroup.DestinationSelectors, v1alpha1.WorkloadGroupScheduling{
-					MatchLabels: v1alpha1.SquashPromiseScheduling(pw),
-					Source:      "promise-workflow",
+					MatchLabels:          v1alpha1.SquashPromiseScheduling(pw),
+					MatchExpressions:     v1alpha1.SquashPromiseSchedulingExpressions(pw),
+					Tolerations:          v1alpha1.SquashPromiseSchedulingTolerations(pw),
+					Preferred:            v1alpha1.SquashPromiseSchedulingPreferences(pw),
+					ResourceAffinity:     affinity,
+					ResourceAntiAffinity: antiAffinity,
+					Source:               "promise-workflow",
 				})
 			}
 
 			if len(p) > 0 {
+				affinity, antiAffinity := v1alpha1.SquashPromiseSchedulingResourceAffinity(p)
 				defaultWorkloadGroup.DestinationSelectors = append(
 					defaultWorkloadGroup.DestinationSelectors,
 					v1alpha1.WorkloadGroupScheduling{
-						MatchLabels: v1alpha1.SquashPromiseScheduling(p),
-						Source:      "promise",
+						MatchLabels:          v1alpha1.SquashPromiseScheduling(p),
+						MatchExpressions:     v1alpha1.SquashPromiseSchedulingExpressions(p),
+						Tolerations:          v1alpha1.SquashPromiseSchedulingTolerations(p),
+						Preferred:            v1alpha1.SquashPromiseSchedulingPreferences(p),
+						ResourceAffinity:     affinity,
+						ResourceAntiAffinity: antiAffinity,
+						Source:               "promise",
 					},
 				)
 			}
@@ -158,6 +200,18 @@ func (w *WorkCreator) Execute(rootDirectory, promiseName, namespace, resourceNam
 		work.Spec.ResourceName = ""
 		work.Labels = v1alpha1.GenerateSharedLabelsForPromise(promiseName)
 		resourceutil.SetPromiseWorkLabels(work.Labels, promiseName, pipelineName)
+	} else {
+		work.SetResourceRequestScheduling(w.ResourceRequestScheduling)
+	}
+
+	if w.Deduplicate {
+		if err := payload.Deduplicate(context.Background(), w.K8sClient, work.Namespace, work.Spec.WorkloadGroups); err != nil {
+			return err
+		}
+	}
+
+	if err := payload.Offload(context.Background(), w.K8sClient, work.Namespace, work.Spec.WorkloadGroups); err != nil {
+		return err
 	}
 
 	var currentWork *v1alpha1.Work
@@ -232,7 +286,11 @@ func (w *WorkCreator) getWorkloadsFromDir(prefixToTrimFromWorkloadFilepath, root
 				return nil, err
 			}
 
-			content, err := compression.CompressContent(byteValue)
+			codec := w.Codec
+			if codec == "" {
+				codec = compression.DefaultCodec
+			}
+			content, err := compression.EncodeContent(byteValue, codec)
 			if err != nil {
 				return nil, err
 			}
@@ -302,6 +360,21 @@ func getSelectorsFromFile(file string) ([]v1alpha1.WorkflowDestinationSelectors,
 		return nil, fmt.Errorf("invalid directory in destination-selectors.yaml: %s, directory must be top-level", path)
 	}
 
+	for _, selector := range schedulingConfig {
+		if err := v1alpha1.ValidateMatchExpressions(selector.MatchExpressions); err != nil {
+			return nil, fmt.Errorf("invalid matchExpressions in destination-selectors.yaml for directory %s: %w", selector.Directory, err)
+		}
+		if err := v1alpha1.ValidatePreferredDestinationSelectors(selector.Preferred); err != nil {
+			return nil, fmt.Errorf("invalid preferred selectors in destination-selectors.yaml for directory %s: %w", selector.Directory, err)
+		}
+		if err := v1alpha1.ValidateResourceAffinityTerms(selector.ResourceAffinity); err != nil {
+			return nil, fmt.Errorf("invalid resourceAffinity in destination-selectors.yaml for directory %s: resourceAffinity%w", selector.Directory, err)
+		}
+		if err := v1alpha1.ValidateResourceAffinityTerms(selector.ResourceAntiAffinity); err != nil {
+			return nil, fmt.Errorf("invalid resourceAntiAffinity in destination-selectors.yaml for directory %s: resourceAntiAffinity%w", selector.Directory, err)
+		}
+	}
+
 	return schedulingConfig, nil
 }
 
@@ -2,6 +2,7 @@ package pipeline_test
 
 import (
 	"context"
+	"math/rand"
 	"os"
 	"path/filepath"
 
@@ -9,6 +10,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/syntasso/kratix/api/v1alpha1"
 	"github.com/syntasso/kratix/lib/compression"
+	"github.com/syntasso/kratix/lib/payload"
 	"github.com/syntasso/kratix/work-creator/pipeline"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -205,6 +207,171 @@ var _ = Describe("WorkCreator", func() {
 			})
 		})
 
+		When("the resource request sets destination selectors", func() {
+			It("adds them to every workload group with the resource-request source", func() {
+				workCreator.ResourceRequestScheduling = &v1alpha1.WorkloadGroupScheduling{
+					MatchLabels: map[string]string{"region": "eu-west"},
+					Source:      "resource-request",
+				}
+				mockPipelineDirectory := filepath.Join(getRootDirectory(), "complete")
+				err := workCreator.Execute(mockPipelineDirectory, "promise-name", "default", "resource-name", "resource", pipelineName)
+				Expect(err).NotTo(HaveOccurred())
+
+				workResource := getWork(expectedNamespace, promiseName, resourceName, pipelineName)
+				Expect(workResource.Spec.WorkloadGroups).NotTo(BeEmpty())
+				for _, workloadGroup := range workResource.Spec.WorkloadGroups {
+					Expect(workloadGroup.DestinationSelectors).To(ContainElement(*workCreator.ResourceRequestScheduling))
+				}
+			})
+		})
+
+		When("the pipeline outputs more than a Work can hold", func() {
+			It("stores the largest workloads in ConfigMaps", func() {
+				pipelineDirectory := GinkgoT().TempDir()
+				Expect(os.MkdirAll(filepath.Join(pipelineDirectory, "input"), 0700)).To(Succeed())
+				largeContent := make([]byte, 2*payload.MaxInlineContentSize)
+				random := rand.New(rand.NewSource(1))
+				for i := range largeContent {
+					largeContent[i] = byte('a' + random.Intn(26))
+				}
+				Expect(os.WriteFile(filepath.Join(pipelineDirectory, "input", "large.yaml"), largeContent, 0600)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(pipelineDirectory, "input", "small.yaml"), []byte("small"), 0600)).To(Succeed())
+
+				err := workCreator.Execute(pipelineDirectory, "promise-name", "default", "resource-name", "resource", pipelineName)
+				Expect(err).NotTo(HaveOccurred())
+
+				workResource := getWork(expectedNamespace, promiseName, resourceName, pipelineName)
+				workloads := workResource.Spec.WorkloadGroups[0].Workloads
+				Expect(workloads).To(HaveLen(2))
+				Expect(workloads[0].Filepath).To(Equal("large.yaml"))
+				Expect(workloads[0].Content).To(BeEmpty())
+				Expect(workloads[0].ContentRef).NotTo(BeNil())
+				Expect(workloads[1].ContentRef).To(BeNil())
+
+				content, err := payload.Resolve(context.Background(), k8sClient, expectedNamespace, workloads[0])
+				Expect(err).NotTo(HaveOccurred())
+				decompressed, err := compression.DecompressContent([]byte(content))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(decompressed).To(Equal(largeContent))
+
+				configMaps := &v1.ConfigMapList{}
+				Expect(k8sClient.List(context.Background(), configMaps, client.InNamespace(expectedNamespace))).To(Succeed())
+				Expect(configMaps.Items).To(HaveLen(workloads[0].ContentRef.Chunks))
+				for _, configMap := range configMaps.Items {
+					Expect(configMap.GetLabels()).To(HaveKeyWithValue(payload.DigestLabel, workloads[0].ContentRef.Digest[:32]))
+				}
+			})
+		})
+
+		When("a codec and deduplication are configured", func() {
+			It("encodes the workloads with the codec and stores them by digest", func() {
+				pipelineDirectory := GinkgoT().TempDir()
+				Expect(os.MkdirAll(filepath.Join(pipelineDirectory, "input"), 0700)).To(Succeed())
+				content := make([]byte, 4*payload.MinDeduplicatedContentSize)
+				random := rand.New(rand.NewSource(1))
+				for i := range content {
+					content[i] = byte('a' + random.Intn(26))
+				}
+				Expect(os.WriteFile(filepath.Join(pipelineDirectory, "input", "crd.yaml"), content, 0600)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(pipelineDirectory, "input", "small.yaml"), []byte("small"), 0600)).To(Succeed())
+
+				workCreator.Codec = compression.CodecZstd
+				workCreator.Deduplicate = true
+				err := workCreator.Execute(pipelineDirectory, "promise-name", "default", "resource-name", "resource", pipelineName)
+				Expect(err).NotTo(HaveOccurred())
+
+				workResource := getWork(expectedNamespace, promiseName, resourceName, pipelineName)
+				workloads := workResource.Spec.WorkloadGroups[0].Workloads
+				Expect(workloads).To(HaveLen(2))
+				Expect(workloads[0].ContentRef).NotTo(BeNil())
+				Expect(workloads[1].ContentRef).To(BeNil())
+				Expect(compression.ContentCodec([]byte(workloads[1].Content))).To(Equal(compression.CodecZstd))
+
+				encoded, err := payload.Resolve(context.Background(), k8sClient, expectedNamespace, workloads[0])
+				Expect(err).NotTo(HaveOccurred())
+				Expect(compression.ContentCodec([]byte(encoded))).To(Equal(compression.CodecZstd))
+				decompressed, err := compression.DecompressContent([]byte(encoded))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(decompressed).To(Equal(content))
+			})
+		})
+
+		When("the destination-selectors contain matchExpressions", func() {
+			It("includes the expressions of each source in the Work", func() {
+				mockPipelineDirectory := filepath.Join(getRootDirectory(), "destination-selectors-with-match-expressions")
+				err := workCreator.Execute(mockPipelineDirectory, "promise-name", "default", "resource-name", "resource", pipelineName)
+				Expect(err).NotTo(HaveOccurred())
+
+				workResource := getWork(expectedNamespace, promiseName, resourceName, pipelineName)
+				Expect(workResource.Spec.WorkloadGroups).To(HaveLen(1))
+				Expect(workResource.Spec.WorkloadGroups[0].DestinationSelectors).To(ConsistOf(
+					v1alpha1.WorkloadGroupScheduling{
+						MatchLabels: map[string]string{"environment": "production"},
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{Key: "region", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"eu-west"}},
+						},
+						Source: "resource-workflow",
+					},
+					v1alpha1.WorkloadGroupScheduling{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"gold", "silver"}},
+						},
+						Source: "promise",
+					},
+					v1alpha1.WorkloadGroupScheduling{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{Key: "gpu", Operator: metav1.LabelSelectorOpExists},
+						},
+						Source: "promise-workflow",
+					},
+				))
+			})
+
+			When("the expressions are invalid", func() {
+				It("errors", func() {
+					mockPipelineDirectory := filepath.Join(getRootDirectory(), "destination-selectors-with-invalid-match-expressions")
+					err := workCreator.Execute(mockPipelineDirectory, "promise-name", "default", "resource-name", "resource", pipelineName)
+					Expect(err).To(MatchError(ContainSubstring("invalid matchExpressions in destination-selectors.yaml for directory .")))
+				})
+			})
+		})
+
+		When("the destination-selectors contain preferred selectors", func() {
+			It("includes the preferred selectors of each source in the Work", func() {
+				mockPipelineDirectory := filepath.Join(getRootDirectory(), "destination-selectors-with-preferred")
+				err := workCreator.Execute(mockPipelineDirectory, "promise-name", "default", "resource-name", "resource", pipelineName)
+				Expect(err).NotTo(HaveOccurred())
+
+				workResource := getWork(expectedNamespace, promiseName, resourceName, pipelineName)
+				Expect(workResource.Spec.WorkloadGroups).To(HaveLen(1))
+				Expect(workResource.Spec.WorkloadGroups[0].DestinationSelectors).To(ConsistOf(
+					v1alpha1.WorkloadGroupScheduling{
+						MatchLabels: map[string]string{"environment": "production"},
+						Preferred: []v1alpha1.PreferredDestinationSelector{
+							{Weight: 50, MatchLabels: map[string]string{"region": "eu-west"}},
+						},
+						Source: "resource-workflow",
+					},
+					v1alpha1.WorkloadGroupScheduling{
+						Preferred: []v1alpha1.PreferredDestinationSelector{
+							{Weight: 10, MatchExpressions: []metav1.LabelSelectorRequirement{
+								{Key: "disk", Operator: metav1.LabelSelectorOpIn, Values: []string{"ssd"}},
+							}},
+						},
+						Source: "promise",
+					},
+				))
+			})
+
+			When("a weight is out of range", func() {
+				It("errors", func() {
+					mockPipelineDirectory := filepath.Join(getRootDirectory(), "destination-selectors-with-invalid-preferred")
+					err := workCreator.Execute(mockPipelineDirectory, "promise-name", "default", "resource-name", "resource", pipelineName)
+					Expect(err).To(MatchError(ContainSubstring("invalid preferred selectors in destination-selectors.yaml for directory .: preferred[0]: weight must be between 1 and 100")))
+				})
+			})
+		})
+
 		Context("with empty metadata directory", func() {
 			BeforeEach(func() {
 				err := workCreator.Execute(filepath.Join(getRootDirectory(), "empty-metadata"), "promise-name", "default", "resource-name", "resource", pipelineName)